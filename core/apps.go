@@ -0,0 +1,155 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AppInfo описывает приложение MixailOS
+type AppInfo struct {
+	// ID - постоянный идентификатор, например "editor"; используется в настройках
+	ID       string
+	Name     string
+	Category string
+	// MIMETypes - типы файлов, которые открывает приложение: "text/plain", "image/*"
+	MIMETypes []string
+	// Order - порядок приложения во вкладках и в меню «Пуск»
+	Order int
+	// OpensFiles - приложение умеет открывать файлы; заполняется при регистрации
+	OpensFiles bool
+}
+
+// Supports проверяет, открывает ли приложение файлы типа mimeType
+func (a AppInfo) Supports(mimeType string) bool {
+	for _, pattern := range a.MIMETypes {
+		if MatchMIME(pattern, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// supportsExact проверяет, указан ли тип среди типов приложения без шаблонов
+func (a AppInfo) supportsExact(mimeType string) bool {
+	for _, pattern := range a.MIMETypes {
+		if pattern == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchMIME проверяет соответствие типа шаблону вида "image/*" или "text/plain"
+func MatchMIME(pattern, mimeType string) bool {
+	if pattern == "*/*" || pattern == mimeType {
+		return true
+	}
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(mimeType, strings.TrimSuffix(pattern, "*"))
+	}
+	return false
+}
+
+// AppRegistry - реестр приложений. Графический интерфейс регистрирует в нем
+// свои приложения, а консоль и ассоциации файлов находят их по идентификатору и типу.
+type AppRegistry struct {
+	mu   sync.Mutex
+	apps []AppInfo
+}
+
+// NewAppRegistry создает пустой реестр приложений
+func NewAppRegistry() *AppRegistry {
+	return &AppRegistry{}
+}
+
+// Register добавляет приложение в реестр
+func (r *AppRegistry) Register(info AppInfo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info.ID == "" {
+		return fmt.Errorf("у приложения %q нет идентификатора", info.Name)
+	}
+	for _, app := range r.apps {
+		if app.ID == info.ID {
+			return fmt.Errorf("приложение %s уже зарегистрировано", info.ID)
+		}
+	}
+	r.apps = append(r.apps, info)
+	sort.SliceStable(r.apps, func(i, j int) bool {
+		return r.apps[i].Order < r.apps[j].Order
+	})
+	return nil
+}
+
+// List возвращает приложения в порядке Order
+func (r *AppRegistry) List() []AppInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]AppInfo(nil), r.apps...)
+}
+
+// Get возвращает приложение по идентификатору
+func (r *AppRegistry) Get(id string) (AppInfo, bool) {
+	for _, app := range r.List() {
+		if app.ID == id {
+			return app, true
+		}
+	}
+	return AppInfo{}, false
+}
+
+// Find ищет приложение по идентификатору или названию без учета регистра
+func (r *AppRegistry) Find(name string) (AppInfo, bool) {
+	for _, app := range r.List() {
+		if strings.EqualFold(app.ID, name) || strings.EqualFold(app.Name, name) {
+			return app, true
+		}
+	}
+	return AppInfo{}, false
+}
+
+// FileOpeners возвращает приложения, которые умеют открывать файлы
+func (r *AppRegistry) FileOpeners() []AppInfo {
+	var apps []AppInfo
+	for _, app := range r.List() {
+		if app.OpensFiles {
+			apps = append(apps, app)
+		}
+	}
+	return apps
+}
+
+// ForMIME возвращает приложения, открывающие файлы типа mimeType: сначала
+// указавшие тип явно, затем поддерживающие его через шаблон
+func (r *AppRegistry) ForMIME(mimeType string) []AppInfo {
+	var exact, wildcard []AppInfo
+	for _, app := range r.FileOpeners() {
+		switch {
+		case app.supportsExact(mimeType):
+			exact = append(exact, app)
+		case app.Supports(mimeType):
+			wildcard = append(wildcard, app)
+		}
+	}
+	return append(exact, wildcard...)
+}
+
+// DefaultFor выбирает приложение для файлов типа mimeType. Сначала используется
+// назначение из настроек (Config.AssociatedApp), затем первое подходящее
+// приложение реестра.
+func (r *AppRegistry) DefaultFor(mimeType string, config *Config) (AppInfo, error) {
+	if config != nil {
+		if id, ok := config.AssociatedApp(mimeType); ok {
+			if app, ok := r.Get(id); ok && app.OpensFiles {
+				return app, nil
+			}
+		}
+	}
+	if apps := r.ForMIME(mimeType); len(apps) > 0 {
+		return apps[0], nil
+	}
+	return AppInfo{}, fmt.Errorf("нет приложения для файлов типа %s", mimeType)
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// maxHistoryEntries ограничивает размер сохраняемой истории браузера
+const maxHistoryEntries = 500
+
+// Bookmark - закладка браузера
+type Bookmark struct {
+	Title string    `json:"title"`
+	URL   string    `json:"url"`
+	Added time.Time `json:"added"`
+}
+
+// HistoryEntry - запись истории посещений
+type HistoryEntry struct {
+	Title   string    `json:"title"`
+	URL     string    `json:"url"`
+	Visited time.Time `json:"visited"`
+}
+
+// BrowserStore хранит закладки и историю браузера текущего пользователя
+// в файловой системе MixailOS
+type BrowserStore struct {
+	FileSystem *FileSystem
+}
+
+// NewBrowserStore создает хранилище закладок и истории
+func NewBrowserStore(fs *FileSystem) *BrowserStore {
+	return &BrowserStore{
+		FileSystem: fs,
+	}
+}
+
+// load читает JSON-файл данных браузера; отсутствующий файл не считается ошибкой
+func (s *BrowserStore) load(name string, v interface{}) error {
+	return s.FileSystem.loadUserJSON("browser", name, v)
+}
+
+// save записывает JSON-файл данных браузера
+func (s *BrowserStore) save(name string, v interface{}) error {
+	return s.FileSystem.saveUserJSON("browser", name, v)
+}
+
+// Bookmarks возвращает список закладок
+func (s *BrowserStore) Bookmarks() ([]Bookmark, error) {
+	var bookmarks []Bookmark
+	err := s.load("bookmarks.json", &bookmarks)
+	return bookmarks, err
+}
+
+// AddBookmark добавляет закладку; повторное добавление адреса обновляет ее название
+func (s *BrowserStore) AddBookmark(title, address string) error {
+	bookmarks, err := s.Bookmarks()
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		title = address
+	}
+
+	for i := range bookmarks {
+		if bookmarks[i].URL == address {
+			bookmarks[i].Title = title
+			return s.save("bookmarks.json", bookmarks)
+		}
+	}
+
+	bookmarks = append(bookmarks, Bookmark{
+		Title: title,
+		URL:   address,
+		Added: time.Now(),
+	})
+	return s.save("bookmarks.json", bookmarks)
+}
+
+// RemoveBookmark удаляет закладку по адресу или по номеру в списке (начиная с 1)
+func (s *BrowserStore) RemoveBookmark(key string) error {
+	bookmarks, err := s.Bookmarks()
+	if err != nil {
+		return err
+	}
+
+	index := -1
+	if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(bookmarks) {
+		index = n - 1
+	} else {
+		for i, b := range bookmarks {
+			if b.URL == key {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("закладка %s не найдена", key)
+	}
+
+	bookmarks = append(bookmarks[:index], bookmarks[index+1:]...)
+	return s.save("bookmarks.json", bookmarks)
+}
+
+// IsBookmarked сообщает, есть ли адрес в закладках
+func (s *BrowserStore) IsBookmarked(address string) bool {
+	bookmarks, _ := s.Bookmarks()
+	for _, b := range bookmarks {
+		if b.URL == address {
+			return true
+		}
+	}
+	return false
+}
+
+// History возвращает историю посещений, начиная с самых новых
+func (s *BrowserStore) History() ([]HistoryEntry, error) {
+	var history []HistoryEntry
+	err := s.load("history.json", &history)
+	return history, err
+}
+
+// AddHistory добавляет посещение в начало истории
+func (s *BrowserStore) AddHistory(title, address string) error {
+	history, err := s.History()
+	if err != nil {
+		return err
+	}
+
+	history = append([]HistoryEntry{{
+		Title:   title,
+		URL:     address,
+		Visited: time.Now(),
+	}}, history...)
+	if len(history) > maxHistoryEntries {
+		history = history[:maxHistoryEntries]
+	}
+	return s.save("history.json", history)
+}
+
+// ClearHistory очищает историю посещений
+func (s *BrowserStore) ClearHistory() error {
+	return s.save("history.json", []HistoryEntry{})
+}
@@ -0,0 +1,551 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// browserMaxRedirects - максимальное количество перенаправлений при загрузке страницы
+	browserMaxRedirects = 10
+	// browserMaxBodySize - максимальный размер загружаемой страницы
+	browserMaxBodySize = 5 << 20
+	// browserUserAgent - заголовок User-Agent браузера MixailOS
+	browserUserAgent = "MixailOS-Browser/1.0"
+)
+
+// Link представляет ссылку на отображаемой странице
+type Link struct {
+	Text string
+	URL  string
+	// Offsets - смещения в байтах меток "[n]" ссылки в Page.Text: в тексте
+	// страницы и в списке ссылок. Такой же текст на самой странице меткой не считается.
+	Offsets []int
+}
+
+// Page содержит загруженную и подготовленную к отображению страницу
+type Page struct {
+	URL         string
+	Title       string
+	Text        string
+	Links       []Link
+	StatusCode  int
+	ContentType string
+}
+
+// Browser загружает веб-страницы и преобразует их в читаемый текст
+type Browser struct {
+	Client *http.Client
+	// FileSystem используется для адресов file:// и встроенных страниц mixail://; может быть nil
+	FileSystem *FileSystem
+}
+
+// NewBrowser создает браузер с HTTP-клиентом по умолчанию
+func NewBrowser(fs *FileSystem) *Browser {
+	return &Browser{
+		FileSystem: fs,
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= browserMaxRedirects {
+					return fmt.Errorf("слишком много перенаправлений")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// NormalizeURL дополняет адрес схемой http://, если она не указана
+func NormalizeURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", errors.New("адрес не указан")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("некорректный адрес: %v", err)
+	}
+	// У локальных файлов хост не указывается: file:///Documents/welcome.txt
+	if u.Host == "" && u.Scheme != "file" {
+		return "", fmt.Errorf("некорректный адрес: %s", rawURL)
+	}
+	return u.String(), nil
+}
+
+// Fetch загружает страницу по адресу и преобразует ее в текст. Поддерживаются веб-страницы
+// (http:// и https://), файлы MixailOS (file://) и встроенные страницы (mixail://).
+func (b *Browser) Fetch(rawURL string) (*Page, error) {
+	address, err := NormalizeURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return b.fetchHTTP(address)
+	case "file":
+		return b.fetchFile(u)
+	case "mixail":
+		return b.fetchInternal(u)
+	}
+	return nil, fmt.Errorf("неподдерживаемая схема адреса: %s", address)
+}
+
+// fetchHTTP загружает веб-страницу, следуя перенаправлениям
+func (b *Browser) fetchHTTP(address string) (*Page, error) {
+	req, err := http.NewRequest("GET", address, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", browserUserAgent)
+	req.Header.Set("Accept", "text/html,text/plain;q=0.9,*/*;q=0.5")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	page := &Page{
+		URL:         resp.Request.URL.String(),
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+
+	// Перекодируем тело ответа в UTF-8 по заголовку, meta-тегу или BOM
+	body := io.LimitReader(resp.Body, browserMaxBodySize)
+	reader, err := charset.NewReader(body, page.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при определении кодировки: %v", err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(page.ContentType)
+	switch {
+	case mediaType == "" || mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		if err := page.render(reader, resp.Request.URL); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(mediaType, "text/"):
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		page.Text = string(data)
+	default:
+		page.Text = fmt.Sprintf("Содержимое типа %s не может быть отображено", mediaType)
+	}
+
+	if page.Title == "" {
+		page.Title = page.URL
+	}
+	if resp.StatusCode >= 400 {
+		page.Text = fmt.Sprintf("Ошибка HTTP %s\n\n%s", resp.Status, page.Text)
+	}
+	return page, nil
+}
+
+// RenderHTML преобразует HTML-документ в страницу с текстом и нумерованными ссылками.
+// base используется для разрешения относительных ссылок и может быть nil.
+func RenderHTML(r io.Reader, base *url.URL) (*Page, error) {
+	page := &Page{}
+	if base != nil {
+		page.URL = base.String()
+	}
+	if err := page.render(r, base); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// render разбирает HTML и заполняет заголовок, текст и ссылки страницы
+func (p *Page) render(r io.Reader, base *url.URL) error {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return fmt.Errorf("ошибка при разборе HTML: %v", err)
+	}
+
+	tr := &textRenderer{base: base}
+	tr.walk(doc)
+	p.Title = tr.title
+	p.Links = tr.links
+
+	// Отрезанные в начале пробелы сдвигают метки ссылок
+	text := strings.TrimLeftFunc(tr.sb.String(), unicode.IsSpace)
+	shift := tr.sb.Len() - len(text)
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+	if len(tr.links) > 0 {
+		var sb strings.Builder
+		sb.WriteString(text)
+		sb.WriteString("\n\nСсылки:\n")
+		for i := range tr.links {
+			link := &tr.links[i]
+			for j := range link.Offsets {
+				link.Offsets[j] -= shift
+			}
+			link.Offsets = append(link.Offsets, sb.Len())
+			sb.WriteString(fmt.Sprintf("[%d] %s\n", i+1, link.URL))
+		}
+		text = strings.TrimRightFunc(sb.String(), unicode.IsSpace)
+	}
+	p.Text = text
+	return nil
+}
+
+// listState описывает вложенный список при отрисовке
+type listState struct {
+	ordered bool
+	index   int
+}
+
+// textRenderer обходит дерево HTML и формирует читаемый текст
+type textRenderer struct {
+	base  *url.URL
+	sb    strings.Builder
+	title string
+	links []Link
+
+	lists []listState
+	// newlines - сколько переводов строки нужно вставить перед следующим текстом
+	newlines int
+	// lineStart - находимся ли в начале строки
+	lineStart bool
+	// space - нужно ли вставить пробел перед следующим словом
+	space bool
+	pre   int
+	quote int
+}
+
+// skippedElements - элементы, содержимое которых не отображается
+var skippedElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "iframe": true, "object": true,
+}
+
+// blockElements - элементы, начинающиеся с новой строки
+var blockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true,
+	"footer": true, "nav": true, "main": true, "aside": true, "form": true,
+	"table": true, "tr": true, "dl": true, "dt": true, "dd": true,
+	"figure": true, "figcaption": true, "address": true, "fieldset": true,
+	"details": true, "summary": true,
+}
+
+// walk рекурсивно обходит узлы документа
+func (tr *textRenderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		tr.text(n.Data)
+		return
+	case html.ElementNode:
+		// обрабатывается ниже
+	default:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			tr.walk(c)
+		}
+		return
+	}
+
+	tag := n.Data
+	if tag == "title" {
+		if tr.title == "" {
+			tr.title = strings.Join(strings.Fields(textContent(n)), " ")
+		}
+		return
+	}
+	if skippedElements[tag] {
+		return
+	}
+
+	switch tag {
+	case "br":
+		tr.breakLine(1)
+		return
+	case "hr":
+		tr.breakLine(1)
+		tr.write(strings.Repeat("─", 40))
+		tr.breakLine(1)
+		return
+	case "img":
+		if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+			tr.text("[Изображение: " + alt + "]")
+		}
+		return
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(tag[1:])
+		heading := strings.Join(strings.Fields(textContent(n)), " ")
+		tr.breakLine(2)
+		switch level {
+		case 1:
+			tr.write(strings.ToUpper(heading))
+			tr.breakLine(1)
+			tr.write(strings.Repeat("=", len([]rune(heading))))
+		case 2:
+			tr.write(heading)
+			tr.breakLine(1)
+			tr.write(strings.Repeat("-", len([]rune(heading))))
+		default:
+			tr.write(strings.Repeat("#", level) + " " + heading)
+		}
+		tr.breakLine(2)
+		return
+	case "ul", "ol":
+		tr.lists = append(tr.lists, listState{ordered: tag == "ol"})
+		tr.breakLine(1)
+		tr.children(n)
+		tr.lists = tr.lists[:len(tr.lists)-1]
+		tr.breakLine(1)
+		return
+	case "li":
+		tr.breakLine(1)
+		marker := "•"
+		depth := len(tr.lists)
+		if depth > 0 {
+			list := &tr.lists[depth-1]
+			list.index++
+			if list.ordered {
+				marker = strconv.Itoa(list.index) + "."
+			}
+		} else {
+			depth = 1
+		}
+		tr.write(strings.Repeat("  ", depth) + marker + " ")
+		tr.space = false
+		tr.children(n)
+		tr.breakLine(1)
+		return
+	case "a":
+		tr.children(n)
+		href := strings.TrimSpace(attr(n, "href"))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return
+		}
+		marker := fmt.Sprintf("[%d]", len(tr.links)+1)
+		tr.write(marker)
+		tr.links = append(tr.links, Link{
+			Text:    strings.Join(strings.Fields(textContent(n)), " "),
+			URL:     tr.resolve(href),
+			Offsets: []int{tr.sb.Len() - len(marker)},
+		})
+		return
+	case "td", "th":
+		if n.PrevSibling != nil {
+			tr.write(" | ")
+			tr.space = false
+		}
+		tr.children(n)
+		return
+	case "pre":
+		tr.breakLine(2)
+		tr.pre++
+		tr.children(n)
+		tr.pre--
+		tr.breakLine(2)
+		return
+	case "blockquote":
+		tr.breakLine(2)
+		tr.quote++
+		tr.children(n)
+		tr.quote--
+		tr.breakLine(2)
+		return
+	}
+
+	if blockElements[tag] {
+		gap := 1
+		if tag == "p" {
+			gap = 2
+		}
+		tr.breakLine(gap)
+		tr.children(n)
+		tr.breakLine(gap)
+		return
+	}
+
+	tr.children(n)
+}
+
+// children обходит дочерние узлы
+func (tr *textRenderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		tr.walk(c)
+	}
+}
+
+// text выводит текстовый узел, схлопывая пробелы вне <pre>
+func (tr *textRenderer) text(data string) {
+	if tr.pre > 0 {
+		lines := strings.Split(data, "\n")
+		for i, line := range lines {
+			if i > 0 {
+				tr.breakLine(1)
+			}
+			if line != "" {
+				tr.write(line)
+			}
+		}
+		return
+	}
+
+	if data != "" && isSpace(data[0]) {
+		tr.space = true
+	}
+	words := strings.Fields(data)
+	for i, word := range words {
+		if i > 0 {
+			tr.space = true
+		}
+		tr.write(word)
+	}
+	if data != "" && isSpace(data[len(data)-1]) {
+		tr.space = true
+	}
+}
+
+// write добавляет текст с учетом отложенных переводов строки и отступов
+func (tr *textRenderer) write(s string) {
+	if tr.newlines > 0 && tr.sb.Len() > 0 {
+		tr.sb.WriteString(strings.Repeat("\n", tr.newlines))
+		tr.lineStart = true
+	}
+	tr.newlines = 0
+
+	if tr.lineStart || tr.sb.Len() == 0 {
+		if tr.quote > 0 {
+			tr.sb.WriteString(strings.Repeat("> ", tr.quote))
+		}
+		tr.lineStart = false
+	} else if tr.space {
+		tr.sb.WriteString(" ")
+	}
+	tr.space = false
+	tr.sb.WriteString(s)
+}
+
+// breakLine запрашивает не менее count переводов строки перед следующим текстом
+func (tr *textRenderer) breakLine(count int) {
+	if count > tr.newlines {
+		tr.newlines = count
+	}
+	tr.space = false
+}
+
+// resolve преобразует относительную ссылку в абсолютную
+func (tr *textRenderer) resolve(href string) string {
+	ref, err := url.Parse(href)
+	if err != nil || tr.base == nil {
+		return href
+	}
+	return tr.base.ResolveReference(ref).String()
+}
+
+// textContent возвращает весь текст внутри узла
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && skippedElements[c.Data] {
+			continue
+		}
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+// attr возвращает значение атрибута элемента
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// isSpace проверяет, является ли байт пробельным символом
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+// Navigator хранит историю переходов вкладки браузера для кнопок "Назад" и "Вперед"
+type Navigator struct {
+	back    []string
+	forward []string
+	current string
+}
+
+// Visit переходит на новый адрес, очищая стек "Вперед"
+func (n *Navigator) Visit(address string) {
+	if address == n.current {
+		return
+	}
+	if n.current != "" {
+		n.back = append(n.back, n.current)
+	}
+	n.current = address
+	n.forward = nil
+}
+
+// Replace заменяет текущий адрес без изменения истории (например, после перенаправления)
+func (n *Navigator) Replace(address string) {
+	n.current = address
+}
+
+// Back возвращается на предыдущий адрес
+func (n *Navigator) Back() (string, bool) {
+	if len(n.back) == 0 {
+		return "", false
+	}
+	n.forward = append(n.forward, n.current)
+	n.current = n.back[len(n.back)-1]
+	n.back = n.back[:len(n.back)-1]
+	return n.current, true
+}
+
+// Forward переходит на адрес, с которого вернулись кнопкой "Назад"
+func (n *Navigator) Forward() (string, bool) {
+	if len(n.forward) == 0 {
+		return "", false
+	}
+	n.back = append(n.back, n.current)
+	n.current = n.forward[len(n.forward)-1]
+	n.forward = n.forward[:len(n.forward)-1]
+	return n.current, true
+}
+
+// Current возвращает текущий адрес
+func (n *Navigator) Current() string {
+	return n.current
+}
+
+// CanGoBack сообщает, есть ли куда вернуться
+func (n *Navigator) CanGoBack() bool {
+	return len(n.back) > 0
+}
+
+// CanGoForward сообщает, есть ли куда перейти вперед
+func (n *Navigator) CanGoForward() bool {
+	return len(n.forward) > 0
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer запускает сервер со страницами для проверки браузера
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != browserUserAgent {
+			t.Errorf("User-Agent = %q, ожидался %q", ua, browserUserAgent)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Тестовая  страница</title><script>alert(1)</script></head>
+<body><h1>Заголовок</h1><p>Текст <a href="/other">ссылки</a> и <a href="#top">якорь</a>.</p>
+<ul><li>один</li><li>два</li></ul><ol><li>первый</li></ol></body></html>`))
+	})
+	mux.HandleFunc("/cp1251", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+		// "Привет" в кодировке windows-1251
+		w.Write([]byte("<p>\xcf\xf0\xe8\xe2\xe5\xf2</p>"))
+	})
+	mux.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("<b>как есть</b>"))
+	})
+	mux.HandleFunc("/binary", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte{0, 1, 2})
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<p>Нет такой страницы</p>"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBrowserFetchHTML(t *testing.T) {
+	srv := newTestServer(t)
+	page, err := NewBrowser(nil).Fetch(srv.URL + "/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Title != "Тестовая страница" {
+		t.Errorf("Title = %q", page.Title)
+	}
+	if page.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d", page.StatusCode)
+	}
+	for _, want := range []string{"ЗАГОЛОВОК\n=========", "Текст ссылки[1] и якорь.", "• один", "• два", "1. первый"} {
+		if !strings.Contains(page.Text, want) {
+			t.Errorf("в тексте нет %q:\n%s", want, page.Text)
+		}
+	}
+	if strings.Contains(page.Text, "alert") {
+		t.Errorf("текст содержит скрипт:\n%s", page.Text)
+	}
+	if len(page.Links) != 1 || page.Links[0].URL != srv.URL+"/other" || page.Links[0].Text != "ссылки" {
+		t.Errorf("Links = %+v", page.Links)
+	}
+}
+
+func TestBrowserFetchCharset(t *testing.T) {
+	srv := newTestServer(t)
+	page, err := NewBrowser(nil).Fetch(srv.URL + "/cp1251")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.Text, "Привет") {
+		t.Errorf("Text = %q", page.Text)
+	}
+}
+
+func TestBrowserFetchContentTypes(t *testing.T) {
+	srv := newTestServer(t)
+	browser := NewBrowser(nil)
+
+	page, err := browser.Fetch(srv.URL + "/text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Text != "<b>как есть</b>" {
+		t.Errorf("текстовый файл: Text = %q", page.Text)
+	}
+
+	page, err = browser.Fetch(srv.URL + "/binary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.Text, "application/octet-stream") {
+		t.Errorf("двоичный файл: Text = %q", page.Text)
+	}
+}
+
+func TestBrowserFetchRedirect(t *testing.T) {
+	srv := newTestServer(t)
+	page, err := NewBrowser(nil).Fetch(srv.URL + "/redirect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.URL != srv.URL+"/page" {
+		t.Errorf("URL = %q, ожидался адрес после перенаправления", page.URL)
+	}
+	if page.Title != "Тестовая страница" {
+		t.Errorf("Title = %q", page.Title)
+	}
+}
+
+func TestBrowserFetchErrors(t *testing.T) {
+	srv := newTestServer(t)
+	browser := NewBrowser(nil)
+
+	if _, err := browser.Fetch(srv.URL + "/loop"); err == nil || !strings.Contains(err.Error(), "слишком много перенаправлений") {
+		t.Errorf("бесконечное перенаправление: err = %v", err)
+	}
+
+	page, err := browser.Fetch(srv.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.StatusCode != http.StatusNotFound || !strings.HasPrefix(page.Text, "Ошибка HTTP 404") || !strings.Contains(page.Text, "Нет такой страницы") {
+		t.Errorf("страница 404: StatusCode = %d, Text = %q", page.StatusCode, page.Text)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if _, err := browser.Fetch(closed.URL); err == nil {
+		t.Error("недоступный сервер: ожидалась ошибка")
+	}
+
+	for _, address := range []string{"", "   ", "ftp://example.com", "http://"} {
+		if _, err := browser.Fetch(address); err == nil {
+			t.Errorf("Fetch(%q): ожидалась ошибка", address)
+		}
+	}
+}
+
+func TestRenderHTMLLinkOffsets(t *testing.T) {
+	page, err := RenderHTML(strings.NewReader(`<p>  Сноска [1] в тексте, <a href="/a">первая</a> и <a href="/b">вторая</a></p>`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Links) != 2 {
+		t.Fatalf("Links = %+v", page.Links)
+	}
+	for i, link := range page.Links {
+		marker := "[" + string(rune('1'+i)) + "]"
+		if len(link.Offsets) != 2 {
+			t.Fatalf("ссылка %d: Offsets = %v", i+1, link.Offsets)
+		}
+		for _, offset := range link.Offsets {
+			if got := page.Text[offset : offset+len(marker)]; got != marker {
+				t.Errorf("ссылка %d: по смещению %d текст %q, ожидалась метка %q", i+1, offset, got, marker)
+			}
+		}
+	}
+	// Текст "[1]" на самой странице меткой не считается
+	if literal := strings.Index(page.Text, "[1]"); literal == page.Links[0].Offsets[0] {
+		t.Errorf("метка первой ссылки указывает на текст страницы: %q", page.Text)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"example.com", "http://example.com"},
+		{" https://example.com/a?b=1 ", "https://example.com/a?b=1"},
+		{"file:///Documents/welcome.txt", "file:///Documents/welcome.txt"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeURL(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, %v; ожидалось %q", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,683 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CalcError - ошибка разбора или вычисления выражения с позицией в строке
+type CalcError struct {
+	// Pos - позиция ошибки в символах (рунах), начиная с 0
+	Pos int
+	Msg string
+}
+
+// Error возвращает текст ошибки с позицией, начиная с 1
+func (e *CalcError) Error() string {
+	return fmt.Sprintf("%s (позиция %d)", e.Msg, e.Pos+1)
+}
+
+// calcErrorf создает ошибку калькулятора в заданной позиции
+func calcErrorf(pos int, format string, args ...interface{}) *CalcError {
+	return &CalcError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// FormatCalcError показывает выражение и отмечает место ошибки символом "^"
+func FormatCalcError(source string, err error) string {
+	ce, ok := err.(*CalcError)
+	if !ok {
+		return "Ошибка: " + err.Error()
+	}
+	return fmt.Sprintf("%s\n%s^\nОшибка: %s", source, strings.Repeat(" ", ce.Pos), ce.Error())
+}
+
+// calcTokenKind - тип лексемы выражения
+type calcTokenKind int
+
+const (
+	calcEOF calcTokenKind = iota
+	calcNumber
+	calcIdent
+	calcOperator
+	calcLParen
+	calcRParen
+	calcComma
+	calcAssign
+)
+
+// calcToken - лексема выражения
+type calcToken struct {
+	kind calcTokenKind
+	text string
+	pos  int
+}
+
+// calcOperatorAliases - альтернативные обозначения операций
+var calcOperatorAliases = map[string]string{
+	"×": "*", "·": "*", "÷": "/", "−": "-", "**": "^",
+}
+
+// calcOperators - операторы в порядке проверки: двухсимвольные раньше односимвольных
+var calcOperators = []string{"**", "<<", ">>", "+", "-", "*", "/", "^", "%", "!", "&", "|", "~", "×", "·", "÷", "−"}
+
+// calcWordOperators - операторы, записываемые словами
+var calcWordOperators = map[string]bool{"mod": true, "xor": true}
+
+// tokenizeExpression разбивает выражение на лексемы. base - основание чисел без префикса:
+// при base = 16 слова из шестнадцатеричных цифр (ff, a0) считаются числами.
+func tokenizeExpression(source string, base int) ([]calcToken, error) {
+	runes := []rune(source)
+	var tokens []calcToken
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '0' && i+2 < len(runes) && strings.ContainsRune("xXbBoO", runes[i+1]) && isWordRune(runes[i+2]):
+			// Числа с префиксом основания: 0xFF, 0b1010, 0o17
+			start := i
+			i += 2
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, calcToken{kind: calcNumber, text: string(runes[start:i]), pos: start})
+
+		case base == 16 && isHexDigit(r):
+			// Шестнадцатеричное число без префикса; слова, не являющиеся числом, остаются именами
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := calcNumber
+			if !unicode.IsDigit(r) && strings.IndexFunc(word, func(r rune) bool { return !isHexDigit(r) }) >= 0 {
+				kind = calcIdent
+				if calcWordOperators[word] {
+					kind = calcOperator
+				}
+			}
+			tokens = append(tokens, calcToken{kind: kind, text: word, pos: start})
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Экспоненциальная запись: 1.5e3, 2E-4
+			if base == 10 && i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			tokens = append(tokens, calcToken{kind: calcNumber, text: string(runes[start:i]), pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := calcIdent
+			if calcWordOperators[word] {
+				kind = calcOperator
+			}
+			tokens = append(tokens, calcToken{kind: kind, text: word, pos: start})
+
+		case r == '(':
+			tokens = append(tokens, calcToken{kind: calcLParen, text: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, calcToken{kind: calcRParen, text: ")", pos: i})
+			i++
+
+		case r == ',' || r == ';':
+			tokens = append(tokens, calcToken{kind: calcComma, text: string(r), pos: i})
+			i++
+
+		case r == '=':
+			tokens = append(tokens, calcToken{kind: calcAssign, text: "=", pos: i})
+			i++
+
+		default:
+			op := ""
+			for _, candidate := range calcOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, calcErrorf(i, "неожиданный символ %q", r)
+			}
+			start := i
+			i += len([]rune(op))
+			if alias, ok := calcOperatorAliases[op]; ok {
+				op = alias
+			}
+			tokens = append(tokens, calcToken{kind: calcOperator, text: op, pos: start})
+		}
+	}
+
+	tokens = append(tokens, calcToken{kind: calcEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// isWordRune проверяет, может ли символ входить в имя или число
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// isHexDigit проверяет, является ли символ шестнадцатеричной цифрой
+func isHexDigit(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// calcNode - узел дерева разобранного выражения
+type calcNode interface {
+	position() int
+}
+
+// numberNode - числовая константа
+type numberNode struct {
+	text string
+	pos  int
+}
+
+// unaryNode - унарный минус или плюс
+type unaryNode struct {
+	op  string
+	x   calcNode
+	pos int
+}
+
+// binaryNode - бинарная операция
+type binaryNode struct {
+	op   string
+	x, y calcNode
+	pos  int
+}
+
+// percentNode - процент: x% равно x/100, а в выражениях a + b% и a - b% берется b% от a
+type percentNode struct {
+	x   calcNode
+	pos int
+}
+
+// factorialNode - факториал x!
+type factorialNode struct {
+	x   calcNode
+	pos int
+}
+
+// varNode - константа или переменная
+type varNode struct {
+	name string
+	pos  int
+}
+
+// callNode - вызов функции
+type callNode struct {
+	name string
+	args []calcNode
+	pos  int
+}
+
+// position возвращает позицию узла в исходной строке
+func (n *numberNode) position() int    { return n.pos }
+func (n *unaryNode) position() int     { return n.pos }
+func (n *binaryNode) position() int    { return n.pos }
+func (n *percentNode) position() int   { return n.pos }
+func (n *factorialNode) position() int { return n.pos }
+func (n *varNode) position() int       { return n.pos }
+func (n *callNode) position() int      { return n.pos }
+
+// calcParser - разбор выражения рекурсивным спуском.
+//
+//	input   = [ name "=" ] expr
+//	expr    = bitXor { "|" bitXor }
+//	bitXor  = bitAnd { "xor" bitAnd }
+//	bitAnd  = shift { "&" shift }
+//	shift   = sum { ("<<" | ">>") sum }
+//	sum     = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "mod") unary }
+//	unary   = ("+" | "-" | "~") unary | power
+//	power   = postfix [ "^" unary ]         (правоассоциативно)
+//	postfix = primary { "%" | "!" }
+//	primary = number | name | name "(" [ expr { "," expr } ] ")" | "(" expr ")"
+type calcParser struct {
+	tokens []calcToken
+	pos    int
+	base   int
+}
+
+// Expression - разобранное выражение калькулятора, которое можно вычислять многократно
+type Expression struct {
+	Source string
+	// Assign - имя переменной для выражений вида "x = 2 * y" или пустая строка
+	Assign string
+	root   calcNode
+}
+
+// ParseExpression разбирает выражение
+func ParseExpression(source string) (*Expression, error) {
+	return ParseExpressionBase(source, 10)
+}
+
+// ParseExpressionBase разбирает выражение, в котором числа без префикса
+// записаны в системе счисления base (2, 8, 10 или 16)
+func ParseExpressionBase(source string, base int) (*Expression, error) {
+	tokens, err := tokenizeExpression(source, base)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == calcEOF {
+		return nil, calcErrorf(0, "пустое выражение")
+	}
+
+	p := &calcParser{tokens: tokens, base: base}
+	assign := ""
+	if tokens[0].kind == calcIdent && tokens[1].kind == calcAssign {
+		assign = tokens[0].text
+		if err := checkAssignable(assign); err != nil {
+			return nil, calcErrorf(tokens[0].pos, "%v", err)
+		}
+		p.pos = 2
+	}
+
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := p.peek(); t.kind {
+	case calcEOF:
+	case calcRParen:
+		return nil, calcErrorf(t.pos, "лишняя закрывающая скобка")
+	case calcAssign:
+		return nil, calcErrorf(t.pos, "присваивание возможно только в начале выражения: имя = выражение")
+	default:
+		return nil, calcErrorf(t.pos, "ожидался оператор, а не %q", t.text)
+	}
+	return &Expression{Source: source, Assign: assign, root: root}, nil
+}
+
+// peek возвращает текущую лексему
+func (p *calcParser) peek() calcToken {
+	return p.tokens[p.pos]
+}
+
+// next возвращает текущую лексему и переходит к следующей
+func (p *calcParser) next() calcToken {
+	t := p.tokens[p.pos]
+	if t.kind != calcEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isOperator проверяет, является ли текущая лексема одним из операторов
+func (p *calcParser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != calcOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// binaryLevel разбирает левоассоциативную цепочку операций ops над операндами operand
+func (p *calcParser) binaryLevel(operand func() (calcNode, error), ops ...string) (calcNode, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(ops...) {
+		op := p.next()
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryNode{op: op.text, x: x, y: y, pos: op.pos}
+	}
+	return x, nil
+}
+
+// expr разбирает побитовое ИЛИ - операцию с наименьшим приоритетом
+func (p *calcParser) expr() (calcNode, error) {
+	return p.binaryLevel(p.bitXor, "|")
+}
+
+// bitXor разбирает исключающее ИЛИ
+func (p *calcParser) bitXor() (calcNode, error) {
+	return p.binaryLevel(p.bitAnd, "xor")
+}
+
+// bitAnd разбирает побитовое И
+func (p *calcParser) bitAnd() (calcNode, error) {
+	return p.binaryLevel(p.shift, "&")
+}
+
+// shift разбирает битовые сдвиги
+func (p *calcParser) shift() (calcNode, error) {
+	return p.binaryLevel(p.sum, "<<", ">>")
+}
+
+// sum разбирает сложение и вычитание
+func (p *calcParser) sum() (calcNode, error) {
+	return p.binaryLevel(p.term, "+", "-")
+}
+
+// term разбирает умножение, деление и остаток от деления
+func (p *calcParser) term() (calcNode, error) {
+	return p.binaryLevel(p.unary, "*", "/", "mod")
+}
+
+// unary разбирает унарные плюс, минус и побитовое НЕ
+func (p *calcParser) unary() (calcNode, error) {
+	if p.isOperator("+", "-", "~") {
+		op := p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op.text, x: x, pos: op.pos}, nil
+	}
+	return p.power()
+}
+
+// power разбирает возведение в степень
+func (p *calcParser) power() (calcNode, error) {
+	x, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		op := p.next()
+		// Правая часть разбирается через unary, поэтому 2^-1 и 2^3^2 = 2^(3^2) работают
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op.text, x: x, y: y, pos: op.pos}, nil
+	}
+	return x, nil
+}
+
+// postfix разбирает знаки процента и факториала
+func (p *calcParser) postfix() (calcNode, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("%", "!") {
+		op := p.next()
+		if op.text == "!" {
+			x = &factorialNode{x: x, pos: op.pos}
+		} else {
+			x = &percentNode{x: x, pos: op.pos}
+		}
+	}
+	return x, nil
+}
+
+// primary разбирает число, имя, вызов функции или выражение в скобках
+func (p *calcParser) primary() (calcNode, error) {
+	t := p.next()
+	switch t.kind {
+	case calcNumber:
+		// Целые числа проверяются при вычислении, так как зависят от размера слова
+		if p.base == 10 {
+			if _, err := parseFloatLiteral(t.text); err != nil {
+				return nil, calcErrorf(t.pos, "некорректное число %q", t.text)
+			}
+		}
+		return &numberNode{text: t.text, pos: t.pos}, nil
+
+	case calcLParen:
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != calcRParen {
+			return nil, calcErrorf(p.peek().pos, "не хватает закрывающей скобки к открытой в позиции %d", t.pos+1)
+		}
+		p.next()
+		return x, nil
+
+	case calcIdent:
+		if p.peek().kind != calcLParen {
+			return &varNode{name: t.text, pos: t.pos}, nil
+		}
+		return p.call(t)
+
+	case calcEOF:
+		return nil, calcErrorf(t.pos, "неожиданный конец выражения")
+	}
+	return nil, calcErrorf(t.pos, "ожидалось число, а не %q", t.text)
+}
+
+// call разбирает аргументы вызова функции; имя функции уже прочитано
+func (p *calcParser) call(name calcToken) (calcNode, error) {
+	fn, ok := calcFunctions[name.text]
+	if !ok {
+		return nil, calcErrorf(name.pos, "неизвестная функция %q", name.text)
+	}
+
+	open := p.next()
+	var args []calcNode
+	if p.peek().kind != calcRParen {
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != calcComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.peek().kind != calcRParen {
+		return nil, calcErrorf(p.peek().pos, "не хватает закрывающей скобки к открытой в позиции %d", open.pos+1)
+	}
+	p.next()
+
+	if len(args) != fn.arity {
+		return nil, calcErrorf(name.pos, "функция %s принимает аргументов: %d, передано: %d", name.text, fn.arity, len(args))
+	}
+	return &callNode{name: name.text, args: args, pos: name.pos}, nil
+}
+
+// Env - окружение вычисления: значения переменных и единицы измерения углов
+type Env struct {
+	Vars map[string]float64
+	// Degrees - тригонометрические функции работают в градусах, а не в радианах
+	Degrees bool
+}
+
+// Eval вычисляет выражение без переменных
+func (e *Expression) Eval() (float64, error) {
+	return e.EvalEnv(nil)
+}
+
+// EvalEnv вычисляет выражение в окружении env, которое может быть nil
+func (e *Expression) EvalEnv(env *Env) (float64, error) {
+	if env == nil {
+		env = &Env{}
+	}
+	v, err := evalFloat(e.root, env)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, calcErrorf(0, "результат не является конечным числом")
+	}
+	return v, nil
+}
+
+// Evaluate разбирает и вычисляет выражение
+func Evaluate(source string) (float64, error) {
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return 0, err
+	}
+	return expr.Eval()
+}
+
+// FormatNumber форматирует результат вычисления, округляя его до 15 значащих цифр,
+// чтобы скрыть погрешность вычислений (sin(30°) показывается как 0.5)
+func FormatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'g', 15, 64)
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// parseFloatLiteral разбирает десятичное число или целое число с префиксом 0x, 0o, 0b
+func parseFloatLiteral(text string) (float64, error) {
+	if len(text) > 2 && text[0] == '0' && strings.ContainsRune("xXbBoO", rune(text[1])) {
+		v, err := strconv.ParseUint(text, 0, 64)
+		return float64(v), err
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// evalFloat вычисляет узел в числах с плавающей точкой
+func evalFloat(n calcNode, env *Env) (float64, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return parseFloatLiteral(n.text)
+
+	case *unaryNode:
+		x, err := evalFloat(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		switch n.op {
+		case "-":
+			return -x, nil
+		case "~":
+			return 0, calcErrorf(n.pos, "битовые операции доступны только в режиме программиста")
+		}
+		return x, nil
+
+	case *percentNode:
+		x, err := evalFloat(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		return x / 100, nil
+
+	case *factorialNode:
+		x, err := evalFloat(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		v, msg := factorial(x)
+		if msg != "" {
+			return 0, calcErrorf(n.pos, "%s", msg)
+		}
+		return v, nil
+
+	case *varNode:
+		if v, ok := lookupName(n.name, env); ok {
+			return v, nil
+		}
+		return 0, calcErrorf(n.pos, "неизвестное имя %q", n.name)
+
+	case *callNode:
+		args := make([]float64, len(n.args))
+		for i, arg := range n.args {
+			v, err := evalFloat(arg, env)
+			if err != nil {
+				return 0, err
+			}
+			args[i] = v
+		}
+		v, msg := calcFunctions[n.name].fn(args, env)
+		if msg != "" {
+			return 0, calcErrorf(n.pos, "%s", msg)
+		}
+		if math.IsInf(v, 0) {
+			return 0, calcErrorf(n.pos, "переполнение")
+		}
+		if math.IsNaN(v) {
+			return 0, calcErrorf(n.pos, "функция %s не определена для этого аргумента", n.name)
+		}
+		return v, nil
+
+	case *binaryNode:
+		x, err := evalFloat(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		// a + b% означает a + (a * b / 100)
+		if pct, ok := n.y.(*percentNode); ok && (n.op == "+" || n.op == "-") {
+			y, err := evalFloat(pct, env)
+			if err != nil {
+				return 0, err
+			}
+			if n.op == "+" {
+				return x + x*y, nil
+			}
+			return x - x*y, nil
+		}
+		y, err := evalFloat(n.y, env)
+		if err != nil {
+			return 0, err
+		}
+
+		var v float64
+		switch n.op {
+		case "+":
+			v = x + y
+		case "-":
+			v = x - y
+		case "*":
+			v = x * y
+		case "/":
+			if y == 0 {
+				return 0, calcErrorf(n.pos, "деление на ноль")
+			}
+			v = x / y
+		case "mod":
+			if y == 0 {
+				return 0, calcErrorf(n.pos, "деление на ноль")
+			}
+			v = math.Mod(x, y)
+		case "&", "|", "xor", "<<", ">>":
+			return 0, calcErrorf(n.pos, "битовые операции доступны только в режиме программиста")
+		case "^":
+			v = math.Pow(x, y)
+			if math.IsNaN(v) {
+				return 0, calcErrorf(n.pos, "степень не определена")
+			}
+		default:
+			return 0, calcErrorf(n.pos, "неизвестная операция %q", n.op)
+		}
+		if math.IsInf(v, 0) {
+			return 0, calcErrorf(n.pos, "переполнение")
+		}
+		return v, nil
+	}
+	return 0, calcErrorf(n.position(), "неизвестная операция")
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"2*3*4", 24},
+		{"2+3*4", 14},
+		{"(2+3)*4", 20},
+		{"10/4", 2.5},
+		{"10 - 4 - 3", 3},
+		{"-2^2", -4},
+		{"2^3^2", 512},
+		{"2**3", 8},
+		{"2^-1", 0.5},
+		{"1 - -1", 2},
+		{"200+10%", 220},
+		{"200-10%", 180},
+		{"50%", 0.5},
+		{"7 mod 3", 1},
+		{"5!", 120},
+		{"-3!", -6},
+		{"2 × 3 ÷ 4", 1.5},
+		{"1e3", 1000},
+		{"0x10+0b11+0o7", 26},
+		{"sqrt(16) + abs(-2)", 6},
+		{"x = 5", 5},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Evaluate(%q) = %v, %v; ожидалось %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+		msg string
+	}{
+		{"", 0, "пустое выражение"},
+		{"2+", 2, "неожиданный конец выражения"},
+		{"2+*3", 2, "ожидалось число"},
+		{"(2+3", 4, "не хватает закрывающей скобки"},
+		{"2+3)", 3, "лишняя закрывающая скобка"},
+		{"2 3", 2, "ожидался оператор"},
+		{"1 $ 2", 2, "неожиданный символ"},
+		// Позиция считается в символах, а не в байтах
+		{"2 × $", 4, "неожиданный символ"},
+		{"1/0", 1, "деление на ноль"},
+		{"foo", 0, "неизвестное имя"},
+		{"sqrt(-1)", 0, "корень из отрицательного числа"},
+		{"0.5!", 3, "факториал"},
+		{"sin()", 0, "принимает аргументов: 1"},
+		{"x = 1 = 2", 6, "присваивание возможно только в начале"},
+		{"pi = 3", 0, "нельзя изменить константу"},
+	}
+	for _, tt := range tests {
+		_, err := Evaluate(tt.in)
+		ce, ok := err.(*CalcError)
+		if !ok {
+			t.Errorf("Evaluate(%q): ожидалась ошибка калькулятора, получено %v", tt.in, err)
+			continue
+		}
+		if ce.Pos != tt.pos || !strings.Contains(ce.Msg, tt.msg) {
+			t.Errorf("Evaluate(%q): ошибка %q в позиции %d; ожидалось %q в позиции %d", tt.in, ce.Msg, ce.Pos, tt.msg, tt.pos)
+		}
+	}
+}
+
+func TestCalculatorVariables(t *testing.T) {
+	c := NewCalculator()
+	for _, step := range []struct {
+		in   string
+		want float64
+	}{
+		{"x = 2 * 3", 6},
+		{"x + 1", 7},
+		{"ans * 2", 14},
+	} {
+		got, err := c.Eval(step.in)
+		if err != nil || got != step.want {
+			t.Fatalf("Eval(%q) = %v, %v; ожидалось %v", step.in, got, err, step.want)
+		}
+	}
+
+	c.Degrees = true
+	if got, err := c.EvalText("sin(30)"); err != nil || got != "0.5" {
+		t.Errorf("sin(30°) = %q, %v", got, err)
+	}
+}
+
+func TestProgrammerPrefixes(t *testing.T) {
+	p := NewProgrammer()
+	tests := []struct {
+		in   string
+		base int
+		want uint64
+	}{
+		{"ff", 16, 255},
+		{"0b1010 + a", 16, 20},
+		{"0x0b1010", 16, 0xb1010},
+		{"b1010", 16, 0xb1010},
+		{"0o17 + 0x10", 2, 31},
+		{"1010", 2, 10},
+	}
+	for _, tt := range tests {
+		got, err := p.EvalBase(tt.in, tt.base)
+		if err != nil || got != tt.want {
+			t.Errorf("EvalBase(%q, %d) = %d, %v; ожидалось %d", tt.in, tt.base, got, err, tt.want)
+		}
+	}
+	if _, err := p.EvalBase("0b12", 16); err == nil || !strings.Contains(err.Error(), "0x0b12") {
+		t.Errorf("EvalBase(0b12, 16): err = %v, ожидалась подсказка 0x0b12", err)
+	}
+	if p.Base != 10 {
+		t.Errorf("EvalBase изменил систему счисления: %d", p.Base)
+	}
+}
+
+func TestProgrammerHistoryText(t *testing.T) {
+	p := NewProgrammer()
+	if err := p.SetBits(8); err != nil {
+		t.Fatal(err)
+	}
+	v, err := p.EvalBase("0 - 5", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := p.HistoryText(v, 16)
+	if text != "-0x5" {
+		t.Fatalf("HistoryText = %q", text)
+	}
+	// Результат из истории читается одинаково в обычном режиме и в режиме программиста
+	if got, err := Evaluate(text); err != nil || got != -5 {
+		t.Errorf("Evaluate(%q) = %v, %v", text, got, err)
+	}
+	if got, err := p.EvalBase(text, 16); err != nil || got != v {
+		t.Errorf("EvalBase(%q) = %d, %v; ожидалось %d", text, got, err, v)
+	}
+
+	p.Signed = false
+	if text := p.HistoryText(v, 16); text != "0xFB" {
+		t.Errorf("беззнаковое HistoryText = %q", text)
+	}
+}
+
+func TestCalcCommandBaseIsPerCommand(t *testing.T) {
+	config := NewConfig(t.TempDir())
+	c := NewConsole(NewFileSystem(config), config)
+
+	if got := c.Execute("calc --hex ff"); !strings.HasPrefix(got, "0xFF") {
+		t.Fatalf("calc --hex ff = %q", got)
+	}
+	if got := c.Execute("calc --bits 8 --unsigned 255 + 1"); !strings.HasPrefix(got, "0 ") {
+		t.Errorf("после --hex система счисления сохранилась: %q", got)
+	}
+}
+
+func TestExactPowLimit(t *testing.T) {
+	c := NewCalculator()
+	c.Exact = true
+	if got, err := c.EvalText("2^100"); err != nil || got != "1267650600228229401496703205376" {
+		t.Errorf("2^100 = %q, %v", got, err)
+	}
+	if _, err := c.EvalText("(1/3)^-2000"); err != nil {
+		t.Errorf("(1/3)^-2000: %v", err)
+	}
+	for _, source := range []string{"(10^1000)^10000", "(1/10^1000)^-10000"} {
+		_, err := c.EvalText(source)
+		if ce, ok := err.(*CalcError); !ok || !strings.Contains(ce.Msg, "переполнение") {
+			t.Errorf("%s: err = %v, ожидалось переполнение", source, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import "time"
+
+// maxCalcHistoryEntries ограничивает размер сохраняемой истории калькулятора
+const maxCalcHistoryEntries = 200
+
+// CalcHistoryEntry - вычисление из истории калькулятора
+type CalcHistoryEntry struct {
+	Expression string    `json:"expression"`
+	Result     string    `json:"result"`
+	Mode       string    `json:"mode"`
+	Time       time.Time `json:"time"`
+}
+
+// CalcHistory хранит историю вычислений текущего пользователя
+// в файловой системе MixailOS
+type CalcHistory struct {
+	FileSystem *FileSystem
+}
+
+// NewCalcHistory создает хранилище истории калькулятора
+func NewCalcHistory(fs *FileSystem) *CalcHistory {
+	return &CalcHistory{
+		FileSystem: fs,
+	}
+}
+
+// Entries возвращает историю от старых вычислений к новым;
+// отсутствующий файл означает пустую историю
+func (h *CalcHistory) Entries() ([]CalcHistoryEntry, error) {
+	var entries []CalcHistoryEntry
+	err := h.FileSystem.loadUserJSON("calculator", "history.json", &entries)
+	return entries, err
+}
+
+// Add добавляет вычисление в историю, удаляя самые старые записи сверх лимита
+func (h *CalcHistory) Add(expression, result, mode string) error {
+	entries, err := h.Entries()
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, CalcHistoryEntry{
+		Expression: expression,
+		Result:     result,
+		Mode:       mode,
+		Time:       time.Now(),
+	})
+	if len(entries) > maxCalcHistoryEntries {
+		entries = entries[len(entries)-maxCalcHistoryEntries:]
+	}
+	return h.save(entries)
+}
+
+// Clear очищает историю
+func (h *CalcHistory) Clear() error {
+	return h.save([]CalcHistoryEntry{})
+}
+
+// save записывает историю, создавая директорию пользователя при необходимости
+func (h *CalcHistory) save(entries []CalcHistoryEntry) error {
+	return h.FileSystem.saveUserJSON("calculator", "history.json", entries)
+}
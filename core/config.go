@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // Config содержит все настройки MixailOS
@@ -13,26 +15,45 @@ type Config struct {
 	Wallpaper   string `json:"wallpaper"`
 	RootDir     string `json:"rootDir"`
 	CurrentDir  string `json:"currentDir"`
+	// DefaultApps - приложения по умолчанию: MIME-тип или шаблон ("image/*") -> ID приложения
 	DefaultApps map[string]string `json:"defaultApps"`
+	// Associations - назначения приложений пользователей: имя пользователя -> MIME-тип -> ID приложения
+	Associations map[string]map[string]string `json:"associations"`
+	// WallpaperMode - режим отображения обоев: scaled, tiled или centered
+	WallpaperMode string `json:"wallpaperMode"`
+	// WallpaperInterval - период смены обоев в минутах; 0 - обои не меняются
+	WallpaperInterval int `json:"wallpaperInterval"`
+	// DesktopMode - приложения открываются окнами на рабочем столе вместо вкладок
+	DesktopMode bool `json:"desktopMode"`
+	// PinnedApps - идентификаторы приложений, закрепленных на панели задач
+	PinnedApps []string `json:"pinnedApps"`
+	// DoNotDisturb - уведомления не всплывают, а только сохраняются в истории
+	DoNotDisturb bool `json:"doNotDisturb"`
+	
+	// Events - шина системных событий; в файл настроек не сохраняется
+	Events *EventBus `json:"-"`
+	
+	// mu упорядочивает изменение и запись настроек: их меняют и интерфейс,
+	// и фоновые горутины (смена обоев по расписанию)
+	mu sync.RWMutex
 }
 
 // NewConfig создает новый экземпляр конфигурации
 func NewConfig(rootDir string) *Config {
 	return &Config{
-		RootDir:    rootDir,
-		CurrentDir: rootDir,
-		DefaultApps: map[string]string{
-			"browser":  "internal",
-			"fileExch": "internal",
-			"calc":     "internal",
-		},
+		RootDir:       rootDir,
+		CurrentDir:    rootDir,
+		WallpaperMode: WallpaperScaled,
+		DefaultApps:   map[string]string{},
+		Events:        NewEventBus(),
 	}
 }
 
 // SetDefault устанавливает значения по умолчанию
 func (c *Config) SetDefault() {
 	c.Username = "User"
-	c.Wallpaper = "default.jpg"
+	c.Wallpaper = DefaultWallpaper
+	c.PinnedApps = []string{"console", "files", "browser"}
 	
 	// Создание необходимых директорий
 	dirs := []string{
@@ -41,6 +62,7 @@ func (c *Config) SetDefault() {
 		filepath.Join(c.RootDir, "Pictures"),
 		filepath.Join(c.RootDir, "Music"),
 		filepath.Join(c.RootDir, "Videos"),
+		filepath.Join(c.RootDir, "Desktop"),
 	}
 	
 	for _, dir := range dirs {
@@ -51,7 +73,7 @@ func (c *Config) SetDefault() {
 	
 	// Создание файла с приветственным сообщением
 	welcomeFile := filepath.Join(c.RootDir, "Documents", "welcome.txt")
-	welcomeText := "Добро пожаловать в MixailOS!\n\nЭто ваша новая операционная система. Чтобы начать, откройте меню и выберите нужное приложение.\n\nДля вызова консоли нажмите Ctrl+T."
+	welcomeText := "Добро пожаловать в MixailOS!\n\nЭто ваша новая операционная система. Чтобы начать, откройте меню «Пуск» на панели задач внизу экрана и выберите нужное приложение.\n\nДля вызова консоли нажмите Ctrl+T."
 	ioutil.WriteFile(welcomeFile, []byte(welcomeText), 0644)
 }
 
@@ -68,31 +90,154 @@ func (c *Config) Load() error {
 		return err
 	}
 	
-	return json.Unmarshal(data, c)
+	if err := json.Unmarshal(data, c); err != nil {
+		return err
+	}
+	
+	// Старые версии указывали несуществующий файл обоев "default.jpg"
+	if c.Wallpaper == "" || c.Wallpaper == "default.jpg" {
+		c.Wallpaper = DefaultWallpaper
+	}
+	
+	// Старые версии хранили в DefaultApps записи вида "browser": "internal";
+	// теперь ключами служат MIME-типы
+	if c.DefaultApps == nil {
+		c.DefaultApps = map[string]string{}
+	}
+	for key := range c.DefaultApps {
+		if !strings.Contains(key, "/") {
+			delete(c.DefaultApps, key)
+		}
+	}
+	return nil
 }
 
 // Save сохраняет конфигурацию в файл
 func (c *Config) Save() error {
-	configPath := filepath.Join(c.RootDir, "config.json")
-	
+	return c.update(func() error { return nil })
+}
+
+// update изменяет настройки функцией change и сохраняет их в файл под блокировкой.
+// Подписчики узнают об изменении после снятия блокировки, поэтому могут сами
+// читать и менять настройки.
+func (c *Config) update(change func() error) error {
+	c.mu.Lock()
+	err := change()
+	if err == nil {
+		err = c.write()
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.Events.Publish(Event{Type: EventConfigChanged})
+	return nil
+}
+
+// write записывает настройки в файл config.json
+func (c *Config) write() error {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
 	}
-	
-	return ioutil.WriteFile(configPath, data, 0644)
+	return ioutil.WriteFile(filepath.Join(c.RootDir, "config.json"), data, 0644)
 }
 
-// ChangeUsername изменяет имя пользователя
+// ChangeUsername изменяет имя пользователя. Данные приложений хранятся отдельно
+// для каждого пользователя, поэтому подписчики получают событие смены пользователя.
 func (c *Config) ChangeUsername(newName string) error {
-	c.Username = newName
-	return c.Save()
+	if newName == c.GetUsername() {
+		return nil
+	}
+	err := c.update(func() error {
+		c.Username = newName
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.Events.Publish(Event{Type: EventUserSwitched, User: newName})
+	return nil
+}
+
+// GetUsername возвращает имя текущего пользователя
+func (c *Config) GetUsername() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Username
 }
 
 // ChangeWallpaper изменяет обои рабочего стола
 func (c *Config) ChangeWallpaper(wallpaperPath string) error {
-	c.Wallpaper = wallpaperPath
-	return c.Save()
+	return c.update(func() error {
+		c.Wallpaper = wallpaperPath
+		return nil
+	})
+}
+
+// GetWallpaper возвращает обои рабочего стола
+func (c *Config) GetWallpaper() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Wallpaper
+}
+
+// GetWallpaperMode возвращает режим отображения обоев
+func (c *Config) GetWallpaperMode() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.WallpaperMode
+}
+
+// GetWallpaperInterval возвращает период смены обоев в минутах
+func (c *Config) GetWallpaperInterval() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.WallpaperInterval
+}
+
+// IsPinned проверяет, закреплено ли приложение на панели задач
+func (c *Config) IsPinned(appID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, id := range c.PinnedApps {
+		if id == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPinnedApps возвращает копию списка приложений, закрепленных на панели задач
+func (c *Config) GetPinnedApps() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.PinnedApps...)
+}
+
+// PinApp закрепляет приложение на панели задач
+func (c *Config) PinApp(appID string) error {
+	if c.IsPinned(appID) {
+		return nil
+	}
+	return c.update(func() error {
+		c.PinnedApps = append(c.PinnedApps, appID)
+		return nil
+	})
+}
+
+// UnpinApp открепляет приложение от панели задач
+func (c *Config) UnpinApp(appID string) error {
+	return c.update(func() error {
+		pinned := c.PinnedApps[:0]
+		for _, id := range c.PinnedApps {
+			if id != appID {
+				pinned = append(pinned, id)
+			}
+		}
+		c.PinnedApps = pinned
+		return nil
+	})
 }
 
 // GetCurrentDir возвращает текущую директорию
@@ -103,4 +248,20 @@ func (c *Config) GetCurrentDir() string {
 // SetCurrentDir изменяет текущую директорию
 func (c *Config) SetCurrentDir(path string) {
 	c.CurrentDir = path
-} 
\ No newline at end of file
+}
+
+// UserDataPath возвращает путь внутри MixailOS к данным приложения текущего пользователя,
+// например "/.config/User/browser"
+func (c *Config) UserDataPath(app string) string {
+	// Имя пользователя не должно выводить путь за пределы директории настроек
+	user := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '.' {
+			return '_'
+		}
+		return r
+	}, c.GetUsername())
+	if user == "" {
+		user = "User"
+	}
+	return "/.config/" + user + "/" + app
+}
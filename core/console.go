@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -11,14 +13,39 @@ type Console struct {
 	FileSystem *FileSystem
 	Config     *Config
 	History    []string
+	Processes  *ProcessTable
+	Downloads  *DownloadManager
+	Calculator *Calculator
+	Programmer *Programmer
+	// CalcHistory - история вычислений пользователя, общая для консоли и калькулятора
+	CalcHistory *CalcHistory
+	// Apps - реестр приложений; заполняется графическим интерфейсом
+	Apps *AppRegistry
+	// Notifications - центр уведомлений, общий для команд и приложений
+	Notifications *NotificationCenter
+	
+	// OpenEditor открывает файл во встроенном текстовом редакторе (задается интерфейсом)
+	OpenEditor func(name string) error
+	// LaunchApp запускает приложение и, если path не пуст, открывает в нем файл (задается интерфейсом)
+	LaunchApp func(appID, path string) error
+	// Terminal - терминал, к которому подключена консоль в текстовом режиме (nil в GUI)
+	Terminal Terminal
 }
 
 // NewConsole создает новый экземпляр консоли
 func NewConsole(fs *FileSystem, config *Config) *Console {
+	processes := NewProcessTable()
 	return &Console{
-		FileSystem: fs,
-		Config:     config,
-		History:    []string{},
+		FileSystem:    fs,
+		Config:        config,
+		History:       []string{},
+		Processes:     processes,
+		Downloads:     NewDownloadManager(fs, processes),
+		Calculator:    NewCalculator(),
+		Programmer:    NewProgrammer(),
+		CalcHistory:   NewCalcHistory(fs),
+		Apps:          NewAppRegistry(),
+		Notifications: NewNotificationCenter(config),
 	}
 }
 
@@ -31,66 +58,182 @@ func (c *Console) Execute(cmd string) string {
 		return ""
 	}
 	
-	switch parts[0] {
-	case "help":
-		return c.HelpCommand()
-	case "info":
-		return c.InfoCommand()
-	case "cls":
-		return "clear"
-	case "txt":
-		if len(parts) < 2 {
-			return "Использование: txt [read|write|list] [параметры]"
-		}
-		return c.TextCommand(parts[1], parts[2:])
-	case "cd":
-		if len(parts) < 2 {
-			return fmt.Sprintf("Текущая директория: %s", c.Config.CurrentDir)
-		}
-		return c.CdCommand(parts[1])
-	case "ls":
-		return c.LsCommand()
-	case "mkdir":
-		if len(parts) < 2 {
-			return "Использование: mkdir <имя_директории>"
-		}
-		return c.MkdirCommand(parts[1])
-	case "rm":
-		if len(parts) < 2 {
-			return "Использование: rm <имя_файла>"
-		}
-		return c.RmCommand(parts[1])
-	case "cp":
-		if len(parts) < 3 {
-			return "Использование: cp <исходный_файл> <файл_назначения>"
-		}
-		return c.CpCommand(parts[1], parts[2])
-	case "echo":
-		return strings.Join(parts[1:], " ")
-	case "date":
-		return time.Now().Format("2006-01-02 15:04:05")
-	default:
+	// Регистрируем команду в таблице процессов на время выполнения
+	pid := c.Processes.Start(parts[0], ProcessCommand, c.Config.GetUsername(), nil)
+	defer c.Processes.Exit(pid)
+	
+	info := findCommand(parts[0])
+	if info == nil {
 		return fmt.Sprintf("Неизвестная команда: %s. Введите 'help' для получения списка команд.", parts[0])
 	}
+	args := parts[1:]
+	if len(args) < info.MinArgs {
+		hint := info.Hint
+		if hint == "" {
+			hint = info.Usage
+		}
+		return "Использование: " + hint
+	}
+	return info.Run(c, args)
+}
+
+// CommandInfo описывает команду консоли: справку и обработчик
+type CommandInfo struct {
+	Name        string
+	Usage       string
+	Description string
+	// Details - описание подкоманд
+	Details []string
+	// MinArgs - минимальное число аргументов; если их меньше, выводится подсказка
+	MinArgs int
+	// Hint - подсказка при нехватке аргументов (по умолчанию Usage)
+	Hint string
+	// Run выполняет команду с аргументами args
+	Run func(c *Console, args []string) string
+}
+
+// Commands - реестр команд консоли в порядке вывода в help и mixail://help.
+// По нему же Execute находит обработчик команды.
+var Commands []CommandInfo
+
+// Реестр заполняется в init, потому что команда help сама обращается к Commands
+func init() {
+	Commands = []CommandInfo{
+		{Name: "help", Usage: "help", Description: "показать список команд",
+			Run: func(c *Console, args []string) string { return c.HelpCommand() }},
+		{Name: "info", Usage: "info", Description: "показать информацию о системе",
+			Run: func(c *Console, args []string) string { return c.InfoCommand() }},
+		{Name: "cls", Usage: "cls", Description: "очистить экран консоли",
+			Run: func(c *Console, args []string) string { return "clear" }},
+		{Name: "txt", Usage: "txt", Description: "работа с текстовыми файлами", Details: []string{
+			"txt read <имя_файла> - чтение файла",
+			"txt write <имя_файла> <содержимое> - запись в файл",
+			"txt list - список текстовых файлов",
+		}, MinArgs: 1, Hint: "txt [read|write|list] [параметры]",
+			Run: func(c *Console, args []string) string { return c.TextCommand(args[0], args[1:]) }},
+		{Name: "cd", Usage: "cd <путь>", Description: "изменить текущую директорию",
+			Run: func(c *Console, args []string) string {
+				if len(args) == 0 {
+					return fmt.Sprintf("Текущая директория: %s", c.Config.CurrentDir)
+				}
+				return c.CdCommand(args[0])
+			}},
+		{Name: "ls", Usage: "ls", Description: "показать содержимое директории", Details: []string{
+			"ls [путь] - имена файлов; директории отмечены символом /",
+			"ls -l [путь] - права, владелец, размер, дата изменения и тип файлов",
+		}, Run: (*Console).LsCommand},
+		{Name: "mkdir", Usage: "mkdir <имя>", Description: "создать новую директорию",
+			MinArgs: 1, Hint: "mkdir <имя_директории>",
+			Run: func(c *Console, args []string) string { return c.MkdirCommand(args[0]) }},
+		{Name: "rm", Usage: "rm <имя>", Description: "удалить файл",
+			MinArgs: 1, Hint: "rm <имя_файла>",
+			Run: func(c *Console, args []string) string { return c.RmCommand(args[0]) }},
+		{Name: "cp", Usage: "cp <источник> <назначение>", Description: "копировать файл",
+			MinArgs: 2, Hint: "cp <исходный_файл> <файл_назначения>",
+			Run: func(c *Console, args []string) string { return c.CpCommand(args[0], args[1]) }},
+		{Name: "echo", Usage: "echo <текст>", Description: "вывести текст",
+			Run: func(c *Console, args []string) string { return strings.Join(args, " ") }},
+		{Name: "date", Usage: "date", Description: "показать текущую дату и время",
+			Run: func(c *Console, args []string) string { return time.Now().Format("2006-01-02 15:04:05") }},
+		{Name: "calc", Usage: "calc", Description: "калькулятор", Details: []string{
+			"calc <выражение> - вычислить выражение, например calc (2 + 3) * 4 ^ 2",
+			"calc x = <выражение> - сохранить результат в переменную x; ans - последний результат",
+			"calc --deg | --rad [выражение] - тригонометрия в градусах или радианах",
+			"calc vars - список переменных",
+			"calc history - история вычислений; calc history clear - очистить историю",
+			"calc --exact | --float [выражение] - точный режим (дроби math/big, 1/3 без округления) или обычный float64",
+			"calc --precision N [выражение] - точность точного режима: N знаков после запятой",
+			"calc --hex | --dec | --oct | --bin <выражение> - режим программиста: целые числа в выбранной системе счисления",
+			"calc --bits 8|16|32|64, --signed, --unsigned - размер слова и знаковость в режиме программиста",
+			"битовые операции: & | xor ~ << >>, остаток: mod; числа с префиксом: 0xFF, 0o17, 0b1010 (префикс важнее режима: в --hex 0b1010 - двоичное число)",
+			"функции: sin, cos, tan, asin, acos, atan, log, ln, exp, sqrt, abs, fact; константы: pi, e; факториал: 5!",
+		}, MinArgs: 1, Hint: "calc [--deg|--rad] [--exact|--float] [--precision N] [--hex|--dec|--oct|--bin] [--bits N] [--signed|--unsigned] <выражение> | calc vars | calc history [clear]",
+			Run: (*Console).CalcCommand},
+		{Name: "convert", Usage: "convert <значение> <из> <в>", Description: "перевести величину в другие единицы, например convert 10 km mi", Details: []string{
+			"convert - список единиц: длина, масса, температура, данные, время",
+			"единицы данных различаются регистром: MB - мегабайт, B - байт",
+		}, Run: (*Console).ConvertCommand},
+		{Name: "edit", Usage: "edit <имя_файла>", Description: "открыть файл в текстовом редакторе",
+			MinArgs: 1, Run: func(c *Console, args []string) string { return c.EditCommand(args[0]) }},
+		{Name: "bookmarks", Usage: "bookmarks", Description: "закладки браузера", Details: []string{
+			"bookmarks list - список закладок",
+			"bookmarks add <адрес> [название] - добавить закладку",
+			"bookmarks rm <номер|адрес> - удалить закладку",
+		}, Run: func(c *Console, args []string) string {
+			if len(args) == 0 {
+				return c.BookmarksCommand("list", nil)
+			}
+			return c.BookmarksCommand(args[0], args[1:])
+		}},
+		{Name: "start", Usage: "start [приложение] [файл]", Description: "запустить приложение; без параметров - список приложений",
+			Run: (*Console).StartCommand},
+		{Name: "open", Usage: "open <файл>", Description: "открыть файл в приложении по умолчанию для его типа",
+			MinArgs: 1, Run: func(c *Console, args []string) string { return c.OpenCommand(strings.Join(args, " ")) }},
+		{Name: "assoc", Usage: "assoc", Description: "приложения для типов файлов", Details: []string{
+			"assoc - список назначений",
+			"assoc <тип|.расширение> <приложение> - открывать файлы типа в приложении",
+			"assoc -d <тип|.расширение> - удалить назначение",
+		}, Run: (*Console).AssocCommand},
+		{Name: "notify", Usage: "notify", Description: "уведомления", Details: []string{
+			"notify [--success|--warning|--error] <заголовок>[: текст] - показать уведомление",
+			"notify list - история уведомлений",
+			"notify clear - очистить историю",
+			"notify dnd [on|off] - режим «Не беспокоить»",
+		}, Run: (*Console).NotifyCommand},
+		{Name: "nano", Usage: "nano [имя_файла]", Description: "полноэкранный редактор (в терминальном режиме)",
+			Run: func(c *Console, args []string) string {
+				name := ""
+				if len(args) > 0 {
+					name = args[0]
+				}
+				return c.NanoCommand(name)
+			}},
+		{Name: "ps", Usage: "ps", Description: "показать список процессов",
+			Run: func(c *Console, args []string) string { return c.PsCommand() }},
+		{Name: "kill", Usage: "kill <pid>", Description: "завершить процесс",
+			MinArgs: 1, Run: func(c *Console, args []string) string { return c.KillCommand(args[0]) }},
+		{Name: "wget", Usage: "wget [--sum алгоритм:значение] <адрес> [имя]", Description: "скачать файл в /Downloads",
+			MinArgs: 1, Hint: "wget [--sum алгоритм:значение] <адрес> [имя_файла]",
+			Run: (*Console).WgetCommand},
+		{Name: "download", Usage: "download", Description: "менеджер загрузок", Details: []string{
+			"download list - список загрузок",
+			"download <адрес> [имя] [--sum алгоритм:значение] - добавить загрузку в очередь",
+			"download cancel <номер> - отменить загрузку",
+			"download retry <номер> - возобновить загрузку",
+		}, Run: func(c *Console, args []string) string {
+			if len(args) == 0 {
+				return c.DownloadCommand("list", nil)
+			}
+			return c.DownloadCommand(args[0], args[1:])
+		}},
+	}
+}
+
+// findCommand возвращает описание команды по имени или nil, если команды нет
+func findCommand(name string) *CommandInfo {
+	for i := range Commands {
+		if Commands[i].Name == name {
+			return &Commands[i]
+		}
+	}
+	return nil
 }
 
 // HelpCommand возвращает справку по командам
 func (c *Console) HelpCommand() string {
-	return `Доступные команды:
-help - показать список команд
-info - показать информацию о системе
-cls - очистить экран консоли
-txt - работа с текстовыми файлами:
-  - txt read <имя_файла> - чтение файла
-  - txt write <имя_файла> <содержимое> - запись в файл
-  - txt list - список текстовых файлов
-cd <путь> - изменить текущую директорию
-ls - показать содержимое текущей директории
-mkdir <имя> - создать новую директорию
-rm <имя> - удалить файл
-cp <источник> <назначение> - копировать файл
-echo <текст> - вывести текст
-date - показать текущую дату и время`
+	var sb strings.Builder
+	sb.WriteString("Доступные команды:")
+	for _, cmd := range Commands {
+		sb.WriteString("\n" + cmd.Usage + " - " + cmd.Description)
+		if len(cmd.Details) > 0 {
+			sb.WriteString(":")
+		}
+		for _, detail := range cmd.Details {
+			sb.WriteString("\n  - " + detail)
+		}
+	}
+	sb.WriteString("\n\nПодробная справка доступна в браузере по адресу mixail://help")
+	return sb.String()
 }
 
 // InfoCommand возвращает информацию о системе
@@ -101,7 +244,7 @@ func (c *Console) InfoCommand() string {
 Текущая директория: %s
 Версия: 1.0.0
 Дата запуска: %s`, 
-		c.Config.Username,
+		c.Config.GetUsername(),
 		c.Config.RootDir,
 		c.Config.CurrentDir,
 		time.Now().Format("2006-01-02 15:04:05"))
@@ -139,8 +282,8 @@ func (c *Console) TextCommand(action string, args []string) string {
 		
 		var textFiles []string
 		for _, file := range files {
-			if strings.Contains(file, ".txt") {
-				textFiles = append(textFiles, file)
+			if !file.IsDir && strings.HasSuffix(file.Name, ".txt") {
+				textFiles = append(textFiles, file.Name)
 			}
 		}
 		
@@ -164,9 +307,20 @@ func (c *Console) CdCommand(path string) string {
 	return fmt.Sprintf("Текущая директория: %s", c.Config.CurrentDir)
 }
 
-// LsCommand показывает содержимое текущей директории
-func (c *Console) LsCommand() string {
-	files, err := c.FileSystem.ListFiles()
+// LsCommand показывает содержимое текущей или указанной директории.
+// С флагом -l для каждого файла выводятся права, владелец, размер, дата изменения и тип.
+func (c *Console) LsCommand(args []string) string {
+	long := false
+	dir := c.Config.CurrentDir
+	for _, arg := range args {
+		if arg == "-l" {
+			long = true
+		} else {
+			dir = arg
+		}
+	}
+	
+	files, err := c.FileSystem.ListDir(dir)
 	if err != nil {
 		return fmt.Sprintf("Ошибка при получении списка файлов: %v", err)
 	}
@@ -175,7 +329,29 @@ func (c *Console) LsCommand() string {
 		return "Директория пуста"
 	}
 	
-	return fmt.Sprintf("Содержимое директории %s:\n%s", c.Config.CurrentDir, strings.Join(files, "\n"))
+	lines := make([]string, len(files))
+	for i, f := range files {
+		name := f.Name
+		if f.IsDir {
+			name += "/"
+		}
+		if !long {
+			lines[i] = name
+			continue
+		}
+		size, owner := "-", f.Owner
+		if !f.IsDir {
+			size = FormatSize(f.Size)
+		}
+		if owner == "" {
+			owner = "-"
+		}
+		lines[i] = fmt.Sprintf("%s  %-10s %10s  %s  %-24s %s",
+			f.Mode, owner, size, f.ModTime.Format("02.01.2006 15:04"), f.MIMEType, name)
+	}
+	
+	path, _ := c.FileSystem.ResolvePath(dir)
+	return fmt.Sprintf("Содержимое директории %s:\n%s", path, strings.Join(lines, "\n"))
 }
 
 // MkdirCommand создает новую директорию
@@ -203,4 +379,641 @@ func (c *Console) CpCommand(src, dst string) string {
 		return fmt.Sprintf("Ошибка при копировании файла: %v", err)
 	}
 	return fmt.Sprintf("Файл %s успешно скопирован в %s", src, dst)
-} 
\ No newline at end of file
+}
+
+// PsCommand показывает список запущенных процессов
+func (c *Console) PsCommand() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-5s %-12s %-12s %-10s %-9s %s\n", "PID", "ТИП", "СОСТОЯНИЕ", "ВЛАДЕЛЕЦ", "ЗАПУЩЕН", "ИМЯ"))
+	for _, p := range c.Processes.List() {
+		sb.WriteString(fmt.Sprintf("%-5d %-12s %-12s %-10s %-9s %s\n",
+			p.PID, p.Kind, p.State, p.Owner, p.StartTime.Format("15:04:05"), p.Name))
+	}
+	
+	stats := ReadResourceStats()
+	sb.WriteString(fmt.Sprintf("Горутины: %d, куча: %s", stats.Goroutines, FormatSize(int64(stats.HeapAlloc))))
+	return sb.String()
+}
+
+// KillCommand завершает процесс по PID
+func (c *Console) KillCommand(arg string) string {
+	pid, err := strconv.Atoi(arg)
+	if err != nil {
+		return fmt.Sprintf("Некорректный PID: %s", arg)
+	}
+	if err := c.Processes.Kill(pid); err != nil {
+		return fmt.Sprintf("Ошибка при завершении процесса: %v", err)
+	}
+	return fmt.Sprintf("Процесс %d завершен", pid)
+}
+
+// EditCommand открывает файл во встроенном текстовом редакторе
+func (c *Console) EditCommand(name string) string {
+	if c.OpenEditor == nil {
+		// В терминальном режиме используем полноэкранный редактор
+		if c.Terminal != nil {
+			return c.NanoCommand(name)
+		}
+		return "Текстовый редактор недоступен"
+	}
+	if err := c.OpenEditor(name); err != nil {
+		return fmt.Sprintf("Ошибка при открытии редактора: %v", err)
+	}
+	return fmt.Sprintf("Файл %s открыт в редакторе", name)
+}
+
+// StartCommand запускает приложение по идентификатору или названию.
+// Без параметров выводит список приложений.
+func (c *Console) StartCommand(args []string) string {
+	apps := c.Apps.List()
+	if len(args) == 0 {
+		if len(apps) == 0 {
+			return "Приложения доступны только в графическом интерфейсе"
+		}
+		lines := []string{"Приложения:"}
+		for _, app := range apps {
+			lines = append(lines, fmt.Sprintf("  %-12s %s (%s)", app.ID, app.Name, app.Category))
+		}
+		return strings.Join(lines, "\n")
+	}
+	if c.LaunchApp == nil {
+		return "Запуск приложений доступен только в графическом интерфейсе"
+	}
+	
+	app, ok := c.Apps.Find(args[0])
+	if !ok {
+		return fmt.Sprintf("Приложение не найдено: %s. Введите 'start' для списка приложений.", args[0])
+	}
+	path := ""
+	if len(args) > 1 {
+		var err error
+		if path, err = c.filePath(strings.Join(args[1:], " ")); err != nil {
+			return fmt.Sprintf("Ошибка: %v", err)
+		}
+	}
+	if err := c.Launch(app.ID, path); err != nil {
+		return fmt.Sprintf("Ошибка при запуске %s: %v", app.Name, err)
+	}
+	return fmt.Sprintf("Запущено приложение %s", app.Name)
+}
+
+// Launch запускает приложение и, если path не пуст, открывает в нем файл.
+// О запуске сообщается подписчикам шины событий.
+func (c *Console) Launch(appID, path string) error {
+	if c.LaunchApp == nil {
+		return fmt.Errorf("запуск приложений доступен только в графическом интерфейсе")
+	}
+	if err := c.LaunchApp(appID, path); err != nil {
+		return err
+	}
+	c.Config.Events.Publish(Event{Type: EventAppLaunched, AppID: appID, Path: path})
+	return nil
+}
+
+// OpenCommand открывает файл в приложении, назначенном для его типа
+func (c *Console) OpenCommand(name string) string {
+	if c.LaunchApp == nil {
+		return "Открытие файлов в приложениях доступно только в графическом интерфейсе"
+	}
+	path, err := c.filePath(name)
+	if err != nil {
+		return fmt.Sprintf("Ошибка: %v", err)
+	}
+	
+	mimeType := c.FileSystem.MIMEType(path)
+	app, err := c.Apps.DefaultFor(mimeType, c.Config)
+	if err != nil {
+		return fmt.Sprintf("Ошибка: %v", err)
+	}
+	if err := c.Launch(app.ID, path); err != nil {
+		return fmt.Sprintf("Ошибка при открытии %s: %v", name, err)
+	}
+	return fmt.Sprintf("%s (%s) открыт в приложении %s", path, mimeType, app.Name)
+}
+
+// AssocCommand показывает и изменяет приложения, назначенные пользователем для типов файлов
+func (c *Console) AssocCommand(args []string) string {
+	if len(args) == 0 {
+		var lines []string
+		for _, a := range c.Config.UserAssociations() {
+			lines = append(lines, fmt.Sprintf("  %-24s %s", a.MIMEType, c.appName(a.AppID)))
+		}
+		if len(lines) == 0 {
+			return "Назначений нет: файлы открываются в первом подходящем приложении"
+		}
+		return "Назначения пользователя " + c.Config.GetUsername() + ":\n" + strings.Join(lines, "\n")
+	}
+	
+	if args[0] == "-d" {
+		if len(args) != 2 {
+			return "Использование: assoc -d <тип|.расширение>"
+		}
+		mimeType, err := assocType(args[1])
+		if err != nil {
+			return fmt.Sprintf("Ошибка: %v", err)
+		}
+		if err := c.Config.RemoveAssociation(mimeType); err != nil {
+			return fmt.Sprintf("Ошибка: %v", err)
+		}
+		return fmt.Sprintf("Назначение для %s удалено", mimeType)
+	}
+	
+	if len(args) != 2 {
+		return "Использование: assoc <тип|.расширение> <приложение>"
+	}
+	mimeType, err := assocType(args[0])
+	if err != nil {
+		return fmt.Sprintf("Ошибка: %v", err)
+	}
+	app, ok := c.Apps.Find(args[1])
+	if !ok {
+		return fmt.Sprintf("Приложение не найдено: %s", args[1])
+	}
+	if err := c.Config.SetAssociation(mimeType, app); err != nil {
+		return fmt.Sprintf("Ошибка: %v", err)
+	}
+	return fmt.Sprintf("Файлы %s будут открываться в приложении %s", mimeType, app.Name)
+}
+
+// assocType преобразует аргумент команды assoc в MIME-тип: расширение ".png"
+// заменяется типом, тип "image/png" или шаблон "image/*" остается как есть
+func assocType(arg string) (string, error) {
+	if strings.HasPrefix(arg, ".") {
+		if mimeType := MIMETypeByExtension(arg); mimeType != "" {
+			return mimeType, nil
+		}
+		return "", fmt.Errorf("неизвестное расширение: %s", arg)
+	}
+	if !strings.Contains(arg, "/") {
+		return "", fmt.Errorf("некорректный тип файлов: %s", arg)
+	}
+	return strings.ToLower(arg), nil
+}
+
+// appName возвращает название приложения или его идентификатор, если оно не зарегистрировано
+func (c *Console) appName(id string) string {
+	if app, ok := c.Apps.Get(id); ok {
+		return app.Name
+	}
+	return id
+}
+
+// NotifyCommand публикует уведомления и управляет их историей
+func (c *Console) NotifyCommand(args []string) string {
+	nc := c.Notifications
+	if len(args) == 0 {
+		return "Использование: notify [--success|--warning|--error] <заголовок>[: текст] | list | clear | dnd [on|off]"
+	}
+	
+	switch args[0] {
+	case "list":
+		history := nc.History()
+		if len(history) == 0 {
+			return "Уведомлений нет"
+		}
+		var lines []string
+		for _, n := range history {
+			line := fmt.Sprintf("%s [%s] %s", n.Time.Format("15:04:05"), n.Severity, n.Title)
+			if n.Body != "" {
+				line += ": " + n.Body
+			}
+			lines = append(lines, line)
+		}
+		nc.MarkAllRead()
+		return strings.Join(lines, "\n")
+	case "clear":
+		nc.Clear()
+		return "История уведомлений очищена"
+	case "dnd":
+		if len(args) == 1 {
+			if nc.DoNotDisturb() {
+				return "Режим «Не беспокоить» включен"
+			}
+			return "Режим «Не беспокоить» выключен"
+		}
+		var enabled bool
+		switch args[1] {
+		case "on":
+			enabled = true
+		case "off":
+			enabled = false
+		default:
+			return "Использование: notify dnd [on|off]"
+		}
+		if err := nc.SetDoNotDisturb(enabled); err != nil {
+			return fmt.Sprintf("Ошибка: %v", err)
+		}
+		if enabled {
+			return "Режим «Не беспокоить» включен: уведомления сохраняются в истории без показа"
+		}
+		return "Режим «Не беспокоить» выключен"
+	}
+	
+	severity := SeverityInfo
+	if strings.HasPrefix(args[0], "--") {
+		s, ok := ParseSeverity(strings.TrimPrefix(args[0], "--"))
+		if !ok {
+			return fmt.Sprintf("Неизвестный уровень уведомления: %s", args[0])
+		}
+		severity = s
+		args = args[1:]
+	}
+	text := strings.Join(args, " ")
+	if text == "" {
+		return "Укажите заголовок уведомления"
+	}
+	title, body := text, ""
+	if i := strings.Index(text, ":"); i > 0 {
+		title, body = strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+	}
+	id := nc.Notify(title, body, severity)
+	return fmt.Sprintf("Уведомление %d отправлено", id)
+}
+
+// filePath проверяет, что файл существует, и возвращает его путь внутри MixailOS
+func (c *Console) filePath(name string) (string, error) {
+	path, err := c.FileSystem.ResolvePath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("файл не найден: %s", name)
+	}
+	return c.FileSystem.VirtualPath(path), nil
+}
+
+// NanoCommand запускает полноэкранный редактор в терминале
+func (c *Console) NanoCommand(name string) string {
+	if c.Terminal == nil {
+		return "nano доступен только в терминальном режиме (запустите MixailOS с флагом -headless)"
+	}
+	
+	nano := NewNano(c.FileSystem)
+	if name != "" {
+		if err := nano.Open(name); err != nil {
+			return fmt.Sprintf("Ошибка при открытии файла: %v", err)
+		}
+	}
+	
+	restore, err := c.Terminal.MakeRaw()
+	if err != nil {
+		return fmt.Sprintf("Ошибка при настройке терминала: %v", err)
+	}
+	err = nano.Run(c.Terminal)
+	restore()
+	
+	if err != nil {
+		return fmt.Sprintf("Ошибка редактора: %v", err)
+	}
+	return ""
+}
+
+// BookmarksCommand управляет закладками браузера
+func (c *Console) BookmarksCommand(action string, args []string) string {
+	store := NewBrowserStore(c.FileSystem)
+	
+	switch action {
+	case "list":
+		bookmarks, err := store.Bookmarks()
+		if err != nil {
+			return fmt.Sprintf("Ошибка при чтении закладок: %v", err)
+		}
+		if len(bookmarks) == 0 {
+			return "Закладок нет"
+		}
+		
+		lines := make([]string, len(bookmarks))
+		for i, b := range bookmarks {
+			lines[i] = fmt.Sprintf("%d. %s - %s", i+1, b.Title, b.URL)
+		}
+		return fmt.Sprintf("Закладки:\n%s", strings.Join(lines, "\n"))
+		
+	case "add":
+		if len(args) < 1 {
+			return "Использование: bookmarks add <адрес> [название]"
+		}
+		address, err := NormalizeURL(args[0])
+		if err != nil {
+			return fmt.Sprintf("Ошибка при добавлении закладки: %v", err)
+		}
+		if err := store.AddBookmark(strings.Join(args[1:], " "), address); err != nil {
+			return fmt.Sprintf("Ошибка при добавлении закладки: %v", err)
+		}
+		return fmt.Sprintf("Закладка %s добавлена", address)
+		
+	case "rm":
+		if len(args) < 1 {
+			return "Использование: bookmarks rm <номер|адрес>"
+		}
+		if err := store.RemoveBookmark(args[0]); err != nil {
+			return fmt.Sprintf("Ошибка при удалении закладки: %v", err)
+		}
+		return fmt.Sprintf("Закладка %s удалена", args[0])
+		
+	default:
+		return fmt.Sprintf("Неизвестное действие для bookmarks: %s", action)
+	}
+}
+
+// WgetCommand скачивает файл в /Downloads. В терминальном режиме дожидается
+// окончания загрузки и показывает прогресс, в GUI ставит загрузку в очередь.
+func (c *Console) WgetCommand(args []string) string {
+	address, name, checksum, err := parseDownloadArgs(args)
+	if err != nil {
+		return err.Error()
+	}
+	
+	id, err := c.Downloads.Add(address, name, checksum)
+	if err != nil {
+		return fmt.Sprintf("Ошибка при загрузке: %v", err)
+	}
+	if c.Terminal == nil {
+		d, _ := c.Downloads.Get(id)
+		return fmt.Sprintf("Загрузка %d добавлена в очередь: %s", id, d.Path())
+	}
+	
+	d, err := c.Downloads.Wait(id, func(d Download) {
+		fmt.Fprintf(c.Terminal, "\r\x1b[K%s", formatDownloadProgress(d))
+	})
+	if err != nil {
+		return fmt.Sprintf("Ошибка при загрузке: %v", err)
+	}
+	fmt.Fprint(c.Terminal, "\r\x1b[K")
+	return formatDownloadResult(d)
+}
+
+// DownloadCommand управляет менеджером загрузок
+func (c *Console) DownloadCommand(action string, args []string) string {
+	switch action {
+	case "list":
+		downloads := c.Downloads.List()
+		if len(downloads) == 0 {
+			return "Загрузок нет"
+		}
+		
+		lines := make([]string, len(downloads))
+		for i, d := range downloads {
+			lines[i] = formatDownloadProgress(d)
+			if d.Err != nil {
+				lines[i] += fmt.Sprintf(" (%v)", d.Err)
+			}
+		}
+		return fmt.Sprintf("Загрузки:\n%s", strings.Join(lines, "\n"))
+		
+	case "cancel", "retry":
+		if len(args) < 1 {
+			return fmt.Sprintf("Использование: download %s <номер>", action)
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Sprintf("Некорректный номер загрузки: %s", args[0])
+		}
+		
+		if action == "cancel" {
+			if err := c.Downloads.Cancel(id); err != nil {
+				return fmt.Sprintf("Ошибка при отмене загрузки: %v", err)
+			}
+			return fmt.Sprintf("Загрузка %d отменена", id)
+		}
+		if err := c.Downloads.Retry(id); err != nil {
+			return fmt.Sprintf("Ошибка при возобновлении загрузки: %v", err)
+		}
+		return fmt.Sprintf("Загрузка %d возобновлена", id)
+		
+	default:
+		// download <адрес> ставит загрузку в очередь, не дожидаясь ее окончания
+		address, name, checksum, err := parseDownloadArgs(append([]string{action}, args...))
+		if err != nil {
+			return err.Error()
+		}
+		id, err := c.Downloads.Add(address, name, checksum)
+		if err != nil {
+			return fmt.Sprintf("Ошибка при загрузке: %v", err)
+		}
+		d, _ := c.Downloads.Get(id)
+		return fmt.Sprintf("Загрузка %d добавлена в очередь: %s", id, d.Path())
+	}
+}
+
+// parseDownloadArgs разбирает аргументы вида [--sum алгоритм:значение] <адрес> [имя_файла]
+func parseDownloadArgs(args []string) (address, name, checksum string, err error) {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--sum" {
+			if i+1 >= len(args) {
+				return "", "", "", fmt.Errorf("после --sum нужно указать контрольную сумму")
+			}
+			checksum = args[i+1]
+			i++
+			continue
+		}
+		positional = append(positional, args[i])
+	}
+	
+	if len(positional) == 0 {
+		return "", "", "", fmt.Errorf("не указан адрес для загрузки")
+	}
+	address = positional[0]
+	if len(positional) > 1 {
+		name = positional[1]
+	}
+	return address, name, checksum, nil
+}
+
+// formatDownloadProgress возвращает строку с состоянием загрузки
+func formatDownloadProgress(d Download) string {
+	size := FormatSize(d.Received)
+	if d.Total >= 0 {
+		size = fmt.Sprintf("%s из %s (%.0f%%)", FormatSize(d.Received), FormatSize(d.Total), d.Progress()*100)
+	}
+	return fmt.Sprintf("%d. %s [%s] %s", d.ID, d.Name, d.State, size)
+}
+
+// formatDownloadResult возвращает итог завершившейся загрузки
+func formatDownloadResult(d Download) string {
+	switch d.State {
+	case DownloadDone:
+		return fmt.Sprintf("Файл сохранен: %s (%s)", d.Path(), FormatSize(d.Received))
+	case DownloadCancelled:
+		return fmt.Sprintf("Загрузка %d отменена", d.ID)
+	default:
+		return fmt.Sprintf("Ошибка при загрузке: %v", d.Err)
+	}
+}
+
+// CalcCommand вычисляет математическое выражение. Переменные и ans сохраняются между вызовами.
+// Параметры --hex, --dec, --oct, --bin и --bits включают режим программиста.
+func (c *Console) CalcCommand(args []string) string {
+	calc := c.Calculator
+	prog := c.Programmer
+	programmer := false
+	// Система счисления задается для каждой команды отдельно и по умолчанию десятичная
+	base := 10
+	bases := map[string]int{"--hex": 16, "--dec": 10, "--oct": 8, "--bin": 2}
+	
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		switch flag := args[0]; flag {
+		case "--deg":
+			calc.Degrees = true
+		case "--rad":
+			calc.Degrees = false
+		case "--exact", "--float":
+			calc.Exact = flag == "--exact"
+		case "--precision":
+			if len(args) < 2 {
+				return "После --precision нужно указать количество знаков после запятой"
+			}
+			n, err := strconv.Atoi(args[1])
+			if err == nil {
+				err = calc.SetPrecision(n)
+			}
+			if err != nil {
+				return fmt.Sprintf("Некорректная точность %s: %v", args[1], err)
+			}
+			calc.Exact = true
+			args = args[1:]
+		case "--hex", "--dec", "--oct", "--bin":
+			base = bases[flag]
+			programmer = true
+		case "--signed", "--unsigned":
+			prog.Signed = flag == "--signed"
+			programmer = true
+		case "--bits":
+			if len(args) < 2 {
+				return "После --bits нужно указать размер слова: 8, 16, 32 или 64"
+			}
+			n, err := strconv.Atoi(args[1])
+			if err == nil {
+				err = prog.SetBits(n)
+			}
+			if err != nil {
+				return fmt.Sprintf("Некорректный размер слова %s: %v", args[1], err)
+			}
+			args = args[1:]
+			programmer = true
+		default:
+			return fmt.Sprintf("Неизвестный параметр calc: %s", flag)
+		}
+		args = args[1:]
+	}
+	
+	if programmer {
+		return c.programmerCalc(args, base)
+	}
+	if len(args) == 0 {
+		status := "Углы измеряются в радианах"
+		if calc.Degrees {
+			status = "Углы измеряются в градусах"
+		}
+		if calc.Exact {
+			return status + "\n" + fmt.Sprintf("Точный режим: дроби и %d знаков после запятой", calc.Precision)
+		}
+		return status + "\nОбычный режим: числа с плавающей точкой (calc --exact - точный режим)"
+	}
+	if len(args) == 1 && args[0] == "vars" {
+		lines := []string{"ans = " + calc.VarText("ans")}
+		for _, name := range calc.VarNames() {
+			lines = append(lines, name+" = "+calc.VarText(name))
+		}
+		return strings.Join(lines, "\n")
+	}
+	if args[0] == "history" {
+		return c.calcHistory(args[1:])
+	}
+	
+	source := strings.Join(args, " ")
+	text, err := calc.EvalText(source)
+	if err != nil {
+		return FormatCalcError(source, err)
+	}
+	c.CalcHistory.Add(source, text, "Обычный")
+	return text
+}
+
+// calcHistory выводит или очищает историю вычислений
+func (c *Console) calcHistory(args []string) string {
+	if len(args) > 0 {
+		if args[0] != "clear" {
+			return "Использование: calc history [clear]"
+		}
+		if err := c.CalcHistory.Clear(); err != nil {
+			return fmt.Sprintf("Ошибка при очистке истории: %v", err)
+		}
+		return "История вычислений очищена"
+	}
+	
+	entries, err := c.CalcHistory.Entries()
+	if err != nil {
+		return fmt.Sprintf("Ошибка при чтении истории: %v", err)
+	}
+	if len(entries) == 0 {
+		return "История вычислений пуста"
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = fmt.Sprintf("%s  %s = %s", e.Time.Format("02.01 15:04"), e.Expression, e.Result)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// programmerCalc вычисляет выражение в режиме программиста с числами в системе base
+func (c *Console) programmerCalc(args []string, base int) string {
+	prog := c.Programmer
+	sign := "знаковые"
+	if !prog.Signed {
+		sign = "беззнаковые"
+	}
+	mode := fmt.Sprintf("Режим программиста: %d бит, %s, основание %d", prog.Bits, sign, base)
+	if len(args) == 0 {
+		return mode
+	}
+	
+	source := strings.Join(args, " ")
+	v, err := prog.EvalBase(source, base)
+	if err != nil {
+		return FormatCalcError(source, err)
+	}
+	c.CalcHistory.Add(source, prog.HistoryText(v, base), "Программист")
+	if base == 10 {
+		return fmt.Sprintf("%s (%s)", prog.Format(v, 10), prog.FormatPrefixed(v, 16))
+	}
+	return fmt.Sprintf("%s (%s)", prog.FormatPrefixed(v, base), prog.Format(v, 10))
+}
+
+// ConvertCommand переводит величину в другие единицы: convert 10 km mi.
+// Значение может быть выражением без пробелов (convert 2^10 KiB MB).
+// Без аргументов выводит список единиц.
+func (c *Console) ConvertCommand(args []string) string {
+	if len(args) == 0 {
+		var sb strings.Builder
+		sb.WriteString("Единицы измерения:")
+		for _, category := range UnitCategories {
+			var names []string
+			for _, u := range UnitsIn(category) {
+				names = append(names, u.Name)
+			}
+			sb.WriteString("\n" + category + ": " + strings.Join(names, ", "))
+		}
+		return sb.String()
+	}
+	
+	// Допускается запись "convert 10 km to mi" и "convert 10 km в mi"
+	if len(args) == 4 && (args[2] == "to" || args[2] == "в") {
+		args = append(args[:2], args[3])
+	}
+	if len(args) != 3 {
+		return "Использование: convert <значение> <из> <в>, например convert 10 km mi"
+	}
+	
+	value, err := Evaluate(args[0])
+	if err != nil {
+		return FormatCalcError(args[0], err)
+	}
+	result, err := ConvertUnits(value, args[1], args[2])
+	if err != nil {
+		return "Ошибка: " + err.Error()
+	}
+	text := fmt.Sprintf("%s %s = %s %s", FormatNumber(value), args[1], FormatNumber(result), args[2])
+	c.CalcHistory.Add(strings.Join(args, " "), FormatNumber(result), "Конвертер")
+	return text
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"sort"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// DesktopDir - папка, файлы которой показываются значками на рабочем столе
+const DesktopDir = "/Desktop"
+
+// Режимы отображения обоев
+const (
+	// WallpaperScaled - изображение масштабируется с сохранением пропорций и заполняет экран
+	WallpaperScaled = "scaled"
+	// WallpaperTiled - изображение повторяется плиткой в исходном размере
+	WallpaperTiled = "tiled"
+	// WallpaperCentered - изображение в исходном размере по центру экрана
+	WallpaperCentered = "centered"
+)
+
+// WallpaperModes - режимы обоев и их названия в порядке отображения
+var WallpaperModes = []struct {
+	Mode  string
+	Title string
+}{
+	{WallpaperScaled, "Заполнить"},
+	{WallpaperTiled, "Замостить"},
+	{WallpaperCentered, "По центру"},
+}
+
+// desktopBackground - цвет рабочего стола, видимый вокруг обоев или вместо них
+var desktopBackground = color.RGBA{R: 0x1e, G: 0x3a, B: 0x5f, A: 0xff}
+
+// ChangeWallpaperMode изменяет режим отображения обоев
+func (c *Config) ChangeWallpaperMode(mode string) error {
+	for _, m := range WallpaperModes {
+		if m.Mode == mode {
+			return c.update(func() error {
+				c.WallpaperMode = mode
+				return nil
+			})
+		}
+	}
+	return fmt.Errorf("неизвестный режим обоев: %s", mode)
+}
+
+// SetDesktopMode включает рабочий стол с окнами вместо вкладок
+func (c *Config) SetDesktopMode(enabled bool) error {
+	return c.update(func() error {
+		c.DesktopMode = enabled
+		return nil
+	})
+}
+
+// RenderWallpaper рисует обои размером width x height в заданном режиме.
+// Если изображения нет, рабочий стол заливается цветом по умолчанию.
+func RenderWallpaper(src image.Image, mode string, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: desktopBackground}, image.Point{}, draw.Src)
+	if src == nil || width <= 0 || height <= 0 {
+		return dst
+	}
+
+	b := src.Bounds()
+	switch mode {
+	case WallpaperTiled:
+		for y := 0; y < height; y += b.Dy() {
+			for x := 0; x < width; x += b.Dx() {
+				draw.Draw(dst, image.Rect(x, y, x+b.Dx(), y+b.Dy()), src, b.Min, draw.Src)
+			}
+		}
+	case WallpaperCentered:
+		x := (width - b.Dx()) / 2
+		y := (height - b.Dy()) / 2
+		draw.Draw(dst, image.Rect(x, y, x+b.Dx(), y+b.Dy()), src, b.Min, draw.Over)
+	default:
+		// Изображение заполняет экран, выступающие края обрезаются
+		scale := float64(width) / float64(b.Dx())
+		if s := float64(height) / float64(b.Dy()); s > scale {
+			scale = s
+		}
+		w := int(float64(b.Dx())*scale + 0.5)
+		h := int(float64(b.Dy())*scale + 0.5)
+		x := (width - w) / 2
+		y := (height - h) / 2
+		xdraw.ApproxBiLinear.Scale(dst, image.Rect(x, y, x+w, y+h), src, b, draw.Over, nil)
+	}
+	return dst
+}
+
+// DesktopItem - значок файла или папки на рабочем столе
+type DesktopItem struct {
+	Name string
+	// Path - путь внутри MixailOS, например "/Desktop/notes.txt"
+	Path  string
+	IsDir bool
+}
+
+// DesktopItems возвращает содержимое папки рабочего стола: сначала папки, затем файлы.
+// Папка создается, если ее еще нет.
+func (fs *FileSystem) DesktopItems() ([]DesktopItem, error) {
+	if err := fs.CreateDirectories(DesktopDir); err != nil {
+		return nil, err
+	}
+	path, err := fs.ResolvePath(DesktopDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]DesktopItem, 0, len(files))
+	for _, f := range files {
+		if f.Name()[0] == '.' {
+			continue
+		}
+		items = append(items, DesktopItem{
+			Name:  f.Name(),
+			Path:  DesktopDir + "/" + f.Name(),
+			IsDir: f.IsDir(),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].IsDir && !items[j].IsDir
+	})
+	return items, nil
+}
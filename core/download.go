@@ -0,0 +1,596 @@
+package core
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DownloadsDir - директория для загрузок внутри MixailOS
+	DownloadsDir = "/Downloads"
+	// defaultMaxConcurrentDownloads - количество одновременных загрузок по умолчанию
+	defaultMaxConcurrentDownloads = 3
+	// partSuffix - расширение недокачанного файла
+	partSuffix = ".part"
+	// partURLSuffix - расширение скрытого файла рядом с недокачанным, в котором
+	// хранится адрес загрузки: докачивать можно только тот же файл
+	partURLSuffix = ".url"
+	// progressInterval - как часто сообщать о ходе загрузки
+	progressInterval = 200 * time.Millisecond
+)
+
+// DownloadState описывает состояние загрузки
+type DownloadState string
+
+const (
+	// DownloadQueued - загрузка ожидает свободного места в очереди
+	DownloadQueued DownloadState = "в очереди"
+	// DownloadActive - файл загружается
+	DownloadActive DownloadState = "загружается"
+	// DownloadVerifying - проверяется контрольная сумма
+	DownloadVerifying DownloadState = "проверка"
+	// DownloadDone - загрузка завершена
+	DownloadDone DownloadState = "завершена"
+	// DownloadFailed - загрузка завершилась ошибкой
+	DownloadFailed DownloadState = "ошибка"
+	// DownloadCancelled - загрузка отменена пользователем
+	DownloadCancelled DownloadState = "отменена"
+)
+
+// Download описывает одну загрузку
+type Download struct {
+	ID       int
+	URL      string
+	Name     string
+	Checksum string
+	State    DownloadState
+	Received int64
+	// Total - полный размер файла или -1, если сервер его не сообщил
+	Total   int64
+	Resumed bool
+	Err     error
+	Started time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Path возвращает путь к загруженному файлу внутри MixailOS
+func (d Download) Path() string {
+	return DownloadsDir + "/" + d.Name
+}
+
+// Progress возвращает долю загруженного от 0 до 1 или -1, если размер неизвестен
+func (d Download) Progress() float64 {
+	if d.Total <= 0 {
+		if d.State == DownloadDone {
+			return 1
+		}
+		return -1
+	}
+	return float64(d.Received) / float64(d.Total)
+}
+
+// Finished сообщает, завершилась ли загрузка (успешно или нет)
+func (d Download) Finished() bool {
+	return d.State == DownloadDone || d.State == DownloadFailed || d.State == DownloadCancelled
+}
+
+// DownloadManager управляет очередью загрузок в директорию Downloads
+type DownloadManager struct {
+	FileSystem *FileSystem
+	Client     *http.Client
+	Processes  *ProcessTable
+
+	// OnChange вызывается при каждом изменении состояния или прогресса загрузки
+	OnChange func(d Download)
+
+	mu        sync.Mutex
+	nextID    int
+	downloads []*Download
+	slots     chan struct{}
+}
+
+// NewDownloadManager создает менеджер загрузок
+func NewDownloadManager(fs *FileSystem, processes *ProcessTable) *DownloadManager {
+	return &DownloadManager{
+		FileSystem: fs,
+		Client:     &http.Client{},
+		Processes:  processes,
+		nextID:     1,
+		slots:      make(chan struct{}, defaultMaxConcurrentDownloads),
+	}
+}
+
+// Add ставит файл в очередь загрузки и возвращает номер загрузки.
+// name может быть пустым - тогда имя берется из адреса. checksum задается в виде
+// "sha256:<hex>" (также поддерживаются md5, sha1 и sha512) и может быть пустым.
+func (m *DownloadManager) Add(rawURL, name, checksum string) (int, error) {
+	address, err := NormalizeURL(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if checksum != "" {
+		if _, _, err := parseChecksum(checksum); err != nil {
+			return 0, err
+		}
+	}
+
+	if name == "" {
+		name = FileNameFromURL(address)
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return 0, fmt.Errorf("недопустимое имя файла: %s", name)
+	}
+	if err := m.FileSystem.CreateDirectories(DownloadsDir); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	d := &Download{
+		ID:       m.nextID,
+		URL:      address,
+		Name:     m.uniqueName(name, address),
+		Checksum: checksum,
+		Total:    -1,
+	}
+	m.nextID++
+	m.downloads = append(m.downloads, d)
+	m.mu.Unlock()
+
+	m.start(d)
+	return d.ID, nil
+}
+
+// Cancel отменяет загрузку. Недокачанный файл сохраняется для возобновления.
+func (m *DownloadManager) Cancel(id int) error {
+	m.mu.Lock()
+	d := m.find(id)
+	if d == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("загрузка %d не найдена", id)
+	}
+	if d.Finished() {
+		m.mu.Unlock()
+		return fmt.Errorf("загрузка %d уже завершена", id)
+	}
+	cancel := d.cancel
+	m.mu.Unlock()
+
+	cancel()
+	return nil
+}
+
+// Retry возобновляет отмененную или завершившуюся ошибкой загрузку
+func (m *DownloadManager) Retry(id int) error {
+	m.mu.Lock()
+	d := m.find(id)
+	if d == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("загрузка %d не найдена", id)
+	}
+	if d.State != DownloadFailed && d.State != DownloadCancelled {
+		m.mu.Unlock()
+		return fmt.Errorf("загрузка %d не может быть возобновлена", id)
+	}
+	m.mu.Unlock()
+
+	m.start(d)
+	return nil
+}
+
+// List возвращает снимок всех загрузок
+func (m *DownloadManager) List() []Download {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]Download, len(m.downloads))
+	for i, d := range m.downloads {
+		list[i] = *d
+	}
+	return list
+}
+
+// Get возвращает снимок загрузки по номеру
+func (m *DownloadManager) Get(id int) (Download, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if d := m.find(id); d != nil {
+		return *d, true
+	}
+	return Download{}, false
+}
+
+// Wait ожидает завершения загрузки, вызывая progress при каждом изменении не чаще,
+// чем раз в progressInterval. progress может быть nil.
+func (m *DownloadManager) Wait(id int, progress func(d Download)) (Download, error) {
+	m.mu.Lock()
+	d := m.find(id)
+	if d == nil {
+		m.mu.Unlock()
+		return Download{}, fmt.Errorf("загрузка %d не найдена", id)
+	}
+	done := d.done
+	m.mu.Unlock()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			snapshot, _ := m.Get(id)
+			return snapshot, nil
+		case <-ticker.C:
+			if progress != nil {
+				snapshot, _ := m.Get(id)
+				progress(snapshot)
+			}
+		}
+	}
+}
+
+// find ищет загрузку по номеру; вызывается под блокировкой
+func (m *DownloadManager) find(id int) *Download {
+	for _, d := range m.downloads {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+// uniqueName подбирает имя, не занятое готовым файлом, другой незавершенной загрузкой
+// или недокачанным файлом другого адреса. Недокачанный файл того же адреса
+// address остается свободным - загрузка продолжится с места остановки.
+// Вызывается под блокировкой.
+func (m *DownloadManager) uniqueName(name, address string) string {
+	taken := func(candidate string) bool {
+		if _, err := m.FileSystem.Stat(DownloadsDir + "/" + candidate); err == nil {
+			return true
+		}
+		for _, d := range m.downloads {
+			if d.Name == candidate && d.State != DownloadDone {
+				return true
+			}
+		}
+		if _, err := m.FileSystem.Stat(DownloadsDir + "/" + candidate + partSuffix); err == nil {
+			return m.partURL(candidate) != address
+		}
+		return false
+	}
+
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	return candidate
+}
+
+// partURLPath возвращает путь к скрытому файлу с адресом недокачанного файла name
+func partURLPath(name string) string {
+	return DownloadsDir + "/." + name + partSuffix + partURLSuffix
+}
+
+// partURL возвращает адрес, с которого загружался недокачанный файл name,
+// или пустую строку, если он неизвестен
+func (m *DownloadManager) partURL(name string) string {
+	data, err := m.FileSystem.ReadFile(partURLPath(name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// start запускает загрузку в отдельной горутине
+func (m *DownloadManager) start(d *Download) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	d.State = DownloadQueued
+	d.Err = nil
+	d.cancel = cancel
+	done := make(chan struct{})
+	d.done = done
+	m.mu.Unlock()
+	m.notify(d)
+
+	go m.run(ctx, d, done)
+}
+
+// run ожидает место в очереди и выполняет загрузку
+// done закрывается по завершении; он передается отдельно, так как Retry заменяет d.done.
+func (m *DownloadManager) run(ctx context.Context, d *Download, done chan struct{}) {
+	defer close(done)
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		m.finish(d, DownloadCancelled, nil)
+		return
+	}
+
+	pid := m.Processes.Start("загрузка "+d.Name, ProcessDownload, m.FileSystem.Config.GetUsername(), d.cancel)
+	defer m.Processes.Exit(pid)
+
+	m.mu.Lock()
+	d.State = DownloadActive
+	d.Started = time.Now()
+	m.mu.Unlock()
+	m.notify(d)
+
+	err := m.fetch(ctx, d)
+	if err == nil && d.Checksum != "" {
+		m.mu.Lock()
+		d.State = DownloadVerifying
+		m.mu.Unlock()
+		m.notify(d)
+		err = m.verify(d)
+	}
+	if err == nil {
+		err = m.FileSystem.RenameFile(d.Path()+partSuffix, d.Path())
+	}
+	if err == nil {
+		m.FileSystem.DeleteFile(partURLPath(d.Name))
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		m.finish(d, DownloadCancelled, nil)
+	case err != nil:
+		m.finish(d, DownloadFailed, err)
+	default:
+		m.finish(d, DownloadDone, nil)
+	}
+}
+
+// fetch загружает файл в Downloads/<имя>.part, продолжая с места остановки, если возможно
+func (m *DownloadManager) fetch(ctx context.Context, d *Download) error {
+	part := d.Path() + partSuffix
+
+	// Недокачанный файл другого адреса не продолжаем, а загружаем заново
+	var offset int64
+	if info, err := m.FileSystem.Stat(part); err == nil && m.partURL(d.Name) == d.URL {
+		offset = info.Size()
+	}
+	if err := m.FileSystem.WriteFile(partURLPath(d.Name), []byte(d.URL)); err != nil {
+		return err
+	}
+
+	resp, err := m.request(ctx, d.URL, offset)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusPartialContent && offset > 0 &&
+		contentRangeStart(resp.Header.Get("Content-Range")) != offset {
+		// Сервер прислал не то место файла - дописывать его нельзя, загружаем заново
+		resp.Body.Close()
+		offset = 0
+		if resp, err = m.request(ctx, d.URL, offset); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	appendMode := false
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Сервер не поддерживает докачку - загружаем заново
+		offset = 0
+	case http.StatusPartialContent:
+		if start := contentRangeStart(resp.Header.Get("Content-Range")); start != offset {
+			return fmt.Errorf("сервер прислал часть файла с позиции %d вместо %d", start, offset)
+		}
+		appendMode = offset > 0
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Файл уже загружен полностью
+		if total := contentRangeTotal(resp.Header.Get("Content-Range")); total == offset {
+			m.setProgress(d, offset, total, true)
+			return nil
+		}
+		// Недокачанный файл не соответствует файлу на сервере - начнем заново при повторе
+		m.FileSystem.DeleteFile(part)
+		m.FileSystem.DeleteFile(partURLPath(d.Name))
+		return fmt.Errorf("сервер отклонил запрос на докачку: %s", resp.Status)
+	default:
+		return fmt.Errorf("ошибка HTTP: %s", resp.Status)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	if t := contentRangeTotal(resp.Header.Get("Content-Range")); t > 0 {
+		total = t
+	}
+	m.setProgress(d, offset, total, appendMode)
+
+	out, err := m.FileSystem.OpenWriter(part, appendMode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	received := offset
+	lastNotify := time.Now()
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			received += int64(n)
+			if time.Since(lastNotify) >= progressInterval {
+				m.setProgress(d, received, total, appendMode)
+				lastNotify = time.Now()
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	m.setProgress(d, received, total, appendMode)
+
+	if total >= 0 && received != total {
+		return fmt.Errorf("загружено %d из %d байт", received, total)
+	}
+	return out.Close()
+}
+
+// request запрашивает адрес, начиная с байта offset
+func (m *DownloadManager) request(ctx context.Context, address string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", address, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", browserUserAgent)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	return m.Client.Do(req)
+}
+
+// verify сверяет контрольную сумму загруженного файла
+func (m *DownloadManager) verify(d *Download) error {
+	h, expected, err := parseChecksum(d.Checksum)
+	if err != nil {
+		return err
+	}
+
+	in, err := m.FileSystem.OpenReader(d.Path() + partSuffix)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if _, err := io.Copy(h, in); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		// Поврежденный файл не должен использоваться для докачки
+		m.FileSystem.DeleteFile(d.Path() + partSuffix)
+		return fmt.Errorf("контрольная сумма не совпадает: ожидалось %s, получено %s", expected, actual)
+	}
+	return nil
+}
+
+// setProgress обновляет прогресс загрузки и оповещает подписчика
+func (m *DownloadManager) setProgress(d *Download, received, total int64, resumed bool) {
+	m.mu.Lock()
+	d.Received = received
+	d.Total = total
+	d.Resumed = resumed
+	m.mu.Unlock()
+	m.notify(d)
+}
+
+// finish переводит загрузку в конечное состояние
+func (m *DownloadManager) finish(d *Download, state DownloadState, err error) {
+	m.mu.Lock()
+	d.State = state
+	d.Err = err
+	m.mu.Unlock()
+	m.notify(d)
+}
+
+// notify передает снимок загрузки в OnChange
+func (m *DownloadManager) notify(d *Download) {
+	if m.OnChange == nil {
+		return
+	}
+	m.mu.Lock()
+	snapshot := *d
+	m.mu.Unlock()
+	m.OnChange(snapshot)
+}
+
+// FileNameFromURL возвращает имя файла из последнего сегмента пути адреса
+func FileNameFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "download"
+	}
+	name := path.Base(u.Path)
+	if name == "" || name == "/" || name == "." {
+		if u.Host != "" {
+			return u.Host + ".html"
+		}
+		return "download"
+	}
+	return name
+}
+
+// parseChecksum разбирает строку вида "sha256:<hex>"
+func parseChecksum(checksum string) (hash.Hash, string, error) {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, "", errors.New("контрольная сумма должна быть задана в виде алгоритм:значение, например sha256:abc...")
+	}
+
+	expected := strings.ToLower(strings.TrimSpace(parts[1]))
+	if _, err := hex.DecodeString(expected); err != nil {
+		return nil, "", fmt.Errorf("некорректная контрольная сумма: %s", parts[1])
+	}
+
+	switch strings.ToLower(parts[0]) {
+	case "md5":
+		return md5.New(), expected, nil
+	case "sha1":
+		return sha1.New(), expected, nil
+	case "sha256":
+		return sha256.New(), expected, nil
+	case "sha512":
+		return sha512.New(), expected, nil
+	}
+	return nil, "", fmt.Errorf("неподдерживаемый алгоритм контрольной суммы: %s", parts[0])
+}
+
+// contentRangeStart возвращает начало присланной части из заголовка Content-Range
+// вида "bytes 100-199/200" или -1
+func contentRangeStart(header string) int64 {
+	header = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(header), "bytes"))
+	i := strings.Index(header, "-")
+	if i < 0 {
+		return -1
+	}
+	start, err := strconv.ParseInt(header[:i], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
+
+// contentRangeTotal возвращает полный размер из заголовка Content-Range или -1
+func contentRangeTotal(header string) int64 {
+	i := strings.LastIndex(header, "/")
+	if i < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(header[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
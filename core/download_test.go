@@ -0,0 +1,281 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDownloadData - содержимое файла, которое отдают тестовые серверы
+var testDownloadData = []byte(strings.Repeat("0123456789", 100))
+
+// newTestDownloads создает менеджер загрузок во временной папке
+func newTestDownloads(t *testing.T) (*DownloadManager, string) {
+	fs, root := newTestFileSystem(t)
+	return NewDownloadManager(fs, NewProcessTable()), root
+}
+
+// newDownloadServer запускает сервер с обработчиком handler
+func newDownloadServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// serveTestData отдает testDownloadData с поддержкой заголовка Range
+func serveTestData(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(testDownloadData))
+}
+
+// writePart создает недокачанный файл name с содержимым data, загружавшийся с адреса address
+func writePart(t *testing.T, root, name string, data []byte, address string) {
+	writeTestFile(t, root, "Downloads/"+name+partSuffix, string(data), 0644)
+	writeTestFile(t, root, "Downloads/."+name+partSuffix+partURLSuffix, address, 0644)
+}
+
+// runDownload добавляет загрузку и ожидает ее завершения
+func runDownload(t *testing.T, m *DownloadManager, address, name, checksum string) Download {
+	id, err := m.Add(address, name, checksum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := m.Wait(id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// checkDownloaded проверяет, что загрузка завершилась и файл совпадает с testDownloadData
+func checkDownloaded(t *testing.T, root string, d Download) {
+	t.Helper()
+	if d.State != DownloadDone {
+		t.Fatalf("состояние %q, ошибка %v; ожидалось %q", d.State, d.Err, DownloadDone)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(root, "Downloads", d.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, testDownloadData) {
+		t.Errorf("загружено %d байт, не совпадает с файлом на сервере", len(data))
+	}
+	for _, name := range []string{d.Name + partSuffix, "." + d.Name + partSuffix + partURLSuffix} {
+		if _, err := os.Stat(filepath.Join(root, "Downloads", name)); !os.IsNotExist(err) {
+			t.Errorf("после загрузки остался %s", name)
+		}
+	}
+}
+
+func TestDownloadResume(t *testing.T) {
+	var ranges []string
+	srv := newDownloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		serveTestData(w, r)
+	})
+	m, root := newTestDownloads(t)
+	writePart(t, root, "file.bin", testDownloadData[:300], srv.URL+"/file.bin")
+
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "")
+	checkDownloaded(t, root, d)
+	if d.Name != "file.bin" || !d.Resumed {
+		t.Errorf("Name = %q, Resumed = %v; ожидалась докачка file.bin", d.Name, d.Resumed)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=300-" {
+		t.Errorf("запросы Range: %q", ranges)
+	}
+}
+
+func TestDownloadRestartsWithoutRangeSupport(t *testing.T) {
+	srv := newDownloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Сервер не поддерживает докачку и всегда отдает файл целиком
+		w.Write(testDownloadData)
+	})
+	m, root := newTestDownloads(t)
+	writePart(t, root, "file.bin", []byte("испорченное начало"), srv.URL+"/file.bin")
+
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "")
+	checkDownloaded(t, root, d)
+	if d.Resumed {
+		t.Error("ответ 200 на запрос с Range считается докачкой")
+	}
+}
+
+func TestDownloadRestartsOnWrongRangeStart(t *testing.T) {
+	var ranges []string
+	srv := newDownloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if r.Header.Get("Range") == "" {
+			w.Write(testDownloadData)
+			return
+		}
+		// На запрос докачки сервер присылает файл с начала
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(testDownloadData)-1, len(testDownloadData)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(testDownloadData)
+	})
+	m, root := newTestDownloads(t)
+	writePart(t, root, "file.bin", testDownloadData[:300], srv.URL+"/file.bin")
+
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "")
+	checkDownloaded(t, root, d)
+	if d.Resumed {
+		t.Error("часть файла с другой позиции дописана к недокачанному файлу")
+	}
+	if len(ranges) != 2 || ranges[1] != "" {
+		t.Errorf("запросы Range: %q; ожидался повтор без Range", ranges)
+	}
+}
+
+func TestDownloadRangeNotSatisfiable(t *testing.T) {
+	srv := newDownloadServer(t, serveTestData)
+
+	// Недокачанный файл уже полный - сервер отвечает 416 с тем же размером
+	m, root := newTestDownloads(t)
+	writePart(t, root, "file.bin", testDownloadData, srv.URL+"/file.bin")
+	checkDownloaded(t, root, runDownload(t, m, srv.URL+"/file.bin", "", ""))
+
+	// Недокачанный файл больше файла на сервере - начинать нужно заново
+	m, root = newTestDownloads(t)
+	writePart(t, root, "file.bin", append(testDownloadData, "лишнее"...), srv.URL+"/file.bin")
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "")
+	if d.State != DownloadFailed || d.Err == nil || !strings.Contains(d.Err.Error(), "416") {
+		t.Fatalf("состояние %q, ошибка %v; ожидалась ошибка 416", d.State, d.Err)
+	}
+	if m.FileSystem.Exists(d.Path()+partSuffix) || m.FileSystem.Exists(partURLPath(d.Name)) {
+		t.Error("неподходящий недокачанный файл не удален")
+	}
+	// Повтор загружает файл заново
+	if err := m.Retry(d.ID); err != nil {
+		t.Fatal(err)
+	}
+	d, _ = m.Wait(d.ID, nil)
+	checkDownloaded(t, root, d)
+}
+
+func TestDownloadShortBody(t *testing.T) {
+	srv := newDownloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(len(testDownloadData)))
+		w.Write(testDownloadData[:100])
+	})
+	m, _ := newTestDownloads(t)
+
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "")
+	if d.State != DownloadFailed || d.Err == nil {
+		t.Fatalf("состояние %q, ошибка %v; ожидалась ошибка", d.State, d.Err)
+	}
+	if m.FileSystem.Exists(d.Path()) {
+		t.Error("недокачанный файл переименован в готовый")
+	}
+}
+
+func TestDownloadChecksum(t *testing.T) {
+	srv := newDownloadServer(t, serveTestData)
+	sum := sha256.Sum256(testDownloadData)
+	m, root := newTestDownloads(t)
+
+	checkDownloaded(t, root, runDownload(t, m, srv.URL+"/file.bin", "", "sha256:"+hex.EncodeToString(sum[:])))
+
+	wrong := sha256.Sum256([]byte("другой файл"))
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "sha256:"+hex.EncodeToString(wrong[:]))
+	if d.State != DownloadFailed || d.Err == nil || !strings.Contains(d.Err.Error(), "контрольная сумма") {
+		t.Fatalf("состояние %q, ошибка %v; ожидалось несовпадение контрольной суммы", d.State, d.Err)
+	}
+	if m.FileSystem.Exists(d.Path()+partSuffix) || m.FileSystem.Exists(d.Path()) {
+		t.Error("файл с неверной контрольной суммой не удален")
+	}
+
+	if _, err := m.Add(srv.URL+"/file.bin", "", "crc32:abcd"); err == nil {
+		t.Error("неподдерживаемый алгоритм контрольной суммы принят")
+	}
+}
+
+func TestDownloadPartOfOtherURL(t *testing.T) {
+	srv := newDownloadServer(t, serveTestData)
+	m, root := newTestDownloads(t)
+	other := []byte("начало другого файла")
+	writePart(t, root, "file.bin", other, "http://example.com/file.bin")
+
+	d := runDownload(t, m, srv.URL+"/file.bin", "", "")
+	if d.Name != "file (1).bin" {
+		t.Fatalf("Name = %q, ожидалось %q", d.Name, "file (1).bin")
+	}
+	checkDownloaded(t, root, d)
+	if data, err := ioutil.ReadFile(filepath.Join(root, "Downloads", "file.bin"+partSuffix)); err != nil || !bytes.Equal(data, other) {
+		t.Errorf("недокачанный файл другого адреса изменен: %q, %v", data, err)
+	}
+}
+
+func TestDownloadCancelAndRetry(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := newDownloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		mu.Unlock()
+		if !first {
+			serveTestData(w, r)
+			return
+		}
+		// Первый запрос отдает половину файла и зависает до отмены
+		w.Header().Set("Content-Length", fmt.Sprint(len(testDownloadData)))
+		w.Write(testDownloadData[:500])
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	m, root := newTestDownloads(t)
+
+	id, err := m.Add(srv.URL+"/file.bin", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part := filepath.Join(root, "Downloads", "file.bin"+partSuffix)
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if info, err := os.Stat(part); err == nil && info.Size() == 500 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("первая половина файла не загружена")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := m.Retry(id); err == nil {
+		t.Error("Retry активной загрузки должен вернуть ошибку")
+	}
+
+	if err := m.Cancel(id); err != nil {
+		t.Fatal(err)
+	}
+	d, _ := m.Wait(id, nil)
+	if d.State != DownloadCancelled {
+		t.Fatalf("состояние %q, ошибка %v; ожидалось %q", d.State, d.Err, DownloadCancelled)
+	}
+	if err := m.Cancel(id); err == nil {
+		t.Error("Cancel завершенной загрузки должен вернуть ошибку")
+	}
+	if info, err := os.Stat(part); err != nil || info.Size() != 500 {
+		t.Fatalf("недокачанный файл после отмены: %v, %v", info, err)
+	}
+
+	if err := m.Retry(id); err != nil {
+		t.Fatal(err)
+	}
+	d, _ = m.Wait(id, nil)
+	checkDownloaded(t, root, d)
+	if !d.Resumed {
+		t.Error("повтор не продолжил загрузку с места остановки")
+	}
+	if err := m.Retry(id); err == nil {
+		t.Error("Retry завершенной загрузки должен вернуть ошибку")
+	}
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// maxUndoSteps ограничивает глубину истории отмены
+	maxUndoSteps = 200
+	// undoMergeInterval - правки, сделанные быстрее этого интервала, объединяются в один шаг отмены
+	undoMergeInterval = time.Second
+)
+
+// Document представляет открытый в редакторе текстовый документ
+type Document struct {
+	FileSystem *FileSystem
+	Path       string
+
+	text     string
+	saved    string
+	undo     []string
+	redo     []string
+	lastEdit time.Time
+}
+
+// NewDocument создает новый пустой документ
+func NewDocument(fs *FileSystem) *Document {
+	return &Document{
+		FileSystem: fs,
+	}
+}
+
+// Reset очищает документ. name задает файл для последующего сохранения и может быть пустым.
+func (d *Document) Reset(name string) error {
+	path := ""
+	if name != "" {
+		var err error
+		if path, err = d.FileSystem.ResolvePath(name); err != nil {
+			return err
+		}
+	}
+
+	d.Path = path
+	d.text = ""
+	d.saved = ""
+	d.undo = nil
+	d.redo = nil
+	return nil
+}
+
+// Open загружает документ из файла
+func (d *Document) Open(name string) error {
+	path, err := d.FileSystem.ResolvePath(name)
+	if err != nil {
+		return err
+	}
+
+	data, err := d.FileSystem.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	d.Path = path
+	d.text = string(data)
+	d.saved = d.text
+	d.undo = nil
+	d.redo = nil
+	return nil
+}
+
+// Save сохраняет документ в текущий файл
+func (d *Document) Save() error {
+	if d.Path == "" {
+		return fmt.Errorf("файл для сохранения не задан")
+	}
+
+	if err := d.FileSystem.WriteFile(d.Path, []byte(d.text)); err != nil {
+		return err
+	}
+	d.saved = d.text
+	return nil
+}
+
+// SaveAs сохраняет документ в новый файл
+func (d *Document) SaveAs(name string) error {
+	path, err := d.FileSystem.ResolvePath(name)
+	if err != nil {
+		return err
+	}
+
+	previous := d.Path
+	d.Path = path
+	if err := d.Save(); err != nil {
+		d.Path = previous
+		return err
+	}
+	return nil
+}
+
+// Name возвращает имя файла документа
+func (d *Document) Name() string {
+	if d.Path == "" {
+		return "Без имени"
+	}
+	return filepath.Base(d.Path)
+}
+
+// Text возвращает текущий текст документа
+func (d *Document) Text() string {
+	return d.text
+}
+
+// SetText изменяет текст документа с сохранением истории для отмены
+func (d *Document) SetText(text string) {
+	if text == d.text {
+		return
+	}
+
+	// Быстрые последовательные правки (набор текста) объединяются в один шаг
+	now := time.Now()
+	if len(d.undo) == 0 || now.Sub(d.lastEdit) > undoMergeInterval {
+		d.undo = append(d.undo, d.text)
+		if len(d.undo) > maxUndoSteps {
+			d.undo = d.undo[1:]
+		}
+	}
+	d.lastEdit = now
+	d.redo = nil
+	d.text = text
+}
+
+// Dirty сообщает, есть ли несохраненные изменения
+func (d *Document) Dirty() bool {
+	return d.text != d.saved
+}
+
+// Undo отменяет последнее изменение
+func (d *Document) Undo() bool {
+	if len(d.undo) == 0 {
+		return false
+	}
+
+	d.redo = append(d.redo, d.text)
+	d.text = d.undo[len(d.undo)-1]
+	d.undo = d.undo[:len(d.undo)-1]
+	d.lastEdit = time.Time{}
+	return true
+}
+
+// Redo повторяет отмененное изменение
+func (d *Document) Redo() bool {
+	if len(d.redo) == 0 {
+		return false
+	}
+
+	d.undo = append(d.undo, d.text)
+	d.text = d.redo[len(d.redo)-1]
+	d.redo = d.redo[:len(d.redo)-1]
+	d.lastEdit = time.Time{}
+	return true
+}
+
+// Find ищет подстроку начиная с позиции from (в рунах) и возвращает позицию найденного
+// вхождения в рунах или -1. Поиск продолжается с начала текста, если до конца ничего не найдено.
+func (d *Document) Find(query string, from int) int {
+	if query == "" {
+		return -1
+	}
+
+	runes := []rune(d.text)
+	if from < 0 || from > len(runes) {
+		from = 0
+	}
+
+	if i := strings.Index(string(runes[from:]), query); i >= 0 {
+		return from + len([]rune(string(runes[from:])[:i]))
+	}
+	if i := strings.Index(d.text, query); i >= 0 {
+		return len([]rune(d.text[:i]))
+	}
+	return -1
+}
+
+// Replace заменяет вхождение query в позиции pos (в рунах) на replacement
+func (d *Document) Replace(pos int, query, replacement string) bool {
+	runes := []rune(d.text)
+	queryLen := len([]rune(query))
+	if query == "" || pos < 0 || pos+queryLen > len(runes) || string(runes[pos:pos+queryLen]) != query {
+		return false
+	}
+
+	d.lastEdit = time.Time{}
+	d.SetText(string(runes[:pos]) + replacement + string(runes[pos+queryLen:]))
+	return true
+}
+
+// ReplaceAll заменяет все вхождения query и возвращает количество замен
+func (d *Document) ReplaceAll(query, replacement string) int {
+	if query == "" {
+		return 0
+	}
+
+	count := strings.Count(d.text, query)
+	if count > 0 {
+		d.lastEdit = time.Time{}
+		d.SetText(strings.ReplaceAll(d.text, query, replacement))
+	}
+	return count
+}
+
+// LineCount возвращает количество строк в документе
+func (d *Document) LineCount() int {
+	return strings.Count(d.text, "\n") + 1
+}
+
+// Position преобразует позицию в рунах в номер строки и столбца (с нуля)
+func (d *Document) Position(pos int) (row, col int) {
+	for i, r := range []rune(d.text) {
+		if i >= pos {
+			break
+		}
+		if r == '\n' {
+			row++
+			col = 0
+		} else {
+			col++
+		}
+	}
+	return row, col
+}
+
+// Offset преобразует номер строки и столбца в позицию в рунах
+func (d *Document) Offset(row, col int) int {
+	pos := 0
+	for i, line := range strings.Split(d.text, "\n") {
+		length := len([]rune(line))
+		if i == row {
+			if col > length {
+				col = length
+			}
+			return pos + col
+		}
+		pos += length + 1
+	}
+	return len([]rune(d.text))
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDocumentUndoMerge(t *testing.T) {
+	fs, _ := newTestFileSystem(t)
+	d := NewDocument(fs)
+
+	// Быстрый набор объединяется в один шаг отмены
+	d.SetText("п")
+	d.SetText("пр")
+	d.SetText("при")
+	// Правка после паузы - отдельный шаг
+	d.lastEdit = time.Now().Add(-2 * undoMergeInterval)
+	d.SetText("привет")
+
+	if !d.Undo() || d.Text() != "при" {
+		t.Fatalf("первая отмена: %q, ожидалось %q", d.Text(), "при")
+	}
+	if !d.Undo() || d.Text() != "" {
+		t.Fatalf("вторая отмена: %q, ожидался пустой текст", d.Text())
+	}
+	if d.Undo() {
+		t.Error("отмена без истории должна вернуть false")
+	}
+
+	if !d.Redo() || d.Text() != "при" {
+		t.Fatalf("повтор: %q, ожидалось %q", d.Text(), "при")
+	}
+	// После отмены правка начинает новый шаг, а история повтора очищается
+	d.SetText("пригород")
+	if d.Redo() {
+		t.Error("после правки история повтора должна очищаться")
+	}
+	if !d.Undo() || d.Text() != "при" {
+		t.Errorf("отмена после повтора: %q, ожидалось %q", d.Text(), "при")
+	}
+}
+
+func TestDocumentFindReplace(t *testing.T) {
+	fs, _ := newTestFileSystem(t)
+	d := NewDocument(fs)
+	d.SetText("Мир и мир, мир")
+
+	// Позиции считаются в рунах, а не в байтах
+	if pos := d.Find("мир", 0); pos != 6 {
+		t.Errorf("Find(мир, 0) = %d, ожидалось 6", pos)
+	}
+	if pos := d.Find("мир", 7); pos != 11 {
+		t.Errorf("Find(мир, 7) = %d, ожидалось 11", pos)
+	}
+	// Поиск продолжается с начала текста
+	if pos := d.Find("Мир", 5); pos != 0 {
+		t.Errorf("Find(Мир, 5) = %d, ожидалось 0", pos)
+	}
+	if pos := d.Find("война", 0); pos != -1 {
+		t.Errorf("Find(война) = %d, ожидалось -1", pos)
+	}
+
+	if d.Replace(7, "мир", "дом") {
+		t.Error("Replace с неверной позицией выполнил замену")
+	}
+	if !d.Replace(11, "мир", "дом") || d.Text() != "Мир и мир, дом" {
+		t.Errorf("Replace(11) = %q", d.Text())
+	}
+	if n := d.ReplaceAll("ир", "ост"); n != 2 || d.Text() != "Мост и мост, дом" {
+		t.Errorf("ReplaceAll = %d, %q", n, d.Text())
+	}
+	// Каждая замена отменяется отдельно
+	if !d.Undo() || d.Text() != "Мир и мир, дом" {
+		t.Errorf("отмена ReplaceAll: %q", d.Text())
+	}
+}
+
+func TestDocumentPositionOffset(t *testing.T) {
+	fs, _ := newTestFileSystem(t)
+	d := NewDocument(fs)
+	d.SetText("первая\nвторая строка\n\nё")
+
+	tests := []struct {
+		pos, row, col int
+	}{
+		{0, 0, 0},
+		{6, 0, 6},
+		{7, 1, 0},
+		{10, 1, 3},
+		{21, 2, 0},
+		{22, 3, 0},
+		{23, 3, 1},
+	}
+	for _, tt := range tests {
+		if row, col := d.Position(tt.pos); row != tt.row || col != tt.col {
+			t.Errorf("Position(%d) = %d:%d, ожидалось %d:%d", tt.pos, row, col, tt.row, tt.col)
+		}
+		if pos := d.Offset(tt.row, tt.col); pos != tt.pos {
+			t.Errorf("Offset(%d, %d) = %d, ожидалось %d", tt.row, tt.col, pos, tt.pos)
+		}
+	}
+
+	// Столбец за концом строки и строка за концом текста ограничиваются
+	if pos := d.Offset(0, 100); pos != 6 {
+		t.Errorf("Offset(0, 100) = %d, ожидалось 6", pos)
+	}
+	if pos := d.Offset(100, 0); pos != 23 {
+		t.Errorf("Offset(100, 0) = %d, ожидалось 23", pos)
+	}
+	if d.LineCount() != 4 {
+		t.Errorf("LineCount = %d, ожидалось 4", d.LineCount())
+	}
+}
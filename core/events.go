@@ -0,0 +1,149 @@
+package core
+
+import (
+	"path"
+	"sync"
+	"time"
+)
+
+// EventType - вид системного события
+type EventType int
+
+const (
+	// EventFileCreated - создан файл или папка
+	EventFileCreated EventType = iota
+	// EventFileChanged - перезаписан существующий файл
+	EventFileChanged
+	// EventFileDeleted - удален файл или папка
+	EventFileDeleted
+	// EventFileRenamed - файл переименован или перемещен
+	EventFileRenamed
+	// EventConfigChanged - настройки сохранены
+	EventConfigChanged
+	// EventUserSwitched - сменился пользователь
+	EventUserSwitched
+	// EventAppLaunched - запущено приложение
+	EventAppLaunched
+)
+
+// eventNames - названия событий для отладочного вывода
+var eventNames = map[EventType]string{
+	EventFileCreated:   "file-created",
+	EventFileChanged:   "file-changed",
+	EventFileDeleted:   "file-deleted",
+	EventFileRenamed:   "file-renamed",
+	EventConfigChanged: "config-changed",
+	EventUserSwitched:  "user-switched",
+	EventAppLaunched:   "app-launched",
+}
+
+// String возвращает название события
+func (t EventType) String() string {
+	return eventNames[t]
+}
+
+// Event - системное событие
+type Event struct {
+	Type EventType
+	// Path - путь внутри MixailOS к файлу; у переименования - новый путь,
+	// у запуска приложения - открытый файл (может быть пустым)
+	Path string
+	// OldPath - прежний путь переименованного файла
+	OldPath string
+	// AppID - запущенное приложение
+	AppID string
+	// User - новое имя пользователя
+	User string
+	Time time.Time
+}
+
+// IsFileEvent проверяет, относится ли событие к файлам
+func (e Event) IsFileEvent() bool {
+	switch e.Type {
+	case EventFileCreated, EventFileChanged, EventFileDeleted, EventFileRenamed:
+		return true
+	}
+	return false
+}
+
+// InDir проверяет, затрагивает ли файловое событие содержимое папки dir
+// (путь внутри MixailOS, например "/Documents")
+func (e Event) InDir(dir string) bool {
+	if !e.IsFileEvent() {
+		return false
+	}
+	dir = path.Clean("/" + dir)
+	return path.Dir(e.Path) == dir || (e.OldPath != "" && path.Dir(e.OldPath) == dir)
+}
+
+// eventHandler - подписчик на события; types пуст, если нужны все события
+type eventHandler struct {
+	types []EventType
+	fn    func(Event)
+}
+
+// wants проверяет, подписан ли обработчик на событие вида t
+func (h eventHandler) wants(t EventType) bool {
+	if len(h.types) == 0 {
+		return true
+	}
+	for _, want := range h.types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// EventBus передает события от файловой системы, настроек и консоли
+// подписчикам, например файловому менеджеру и рабочему столу. Обработчики
+// вызываются в горутине, опубликовавшей событие.
+type EventBus struct {
+	mu       sync.Mutex
+	handlers map[int]eventHandler
+	nextID   int
+}
+
+// NewEventBus создает шину событий
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: map[int]eventHandler{}}
+}
+
+// Subscribe подписывает fn на события видов types (на все, если types не указаны)
+// и возвращает функцию отмены подписки
+func (b *EventBus) Subscribe(fn func(Event), types ...EventType) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = eventHandler{types: types, fn: fn}
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish передает событие подписчикам. У шины nil событие игнорируется,
+// поэтому ядро может публиковать события и без подключенного интерфейса.
+func (b *EventBus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.Lock()
+	var handlers []eventHandler
+	for id := 0; id < b.nextID; id++ {
+		if h, ok := b.handlers[id]; ok && h.wants(e.Type) {
+			handlers = append(handlers, h)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h.fn(e)
+	}
+}
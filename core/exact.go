@@ -0,0 +1,427 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	// DefaultPrecision - количество знаков после запятой в точном режиме по умолчанию
+	DefaultPrecision = 30
+	// MaxPrecision - наибольшая допустимая точность
+	MaxPrecision = 1000
+	// maxExactExponent - наибольший показатель степени, вычисляемой точно
+	maxExactExponent = 10000
+	// maxExactBits - наибольший размер числителя или знаменателя точной степени в битах
+	maxExactBits = 1 << 18
+	// maxExactFactorial - наибольшее число, факториал которого вычисляется точно
+	maxExactFactorial = 5000
+	// float64Prec - точность мантиссы float64 в битах
+	float64Prec = 53
+)
+
+// Number - результат точного вычисления: рациональное число, если результат
+// можно представить точно, иначе число с плавающей точкой заданной точности
+type Number struct {
+	Rat   *big.Rat
+	Float *big.Float
+}
+
+// IsExact сообщает, является ли число точным (рациональным)
+func (n Number) IsExact() bool {
+	return n.Rat != nil
+}
+
+// Float64 возвращает ближайшее значение float64
+func (n Number) Float64() float64 {
+	if n.Rat != nil {
+		v, _ := n.Rat.Float64()
+		return v
+	}
+	v, _ := n.Float.Float64()
+	return v
+}
+
+// Format форматирует число с precision знаками после запятой. Дроби, которые
+// нельзя записать точно в precision знаков, выводятся в виде "1/3 ≈ 0.333...".
+// Результаты, вычисленные через float64, округляются до 15 значащих цифр.
+func (n Number) Format(precision int) string {
+	if n.Rat == nil {
+		if n.Float.Prec() <= float64Prec {
+			return FormatNumber(n.Float64())
+		}
+		return trimZeros(n.Float.Text('f', precision))
+	}
+	if n.Rat.IsInt() {
+		return n.Rat.Num().String()
+	}
+	decimal := trimZeros(n.Rat.FloatString(precision))
+	if r, ok := new(big.Rat).SetString(decimal); ok && r.Cmp(n.Rat) == 0 {
+		return decimal
+	}
+	return n.Rat.String() + " ≈ " + decimal
+}
+
+// trimZeros удаляет незначащие нули после запятой
+func trimZeros(s string) string {
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// ExactEnv - окружение точного вычисления
+type ExactEnv struct {
+	Vars    map[string]Number
+	Degrees bool
+	// Precision - количество знаков после запятой для неточных результатов
+	Precision int
+}
+
+// bits возвращает точность big.Float в битах с запасом на округление
+func (env *ExactEnv) bits() uint {
+	return uint(float64(env.Precision)*math.Log2(10)) + 64
+}
+
+// float преобразует число в big.Float. Рациональные числа получают точность
+// окружения, приближенные сохраняют свою.
+func (env *ExactEnv) float(n Number) *big.Float {
+	if n.Rat != nil {
+		return new(big.Float).SetPrec(env.bits()).SetRat(n.Rat)
+	}
+	return n.Float
+}
+
+// fromFloat64 создает приближенное число из результата функции float64.
+// Точность такого числа ограничена float64 и не растет в дальнейших вычислениях.
+func (env *ExactEnv) fromFloat64(v float64) Number {
+	return Number{Float: new(big.Float).SetFloat64(v)}
+}
+
+// EvalExact вычисляет выражение точно: в рациональных числах, пока это возможно,
+// иначе в числах с плавающей точкой точности env.Precision
+func (e *Expression) EvalExact(env *ExactEnv) (Number, error) {
+	if env.Precision <= 0 {
+		env.Precision = DefaultPrecision
+	}
+	return evalExact(e.root, env)
+}
+
+// ratFromLiteral разбирает число, в том числе с префиксом основания
+func ratFromLiteral(text string) (*big.Rat, bool) {
+	if len(text) > 2 && text[0] == '0' && strings.ContainsRune("xXbBoO", rune(text[1])) {
+		i, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return nil, false
+		}
+		return new(big.Rat).SetInt(i), true
+	}
+	return new(big.Rat).SetString(text)
+}
+
+// evalExact вычисляет узел точно
+func evalExact(n calcNode, env *ExactEnv) (Number, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		r, ok := ratFromLiteral(n.text)
+		if !ok {
+			return Number{}, calcErrorf(n.pos, "некорректное число %q", n.text)
+		}
+		return Number{Rat: r}, nil
+
+	case *varNode:
+		switch n.name {
+		case "pi", "π":
+			return Number{Float: bigPi(env.bits())}, nil
+		case "e":
+			return Number{Float: bigE(env.bits())}, nil
+		}
+		if v, ok := env.Vars[n.name]; ok {
+			return v, nil
+		}
+		return Number{}, calcErrorf(n.pos, "неизвестное имя %q", n.name)
+
+	case *unaryNode:
+		x, err := evalExact(n.x, env)
+		if err != nil {
+			return Number{}, err
+		}
+		switch n.op {
+		case "-":
+			if x.Rat != nil {
+				return Number{Rat: new(big.Rat).Neg(x.Rat)}, nil
+			}
+			return Number{Float: new(big.Float).SetPrec(x.Float.Prec()).Neg(x.Float)}, nil
+		case "~":
+			return Number{}, calcErrorf(n.pos, "битовые операции доступны только в режиме программиста")
+		}
+		return x, nil
+
+	case *percentNode:
+		x, err := evalExact(n.x, env)
+		if err != nil {
+			return Number{}, err
+		}
+		return exactArith("/", x, Number{Rat: big.NewRat(100, 1)}, env), nil
+
+	case *factorialNode:
+		x, err := evalExact(n.x, env)
+		if err != nil {
+			return Number{}, err
+		}
+		return exactFactorial(x, n.pos)
+
+	case *callNode:
+		return evalExactCall(n, env)
+
+	case *binaryNode:
+		x, err := evalExact(n.x, env)
+		if err != nil {
+			return Number{}, err
+		}
+		// a + b% означает a + (a * b / 100)
+		if pct, ok := n.y.(*percentNode); ok && (n.op == "+" || n.op == "-") {
+			y, err := evalExact(pct, env)
+			if err != nil {
+				return Number{}, err
+			}
+			return exactArith(n.op, x, exactArith("*", x, y, env), env), nil
+		}
+		y, err := evalExact(n.y, env)
+		if err != nil {
+			return Number{}, err
+		}
+
+		switch n.op {
+		case "+", "-", "*":
+			return exactArith(n.op, x, y, env), nil
+		case "/", "mod":
+			if isZero(y) {
+				return Number{}, calcErrorf(n.pos, "деление на ноль")
+			}
+			return exactArith(n.op, x, y, env), nil
+		case "^":
+			return exactPow(x, y, n.pos, env)
+		case "&", "|", "xor", "<<", ">>":
+			return Number{}, calcErrorf(n.pos, "битовые операции доступны только в режиме программиста")
+		}
+		return Number{}, calcErrorf(n.pos, "неизвестная операция %q", n.op)
+	}
+	return Number{}, calcErrorf(n.position(), "неизвестная операция")
+}
+
+// isZero проверяет, равно ли число нулю
+func isZero(n Number) bool {
+	if n.Rat != nil {
+		return n.Rat.Sign() == 0
+	}
+	return n.Float.Sign() == 0
+}
+
+// exactArith выполняет арифметическую операцию; результат точен, если точны оба операнда
+func exactArith(op string, x, y Number, env *ExactEnv) Number {
+	if x.Rat != nil && y.Rat != nil {
+		r := new(big.Rat)
+		switch op {
+		case "+":
+			r.Add(x.Rat, y.Rat)
+		case "-":
+			r.Sub(x.Rat, y.Rat)
+		case "*":
+			r.Mul(x.Rat, y.Rat)
+		case "/":
+			r.Quo(x.Rat, y.Rat)
+		case "mod":
+			// Остаток со знаком делимого, как у math.Mod: x - y * trunc(x / y)
+			q := new(big.Rat).Quo(x.Rat, y.Rat)
+			t := new(big.Int).Quo(q.Num(), q.Denom())
+			r.Sub(x.Rat, new(big.Rat).Mul(y.Rat, new(big.Rat).SetInt(t)))
+		}
+		return Number{Rat: r}
+	}
+
+	// Точность результата - наименьшая из точностей операндов
+	a, b := env.float(x), env.float(y)
+	prec := a.Prec()
+	if b.Prec() < prec {
+		prec = b.Prec()
+	}
+	f := new(big.Float).SetPrec(prec)
+	switch op {
+	case "+":
+		f.Add(a, b)
+	case "-":
+		f.Sub(a, b)
+	case "*":
+		f.Mul(a, b)
+	case "/":
+		f.Quo(a, b)
+	case "mod":
+		q := new(big.Float).SetPrec(prec).Quo(a, b)
+		t, _ := q.Int(nil)
+		f.Sub(a, new(big.Float).SetPrec(prec).Mul(b, new(big.Float).SetInt(t)))
+	}
+	return Number{Float: f}
+}
+
+// exactPow возводит в степень: точно для рациональных оснований и целых показателей
+func exactPow(x, y Number, pos int, env *ExactEnv) (Number, error) {
+	if x.Rat != nil && y.Rat != nil && y.Rat.IsInt() {
+		exp := y.Rat.Num()
+		if exp.CmpAbs(big.NewInt(maxExactExponent)) > 0 {
+			return Number{}, calcErrorf(pos, "показатель степени больше %d", maxExactExponent)
+		}
+		if x.Rat.Sign() == 0 && exp.Sign() < 0 {
+			return Number{}, calcErrorf(pos, "деление на ноль")
+		}
+
+		// Размер результата оценивается до вычисления, чтобы огромная степень
+		// вроде (10^1000)^10000 не заняла всю память
+		e := new(big.Int).Abs(exp)
+		bits := x.Rat.Num().BitLen()
+		if denBits := x.Rat.Denom().BitLen(); denBits > bits {
+			bits = denBits
+		}
+		if int64(bits)*e.Int64() > maxExactBits {
+			return Number{}, calcErrorf(pos, "переполнение: результат больше %d бит", maxExactBits)
+		}
+
+		num := new(big.Int).Exp(x.Rat.Num(), e, nil)
+		den := new(big.Int).Exp(x.Rat.Denom(), e, nil)
+		if exp.Sign() < 0 {
+			num, den = den, num
+		}
+		return Number{Rat: new(big.Rat).SetFrac(num, den)}, nil
+	}
+
+	// Дробная степень вычисляется через float64
+	v := math.Pow(x.Float64(), y.Float64())
+	if math.IsNaN(v) {
+		return Number{}, calcErrorf(pos, "степень не определена")
+	}
+	if math.IsInf(v, 0) {
+		return Number{}, calcErrorf(pos, "переполнение")
+	}
+	return env.fromFloat64(v), nil
+}
+
+// exactFactorial вычисляет факториал точно
+func exactFactorial(x Number, pos int) (Number, error) {
+	if x.Rat == nil || !x.Rat.IsInt() || x.Rat.Sign() < 0 {
+		return Number{}, calcErrorf(pos, "факториал определен только для неотрицательных целых чисел")
+	}
+	if x.Rat.Num().Cmp(big.NewInt(maxExactFactorial)) > 0 {
+		return Number{}, calcErrorf(pos, "факториал слишком велик (максимум %d!)", maxExactFactorial)
+	}
+	n := x.Rat.Num().Int64()
+	if n < 2 {
+		return Number{Rat: big.NewRat(1, 1)}, nil
+	}
+	return Number{Rat: new(big.Rat).SetInt(new(big.Int).MulRange(1, n))}, nil
+}
+
+// evalExactCall вычисляет функцию. sqrt, abs и fact вычисляются с полной точностью,
+// остальные функции - через float64.
+func evalExactCall(n *callNode, env *ExactEnv) (Number, error) {
+	args := make([]Number, len(n.args))
+	for i, arg := range n.args {
+		v, err := evalExact(arg, env)
+		if err != nil {
+			return Number{}, err
+		}
+		args[i] = v
+	}
+
+	x := args[0]
+	switch n.name {
+	case "abs":
+		if x.Rat != nil {
+			return Number{Rat: new(big.Rat).Abs(x.Rat)}, nil
+		}
+		return Number{Float: new(big.Float).SetPrec(x.Float.Prec()).Abs(x.Float)}, nil
+	case "fact":
+		return exactFactorial(x, n.pos)
+	case "sqrt":
+		f := env.float(x)
+		if f.Sign() < 0 {
+			return Number{}, calcErrorf(n.pos, "корень из отрицательного числа")
+		}
+		// Корень из точного квадрата остается точным: sqrt(4/9) = 2/3
+		if x.Rat != nil {
+			num, den := new(big.Int).Sqrt(x.Rat.Num()), new(big.Int).Sqrt(x.Rat.Denom())
+			if new(big.Int).Mul(num, num).Cmp(x.Rat.Num()) == 0 && new(big.Int).Mul(den, den).Cmp(x.Rat.Denom()) == 0 {
+				return Number{Rat: new(big.Rat).SetFrac(num, den)}, nil
+			}
+		}
+		return Number{Float: new(big.Float).SetPrec(f.Prec()).Sqrt(f)}, nil
+	}
+
+	floats := make([]float64, len(args))
+	for i, arg := range args {
+		floats[i] = arg.Float64()
+	}
+	v, msg := calcFunctions[n.name].fn(floats, &Env{Degrees: env.Degrees})
+	if msg != "" {
+		return Number{}, calcErrorf(n.pos, "%s", msg)
+	}
+	if math.IsInf(v, 0) {
+		return Number{}, calcErrorf(n.pos, "переполнение")
+	}
+	if math.IsNaN(v) {
+		return Number{}, calcErrorf(n.pos, "функция %s не определена для этого аргумента", n.name)
+	}
+	// Небольшие целые значения (cos(0), log(100)) сохраняем рациональными
+	if v == math.Trunc(v) && math.Abs(v) < 1<<20 {
+		return Number{Rat: new(big.Rat).SetFloat64(v)}, nil
+	}
+	return env.fromFloat64(v), nil
+}
+
+// bigPi вычисляет число пи с точностью prec бит по формуле Мэчина:
+// pi = 16 * arctg(1/5) - 4 * arctg(1/239)
+func bigPi(prec uint) *big.Float {
+	a := bigArctanInv(5, prec)
+	b := bigArctanInv(239, prec)
+	a.Mul(a, big.NewFloat(16))
+	b.Mul(b, big.NewFloat(4))
+	return a.Sub(a, b)
+}
+
+// bigArctanInv вычисляет arctg(1/x) рядом Тейлора
+func bigArctanInv(x int64, prec uint) *big.Float {
+	sum := new(big.Float).SetPrec(prec)
+	x2 := new(big.Float).SetPrec(prec).SetInt64(x * x)
+	power := new(big.Float).SetPrec(prec).Quo(big.NewFloat(1), new(big.Float).SetPrec(prec).SetInt64(x))
+	eps := new(big.Float).SetPrec(prec).SetMantExp(big.NewFloat(1), -int(prec))
+
+	for k := int64(0); ; k++ {
+		term := new(big.Float).SetPrec(prec).Quo(power, new(big.Float).SetPrec(prec).SetInt64(2*k+1))
+		if k%2 == 0 {
+			sum.Add(sum, term)
+		} else {
+			sum.Sub(sum, term)
+		}
+		if term.Cmp(eps) < 0 {
+			return sum
+		}
+		power.Quo(power, x2)
+	}
+}
+
+// bigE вычисляет число e с точностью prec бит как сумму ряда 1/k!
+func bigE(prec uint) *big.Float {
+	sum := new(big.Float).SetPrec(prec).SetInt64(1)
+	term := new(big.Float).SetPrec(prec).SetInt64(1)
+	eps := new(big.Float).SetPrec(prec).SetMantExp(big.NewFloat(1), -int(prec))
+
+	for k := int64(1); term.Cmp(eps) >= 0; k++ {
+		term.Quo(term, new(big.Float).SetPrec(prec).SetInt64(k))
+		sum.Add(sum, term)
+	}
+	return sum
+}
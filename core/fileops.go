@@ -0,0 +1,189 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Exists проверяет, существует ли файл или папка
+func (fs *FileSystem) Exists(name string) bool {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Lstat(path)
+	return err == nil
+}
+
+// CopyPath копирует файл или папку со всем содержимым в dst. Файл dst не
+// должен существовать; права доступа и символические ссылки сохраняются.
+func (fs *FileSystem) CopyPath(src, dst string) error {
+	srcPath, dstPath, err := fs.resolvePair(src, dst)
+	if err != nil {
+		return err
+	}
+	if isInside(dstPath, srcPath) {
+		return fmt.Errorf("нельзя скопировать папку %s в саму себя", filepath.Base(srcPath))
+	}
+	if _, err := os.Lstat(dstPath); err == nil {
+		return fmt.Errorf("%s уже существует", fs.VirtualPath(dstPath))
+	}
+
+	if err := copyTree(srcPath, dstPath); err != nil {
+		// Не оставляем наполовину скопированную папку
+		os.RemoveAll(dstPath)
+		return err
+	}
+	fs.publish(EventFileCreated, dstPath)
+	return nil
+}
+
+// MovePath перемещает файл или папку в dst. Если dst находится на другом
+// диске, содержимое копируется, а исходный файл удаляется.
+func (fs *FileSystem) MovePath(src, dst string) error {
+	srcPath, dstPath, err := fs.resolvePair(src, dst)
+	if err != nil {
+		return err
+	}
+	if srcPath == dstPath {
+		return nil
+	}
+	if isInside(dstPath, srcPath) {
+		return fmt.Errorf("нельзя переместить папку %s в саму себя", filepath.Base(srcPath))
+	}
+	if _, err := os.Lstat(dstPath); err == nil {
+		return fmt.Errorf("%s уже существует", fs.VirtualPath(dstPath))
+	}
+
+	err = os.Rename(srcPath, dstPath)
+	if errors.Is(err, syscall.EXDEV) {
+		if err = copyTree(srcPath, dstPath); err != nil {
+			// Исходный файл не тронут, наполовину скопированная копия удаляется
+			os.RemoveAll(dstPath)
+			return err
+		}
+		err = os.RemoveAll(srcPath)
+	}
+	if err != nil {
+		return err
+	}
+	fs.Config.Events.Publish(Event{Type: EventFileRenamed, Path: fs.VirtualPath(dstPath), OldPath: fs.VirtualPath(srcPath)})
+	return nil
+}
+
+// RemovePath удаляет файл или папку со всем содержимым
+func (fs *FileSystem) RemovePath(name string) error {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return err
+	}
+	if path == filepath.Clean(fs.Config.RootDir) {
+		return fmt.Errorf("нельзя удалить корневую папку")
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	fs.publish(EventFileDeleted, path)
+	return nil
+}
+
+// CopyName возвращает свободный путь для копии файла name в папке dir:
+// "отчет (копия).txt", затем "отчет (копия 2).txt" и так далее
+func (fs *FileSystem) CopyName(dir, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "" {
+		// Скрытые файлы вроде ".profile" целиком считаются именем
+		base, ext = name, ""
+	}
+
+	for i := 1; ; i++ {
+		suffix := " (копия)"
+		if i > 1 {
+			suffix = fmt.Sprintf(" (копия %d)", i)
+		}
+		path := filepath.ToSlash(filepath.Join(dir, base+suffix+ext))
+		if !fs.Exists(path) {
+			return path
+		}
+	}
+}
+
+// resolvePair преобразует исходный и целевой пути в абсолютные
+func (fs *FileSystem) resolvePair(src, dst string) (string, string, error) {
+	srcPath, err := fs.ResolvePath(src)
+	if err != nil {
+		return "", "", err
+	}
+	dstPath, err := fs.ResolvePath(dst)
+	if err != nil {
+		return "", "", err
+	}
+	return srcPath, dstPath, nil
+}
+
+// isInside проверяет, совпадает ли path с dir или находится внутри нее
+func isInside(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// copyTree рекурсивно копирует src в dst
+func copyTree(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	case info.IsDir():
+		if err := os.Mkdir(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := copyTree(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return copyRegularFile(src, dst, info.Mode().Perm())
+	}
+}
+
+// copyRegularFile копирует содержимое файла src в новый файл dst с правами perm
+func copyRegularFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
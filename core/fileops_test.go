@@ -0,0 +1,162 @@
+package core
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestFileSystem создает файловую систему MixailOS во временной папке
+func newTestFileSystem(t *testing.T) (*FileSystem, string) {
+	root := t.TempDir()
+	return NewFileSystem(NewConfig(root)), root
+}
+
+// writeTestFile создает файл root/name с содержимым content и правами perm
+func writeTestFile(t *testing.T, root, name, content string, perm os.FileMode) {
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyPathRecursive(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	writeTestFile(t, root, "src/a.txt", "первый", 0600)
+	writeTestFile(t, root, "src/sub/run.sh", "#!/bin/sh", 0755)
+	if err := os.Symlink("a.txt", filepath.Join(root, "src", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.CopyPath("/src", "/dst"); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"dst/a.txt": "первый", "dst/sub/run.sh": "#!/bin/sh"} {
+		data, err := ioutil.ReadFile(filepath.Join(root, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; ожидалось %q", name, data, err, want)
+		}
+	}
+	for name, want := range map[string]os.FileMode{"dst/a.txt": 0600, "dst/sub/run.sh": 0755} {
+		if info, err := os.Stat(filepath.Join(root, name)); err != nil || info.Mode().Perm() != want {
+			t.Errorf("права %s = %v, %v; ожидалось %v", name, info.Mode().Perm(), err, want)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(root, "dst", "link")); err != nil || target != "a.txt" {
+		t.Errorf("символическая ссылка: %q, %v", target, err)
+	}
+	// Исходная папка не изменилась
+	if !fs.Exists("/src/sub/run.sh") {
+		t.Error("исходный файл пропал после копирования")
+	}
+}
+
+func TestCopyPathErrors(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	writeTestFile(t, root, "src/a.txt", "a", 0644)
+	writeTestFile(t, root, "other.txt", "b", 0644)
+
+	if err := fs.CopyPath("/src", "/src/inner"); err == nil || !strings.Contains(err.Error(), "в саму себя") {
+		t.Errorf("копирование папки в себя: err = %v", err)
+	}
+	if err := fs.MovePath("/src", "/src/inner"); err == nil || !strings.Contains(err.Error(), "в саму себя") {
+		t.Errorf("перемещение папки в себя: err = %v", err)
+	}
+	if fs.Exists("/src/inner") {
+		t.Error("после ошибки осталась папка /src/inner")
+	}
+
+	for _, op := range []func(string, string) error{fs.CopyPath, fs.MovePath} {
+		if err := op("/other.txt", "/src/a.txt"); err == nil || !strings.Contains(err.Error(), "уже существует") {
+			t.Errorf("вставка поверх существующего файла: err = %v", err)
+		}
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(root, "src", "a.txt")); string(data) != "a" {
+		t.Errorf("существующий файл изменен: %q", data)
+	}
+}
+
+func TestCopyPathRemovesPartialCopy(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	writeTestFile(t, root, "src/a.txt", "a", 0644)
+	// Сокет нельзя открыть как файл, поэтому копирование папки прерывается на нем
+	listener, err := net.Listen("unix", filepath.Join(root, "src", "z.sock"))
+	if err != nil {
+		t.Skip("не удалось создать сокет:", err)
+	}
+	defer listener.Close()
+
+	if err := fs.CopyPath("/src", "/dst"); err == nil {
+		t.Fatal("ожидалась ошибка копирования сокета")
+	}
+	if fs.Exists("/dst") {
+		t.Error("после ошибки осталась наполовину скопированная папка /dst")
+	}
+}
+
+func TestMoveAndRemovePath(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	writeTestFile(t, root, "src/a.txt", "a", 0644)
+
+	if err := fs.MovePath("/src", "/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if fs.Exists("/src") || !fs.Exists("/moved/a.txt") {
+		t.Error("папка не перемещена")
+	}
+	if err := fs.RemovePath("/moved"); err != nil || fs.Exists("/moved") {
+		t.Errorf("удаление папки: %v", err)
+	}
+	if err := fs.RemovePath("/"); err == nil {
+		t.Error("корневую папку удалять нельзя")
+	}
+	if err := fs.RemovePath("/missing"); err == nil {
+		t.Error("удаление несуществующего файла: ожидалась ошибка")
+	}
+}
+
+func TestCopyName(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	writeTestFile(t, root, "docs/отчет.txt", "", 0644)
+	writeTestFile(t, root, "docs/.profile", "", 0644)
+
+	if got := fs.CopyName("/docs", "отчет.txt"); got != "/docs/отчет (копия).txt" {
+		t.Errorf("первая копия: %q", got)
+	}
+	writeTestFile(t, root, "docs/отчет (копия).txt", "", 0644)
+	if got := fs.CopyName("/docs", "отчет.txt"); got != "/docs/отчет (копия 2).txt" {
+		t.Errorf("вторая копия: %q", got)
+	}
+	// Скрытый файл целиком считается именем, а не расширением
+	if got := fs.CopyName("/docs", ".profile"); got != "/docs/.profile (копия)" {
+		t.Errorf("копия скрытого файла: %q", got)
+	}
+}
+
+func TestIsInside(t *testing.T) {
+	sep := string(filepath.Separator)
+	dir := sep + "a" + sep + "b"
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{dir, true},
+		{dir + sep + "c", true},
+		{dir + "c", false},
+		{sep + "a", false},
+	}
+	for _, tt := range tests {
+		if got := isInside(tt.path, dir); got != tt.want {
+			t.Errorf("isInside(%q, %q) = %v", tt.path, dir, got)
+		}
+	}
+}
@@ -2,10 +2,12 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // FileSystem представляет файловую систему MixailOS
@@ -20,23 +22,58 @@ func NewFileSystem(config *Config) *FileSystem {
 	}
 }
 
-// ListFiles возвращает список файлов и директорий в текущей директории
-func (fs *FileSystem) ListFiles() ([]string, error) {
-	files, err := ioutil.ReadDir(fs.Config.CurrentDir)
+// FileEntry - файл или директория в списке содержимого директории
+type FileEntry struct {
+	Name string
+	// Path - путь внутри MixailOS, например "/Documents/welcome.txt"
+	Path    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+	IsDir   bool
+	// Owner - владелец файла в основной системе; пустой, если его не удалось определить
+	Owner string
+	// MIMEType - тип файла; для директорий MIMEDirectory
+	MIMEType string
+}
+
+// ListFiles возвращает файлы и директории текущей директории, отсортированные по имени
+func (fs *FileSystem) ListFiles() ([]FileEntry, error) {
+	return fs.ListDir(fs.Config.CurrentDir)
+}
+
+// ListDir возвращает файлы и директории директории name, отсортированные по имени
+func (fs *FileSystem) ListDir(name string) ([]FileEntry, error) {
+	dir, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
 	
-	var fileNames []string
+	entries := make([]FileEntry, 0, len(files))
 	for _, file := range files {
-		fileType := "file"
-		if file.IsDir() {
-			fileType = "dir"
+		path := filepath.Join(dir, file.Name())
+		entry := FileEntry{
+			Name:    file.Name(),
+			Path:    fs.VirtualPath(path),
+			Size:    file.Size(),
+			Mode:    file.Mode(),
+			ModTime: file.ModTime(),
+			IsDir:   file.IsDir(),
+			Owner:   fileOwner(file),
+		}
+		if entry.IsDir {
+			entry.Size = 0
+			entry.MIMEType = MIMEDirectory
+		} else if entry.MIMEType = MIMETypeByExtension(filepath.Ext(file.Name())); entry.MIMEType == "" {
+			entry.MIMEType = fs.sniffMIME(path)
 		}
-		fileNames = append(fileNames, fmt.Sprintf("%s (%s)", file.Name(), fileType))
+		entries = append(entries, entry)
 	}
-	
-	return fileNames, nil
+	return entries, nil
 }
 
 // ChangeDirectory изменяет текущую директорию
@@ -83,7 +120,12 @@ func (fs *FileSystem) CreateTextFile(name string, content string) error {
 	}
 	
 	path := filepath.Join(fs.Config.CurrentDir, name)
-	return ioutil.WriteFile(path, []byte(content), 0644)
+	event := writeEvent(path)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	fs.publish(event, path)
+	return nil
 }
 
 // ReadTextFile читает текстовый файл
@@ -104,21 +146,32 @@ func (fs *FileSystem) ReadTextFile(name string) (string, error) {
 
 // DeleteFile удаляет файл
 func (fs *FileSystem) DeleteFile(name string) error {
-	path := filepath.Join(fs.Config.CurrentDir, name)
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return err
+	}
 	
 	// Проверяем, существует ли файл
-	_, err := os.Stat(path)
+	_, err = os.Stat(path)
 	if err != nil {
 		return err
 	}
 	
-	return os.Remove(path)
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	fs.publish(EventFileDeleted, path)
+	return nil
 }
 
 // CreateDirectory создает новую директорию
 func (fs *FileSystem) CreateDirectory(name string) error {
 	path := filepath.Join(fs.Config.CurrentDir, name)
-	return os.Mkdir(path, 0755)
+	if err := os.Mkdir(path, 0755); err != nil {
+		return err
+	}
+	fs.publish(EventFileCreated, path)
+	return nil
 }
 
 // CopyFile копирует файл
@@ -131,5 +184,159 @@ func (fs *FileSystem) CopyFile(src, dst string) error {
 		return err
 	}
 	
-	return ioutil.WriteFile(dstPath, input, 0644)
-} 
\ No newline at end of file
+	event := writeEvent(dstPath)
+	if err := ioutil.WriteFile(dstPath, input, 0644); err != nil {
+		return err
+	}
+	fs.publish(event, dstPath)
+	return nil
+}
+
+// ResolvePath преобразует имя файла в абсолютный путь.
+// Относительные пути отсчитываются от текущей директории, пути, начинающиеся с "/", -
+// от рабочей директории MixailOS. Выход за пределы рабочей директории запрещен.
+func (fs *FileSystem) ResolvePath(name string) (string, error) {
+	var path string
+	switch {
+	case strings.HasPrefix(name, fs.Config.RootDir):
+		path = filepath.Clean(name)
+	case strings.HasPrefix(name, "/") || strings.HasPrefix(name, "\\"):
+		path = filepath.Join(fs.Config.RootDir, name)
+	default:
+		path = filepath.Join(fs.Config.CurrentDir, name)
+	}
+	
+	root := filepath.Clean(fs.Config.RootDir)
+	if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("недопустимый путь: %s", name)
+	}
+	return path, nil
+}
+
+// VirtualPath возвращает путь внутри MixailOS (относительно рабочей директории) в виде "/Documents/file.txt"
+func (fs *FileSystem) VirtualPath(path string) string {
+	rel, err := filepath.Rel(fs.Config.RootDir, path)
+	if err != nil || rel == "." {
+		return "/"
+	}
+	return "/" + filepath.ToSlash(rel)
+}
+
+// CreateDirectories создает директорию вместе со всеми недостающими родительскими
+func (fs *FileSystem) CreateDirectories(name string) error {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil
+	}
+	
+	// Сообщаем о самой верхней из создаваемых папок: она появится в существующей папке
+	created := path
+	for {
+		parent := filepath.Dir(created)
+		if _, err := os.Stat(parent); err == nil || parent == created {
+			break
+		}
+		created = parent
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	fs.publish(EventFileCreated, created)
+	return nil
+}
+
+// ReadFile читает файл с произвольным расширением
+func (fs *FileSystem) ReadFile(name string) ([]byte, error) {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
+
+// WriteFile записывает файл с произвольным расширением
+func (fs *FileSystem) WriteFile(name string, data []byte) error {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return err
+	}
+	event := writeEvent(path)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	fs.publish(event, path)
+	return nil
+}
+
+// Stat возвращает информацию о файле
+func (fs *FileSystem) Stat(name string) (os.FileInfo, error) {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(path)
+}
+
+// OpenReader открывает файл для потокового чтения
+func (fs *FileSystem) OpenReader(name string) (io.ReadCloser, error) {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// OpenWriter открывает файл для потоковой записи. При appendMode данные дописываются в конец,
+// иначе файл перезаписывается.
+func (fs *FileSystem) OpenWriter(name string, appendMode bool) (io.WriteCloser, error) {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendMode {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	event := writeEvent(path)
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fs.publish(event, path)
+	return f, nil
+}
+
+// RenameFile переименовывает или перемещает файл
+func (fs *FileSystem) RenameFile(oldName, newName string) error {
+	oldPath, err := fs.ResolvePath(oldName)
+	if err != nil {
+		return err
+	}
+	newPath, err := fs.ResolvePath(newName)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+	fs.Config.Events.Publish(Event{Type: EventFileRenamed, Path: fs.VirtualPath(newPath), OldPath: fs.VirtualPath(oldPath)})
+	return nil
+}
+
+// publish сообщает подписчикам шины событий об изменении файла path
+func (fs *FileSystem) publish(t EventType, path string) {
+	fs.Config.Events.Publish(Event{Type: t, Path: fs.VirtualPath(path)})
+}
+
+// writeEvent возвращает событие, которое вызовет запись в path:
+// создание нового файла или изменение существующего
+func writeEvent(path string) EventType {
+	if _, err := os.Stat(path); err == nil {
+		return EventFileChanged
+	}
+	return EventFileCreated
+}
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MIMEDirectory - тип, которым обозначаются папки
+const MIMEDirectory = "inode/directory"
+
+// MIMEUnknown - тип файлов, формат которых не удалось определить
+const MIMEUnknown = "application/octet-stream"
+
+// mimeSniffLen - сколько первых байт файла читается для определения типа по содержимому
+const mimeSniffLen = 512
+
+// fileExtensions - типы файлов по расширению. Дополняют стандартную таблицу
+// mime.TypeByExtension, которая на разных системах отличается.
+var fileExtensions = map[string]string{
+	".txt":  "text/plain",
+	".log":  "text/plain",
+	".ini":  "text/plain",
+	".conf": "text/plain",
+	".md":   "text/markdown",
+	".csv":  "text/csv",
+	".go":   "text/x-go",
+	".py":   "text/x-python",
+	".c":    "text/x-c",
+	".h":    "text/x-c",
+	".sh":   "text/x-shellscript",
+	".html": "text/html",
+	".htm":  "text/html",
+	".css":  "text/css",
+	".js":   "text/javascript",
+	".json": "application/json",
+	".xml":  "application/xml",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".bmp":  "image/bmp",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".ogg":  "audio/ogg",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+}
+
+// MIMETypeByExtension возвращает тип файла по расширению (".png" или "png")
+// или пустую строку, если расширение неизвестно
+func MIMETypeByExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if mimeType, ok := fileExtensions[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return stripMIMEParams(mimeType)
+	}
+	return ""
+}
+
+// stripMIMEParams убирает параметры типа: "text/plain; charset=utf-8" -> "text/plain"
+func stripMIMEParams(mimeType string) string {
+	return strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0])
+}
+
+// MIMEType определяет тип файла: сначала по расширению, затем по первым байтам
+// содержимого. Для папок возвращается MIMEDirectory, для файлов неизвестного
+// формата - MIMEUnknown.
+func (fs *FileSystem) MIMEType(name string) string {
+	if info, err := fs.Stat(name); err == nil && info.IsDir() {
+		return MIMEDirectory
+	}
+	if mimeType := MIMETypeByExtension(filepath.Ext(name)); mimeType != "" {
+		return mimeType
+	}
+	return fs.sniffMIME(name)
+}
+
+// sniffMIME определяет тип файла по содержимому
+func (fs *FileSystem) sniffMIME(name string) string {
+	r, err := fs.OpenReader(name)
+	if err != nil {
+		return MIMEUnknown
+	}
+	defer r.Close()
+
+	buf := make([]byte, mimeSniffLen)
+	n, err := io.ReadFull(r, buf)
+	if n == 0 || (err != nil && err != io.ErrUnexpectedEOF) {
+		return MIMEUnknown
+	}
+	return stripMIMEParams(http.DetectContentType(buf[:n]))
+}
+
+// mimeKeys возвращает ключи поиска ассоциации для типа: сам тип и шаблон "image/*"
+func mimeKeys(mimeType string) []string {
+	major := strings.SplitN(mimeType, "/", 2)[0]
+	return []string{mimeType, major + "/*"}
+}
+
+// AssociatedApp возвращает приложение, назначенное для типа файлов. Сначала
+// проверяются назначения текущего пользователя, затем системные DefaultApps.
+func (c *Config) AssociatedApp(mimeType string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, key := range mimeKeys(mimeType) {
+		if id, ok := c.Associations[c.Username][key]; ok {
+			return id, true
+		}
+	}
+	for _, key := range mimeKeys(mimeType) {
+		if id, ok := c.DefaultApps[key]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// FileAssociation - приложение, назначенное для типа файлов
+type FileAssociation struct {
+	MIMEType string
+	AppID    string
+}
+
+// UserAssociations возвращает назначения текущего пользователя, отсортированные по типу
+func (c *Config) UserAssociations() []FileAssociation {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var list []FileAssociation
+	for mimeType, id := range c.Associations[c.Username] {
+		list = append(list, FileAssociation{MIMEType: mimeType, AppID: id})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].MIMEType < list[j].MIMEType })
+	return list
+}
+
+// SetAssociation назначает текущему пользователю приложение для типа файлов.
+// Приложение должно уметь открывать файлы.
+func (c *Config) SetAssociation(mimeType string, app AppInfo) error {
+	if !strings.Contains(mimeType, "/") {
+		return fmt.Errorf("некорректный тип файлов: %s", mimeType)
+	}
+	if !app.OpensFiles {
+		return fmt.Errorf("приложение %s не открывает файлы", app.Name)
+	}
+	return c.update(func() error {
+		if c.Associations == nil {
+			c.Associations = map[string]map[string]string{}
+		}
+		if c.Associations[c.Username] == nil {
+			c.Associations[c.Username] = map[string]string{}
+		}
+		c.Associations[c.Username][mimeType] = app.ID
+		return nil
+	})
+}
+
+// RemoveAssociation удаляет назначение текущего пользователя для типа файлов
+func (c *Config) RemoveAssociation(mimeType string) error {
+	return c.update(func() error {
+		if _, ok := c.Associations[c.Username][mimeType]; !ok {
+			return fmt.Errorf("для типа %s приложение не назначено", mimeType)
+		}
+		delete(c.Associations[c.Username], mimeType)
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"testing"
+)
+
+func TestMIMETypeByExtension(t *testing.T) {
+	tests := []struct {
+		ext  string
+		want string
+	}{
+		{".png", "image/png"},
+		{"PNG", "image/png"},
+		{".md", "text/markdown"},
+		{".go", "text/x-go"},
+		// Таблица MixailOS важнее системной и не содержит параметров
+		{".js", "text/javascript"},
+		{".html", "text/html"},
+		// Расширения, которых нет в таблице, берутся из mime.TypeByExtension
+		{".wasm", "application/wasm"},
+		{".zzz", ""},
+	}
+	for _, tt := range tests {
+		if got := MIMETypeByExtension(tt.ext); got != tt.want {
+			t.Errorf("MIMETypeByExtension(%q) = %q, ожидалось %q", tt.ext, got, tt.want)
+		}
+	}
+}
+
+func TestMIMETypeSniff(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, root, "picture", buf.String(), 0644)
+	writeTestFile(t, root, "notes", "Просто текст без расширения\n", 0644)
+	writeTestFile(t, root, "data.zzz", "текст с неизвестным расширением", 0644)
+	writeTestFile(t, root, "blob", "\x00\x01\x02\x03\xff", 0644)
+	writeTestFile(t, root, "empty", "", 0644)
+	// Расширение важнее содержимого
+	writeTestFile(t, root, "fake.png", "на самом деле текст", 0644)
+	if err := os.Mkdir(root+"/folder.png", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"/picture", "image/png"},
+		{"/notes", "text/plain"},
+		{"/data.zzz", "text/plain"},
+		{"/blob", MIMEUnknown},
+		{"/empty", MIMEUnknown},
+		{"/missing", MIMEUnknown},
+		{"/fake.png", "image/png"},
+		{"/folder.png", MIMEDirectory},
+	}
+	for _, tt := range tests {
+		if got := fs.MIMEType(tt.name); got != tt.want {
+			t.Errorf("MIMEType(%q) = %q, ожидалось %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssociatedApp(t *testing.T) {
+	config := NewConfig(t.TempDir())
+	config.Username = "User"
+	config.DefaultApps = map[string]string{"image/*": "viewer", "text/plain": "editor"}
+	paint := AppInfo{ID: "paint", Name: "Рисование", OpensFiles: true}
+
+	check := func(mimeType, want string) {
+		t.Helper()
+		id, ok := config.AssociatedApp(mimeType)
+		if want == "" && ok {
+			t.Errorf("AssociatedApp(%q) = %q, ожидалось отсутствие назначения", mimeType, id)
+		}
+		if want != "" && (!ok || id != want) {
+			t.Errorf("AssociatedApp(%q) = %q, %v; ожидалось %q", mimeType, id, ok, want)
+		}
+	}
+
+	// Шаблон "image/*" подходит для всех изображений
+	check("image/png", "viewer")
+	check("text/plain", "editor")
+	check("video/mp4", "")
+
+	// Назначение пользователя важнее системного
+	if err := config.SetAssociation("image/png", paint); err != nil {
+		t.Fatal(err)
+	}
+	check("image/png", "paint")
+	check("image/gif", "viewer")
+	if err := config.SetAssociation("text/*", paint); err != nil {
+		t.Fatal(err)
+	}
+	// Точный системный тип не перекрывает шаблон пользователя
+	check("text/plain", "paint")
+	if list := config.UserAssociations(); len(list) != 2 || list[0].MIMEType != "image/png" || list[1].MIMEType != "text/*" {
+		t.Errorf("UserAssociations = %v", list)
+	}
+
+	// Назначения хранятся отдельно для каждого пользователя
+	if err := config.ChangeUsername("Гость"); err != nil {
+		t.Fatal(err)
+	}
+	check("image/png", "viewer")
+	if err := config.ChangeUsername("User"); err != nil {
+		t.Fatal(err)
+	}
+	check("image/png", "paint")
+
+	if err := config.RemoveAssociation("image/png"); err != nil {
+		t.Fatal(err)
+	}
+	check("image/png", "viewer")
+	if err := config.RemoveAssociation("image/png"); err == nil {
+		t.Error("удаление отсутствующего назначения должно вернуть ошибку")
+	}
+
+	// Приложение, которое не открывает файлы, назначить нельзя
+	if err := config.SetAssociation("image/png", AppInfo{ID: "calc", Name: "Калькулятор"}); err == nil {
+		t.Error("назначено приложение, которое не открывает файлы")
+	}
+	if err := config.SetAssociation("png", paint); err == nil {
+		t.Error("принят тип без косой черты")
+	}
+	check("image/png", "viewer")
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+// Режимы отображения папки в файловом менеджере
+const (
+	// ViewList - таблица с колонками имени, размера, типа и даты изменения
+	ViewList = "list"
+	// ViewGrid - сетка небольших значков
+	ViewGrid = "grid"
+	// ViewIcons - крупные значки
+	ViewIcons = "icons"
+)
+
+// Колонки, по которым сортируется содержимое папки
+const (
+	SortByName     = "name"
+	SortBySize     = "size"
+	SortByType     = "type"
+	SortByModified = "modified"
+)
+
+// FolderView - настройки отображения папки в файловом менеджере
+type FolderView struct {
+	Mode       string `json:"mode"`
+	SortBy     string `json:"sortBy"`
+	Descending bool   `json:"descending"`
+	// ShowHidden - показывать скрытые файлы, имена которых начинаются с точки
+	ShowHidden bool `json:"showHidden"`
+}
+
+// DefaultFolderView - настройки папки, которую пользователь еще не настраивал
+var DefaultFolderView = FolderView{Mode: ViewList, SortBy: SortByName}
+
+// IsHidden проверяет, является ли файл скрытым (имя начинается с точки)
+func (e FileEntry) IsHidden() bool {
+	return strings.HasPrefix(e.Name, ".")
+}
+
+// FilterFileEntries оставляет файлы, имя которых содержит query (без учета регистра).
+// Скрытые файлы остаются только при showHidden.
+func FilterFileEntries(entries []FileEntry, query string, showHidden bool) []FileEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var filtered []FileEntry
+	for _, e := range entries {
+		if e.IsHidden() && !showHidden {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(e.Name), query) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// SortFileEntries сортирует файлы по колонке sortBy. Папки всегда идут перед
+// файлами, одинаковые значения упорядочиваются по имени.
+func SortFileEntries(entries []FileEntry, sortBy string, descending bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+
+		var less, equal bool
+		switch sortBy {
+		case SortBySize:
+			less, equal = a.Size < b.Size, a.Size == b.Size
+		case SortByType:
+			less, equal = a.MIMEType < b.MIMEType, a.MIMEType == b.MIMEType
+		case SortByModified:
+			less, equal = a.ModTime.Before(b.ModTime), a.ModTime.Equal(b.ModTime)
+		}
+		if sortBy == SortByName || equal {
+			nameA, nameB := strings.ToLower(a.Name), strings.ToLower(b.Name)
+			less, equal = nameA < nameB, nameA == nameB
+			if equal {
+				less = a.Name < b.Name
+			}
+		}
+		if descending {
+			return !less && !equal
+		}
+		return less
+	})
+}
+
+// FolderViewStore хранит настройки отображения папок текущего пользователя
+// в файловой системе MixailOS
+type FolderViewStore struct {
+	FileSystem *FileSystem
+}
+
+// NewFolderViewStore создает хранилище настроек папок
+func NewFolderViewStore(fs *FileSystem) *FolderViewStore {
+	return &FolderViewStore{
+		FileSystem: fs,
+	}
+}
+
+// load читает настройки всех папок; отсутствующий файл не считается ошибкой
+func (s *FolderViewStore) load() (map[string]FolderView, error) {
+	views := map[string]FolderView{}
+	err := s.FileSystem.loadUserJSON("files", "folders.json", &views)
+	return views, err
+}
+
+// Get возвращает настройки папки dir (путь внутри MixailOS) или DefaultFolderView
+func (s *FolderViewStore) Get(dir string) FolderView {
+	views, _ := s.load()
+	view, ok := views[dir]
+	if !ok {
+		return DefaultFolderView
+	}
+	if view.Mode == "" {
+		view.Mode = DefaultFolderView.Mode
+	}
+	if view.SortBy == "" {
+		view.SortBy = DefaultFolderView.SortBy
+	}
+	return view
+}
+
+// Set сохраняет настройки папки dir
+func (s *FolderViewStore) Set(dir string, view FolderView) error {
+	views, err := s.load()
+	if err != nil {
+		return err
+	}
+	if view == DefaultFolderView {
+		delete(views, dir)
+	} else {
+		views[dir] = view
+	}
+	return s.FileSystem.saveUserJSON("files", "folders.json", views)
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// ImageMIMETypes - форматы изображений, которые умеет декодировать MixailOS
+var ImageMIMETypes = []string{"image/png", "image/jpeg", "image/gif", "image/bmp", "image/webp"}
+
+// IsImageFile проверяет по расширению, является ли файл поддерживаемым изображением
+func IsImageFile(name string) bool {
+	mimeType := MIMETypeByExtension(filepath.Ext(name))
+	for _, t := range ImageMIMETypes {
+		if t == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageInfo - сведения об изображении
+type ImageInfo struct {
+	// Path - путь внутри MixailOS
+	Path    string
+	Format  string
+	Width   int
+	Height  int
+	Size    int64
+	ModTime time.Time
+	// ColorModel - описание цветовой модели: "RGBA", "Палитра, 256 цветов"
+	ColorModel string
+}
+
+// String возвращает сведения об изображении одной строкой
+func (i ImageInfo) String() string {
+	return fmt.Sprintf("%s  |  %d x %d  |  %s  |  %s  |  %s  |  %s",
+		filepath.Base(i.Path), i.Width, i.Height, strings.ToUpper(i.Format), i.ColorModel,
+		FormatSize(i.Size), i.ModTime.Format("02.01.2006 15:04"))
+}
+
+// LoadImage читает и декодирует изображение PNG, JPEG, GIF, BMP или WebP.
+// У анимированных GIF возвращается первый кадр.
+func (fs *FileSystem) LoadImage(name string) (image.Image, ImageInfo, error) {
+	info := ImageInfo{}
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, info, err
+	}
+	stat, err := fs.Stat(path)
+	if err != nil {
+		return nil, info, err
+	}
+	r, err := fs.OpenReader(path)
+	if err != nil {
+		return nil, info, err
+	}
+	defer r.Close()
+
+	img, format, err := image.Decode(r)
+	if err != nil {
+		return nil, info, fmt.Errorf("не удалось прочитать изображение %s: %v", filepath.Base(path), err)
+	}
+	b := img.Bounds()
+	info = ImageInfo{
+		Path:       fs.VirtualPath(path),
+		Format:     format,
+		Width:      b.Dx(),
+		Height:     b.Dy(),
+		Size:       stat.Size(),
+		ModTime:    stat.ModTime(),
+		ColorModel: colorModelName(img),
+	}
+	return img, info, nil
+}
+
+// colorModelName описывает цветовую модель изображения
+func colorModelName(img image.Image) string {
+	if p, ok := img.ColorModel().(color.Palette); ok {
+		return fmt.Sprintf("Палитра, %d цветов", len(p))
+	}
+	switch img.ColorModel() {
+	case color.RGBAModel, color.NRGBAModel:
+		return "RGBA"
+	case color.RGBA64Model, color.NRGBA64Model:
+		return "RGBA, 16 бит"
+	case color.GrayModel:
+		return "Оттенки серого"
+	case color.Gray16Model:
+		return "Оттенки серого, 16 бит"
+	case color.YCbCrModel:
+		return "YCbCr"
+	case color.CMYKModel:
+		return "CMYK"
+	}
+	return "Другая"
+}
+
+// ImagesInFolder возвращает отсортированные пути изображений в папке,
+// где находится файл name
+func (fs *FileSystem) ImagesInFolder(name string) ([]string, error) {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, f := range files {
+		if !f.IsDir() && IsImageFile(f.Name()) {
+			images = append(images, fs.VirtualPath(filepath.Join(dir, f.Name())))
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return strings.ToLower(images[i]) < strings.ToLower(images[j])
+	})
+	return images, nil
+}
+
+// RotateImage поворачивает изображение на turns четвертей оборота по часовой стрелке
+func RotateImage(src image.Image, turns int) image.Image {
+	turns = ((turns % 4) + 4) % 4
+	if turns == 0 {
+		return src
+	}
+
+	// Пиксели копируются напрямую между буферами RGBA: At/Set для каждого
+	// пикселя слишком медленны для фотографий
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(src.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	}
+
+	b := rgba.Bounds()
+	w, h := b.Dx(), b.Dy()
+	var dst *image.RGBA
+	if turns == 2 {
+		dst = image.NewRGBA(image.Rect(0, 0, w, h))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+	for y := 0; y < h; y++ {
+		row := rgba.Pix[y*rgba.Stride:]
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch turns {
+			case 1:
+				dx, dy = h-1-y, x
+			case 2:
+				dx, dy = w-1-x, h-1-y
+			case 3:
+				dx, dy = y, w-1-x
+			}
+			i := dy*dst.Stride + dx*4
+			copy(dst.Pix[i:i+4], row[x*4:x*4+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestRotateImage(t *testing.T) {
+	// Изображение 3x2 в палитре, чтобы проверить преобразование в RGBA:
+	// в каждом пикселе номер цвета равен 4*y + x
+	palette := color.Palette{}
+	for i := 0; i < 16; i++ {
+		palette = append(palette, color.RGBA{R: uint8(i), A: 0xff})
+	}
+	src := image.NewPaletted(image.Rect(0, 0, 3, 2), palette)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.SetColorIndex(x, y, uint8(4*y+x))
+		}
+	}
+
+	tests := []struct {
+		turns int
+		rows  [][]uint8
+	}{
+		{1, [][]uint8{{4, 0}, {5, 1}, {6, 2}}},
+		{2, [][]uint8{{6, 5, 4}, {2, 1, 0}}},
+		{3, [][]uint8{{2, 6}, {1, 5}, {0, 4}}},
+		{-1, [][]uint8{{2, 6}, {1, 5}, {0, 4}}},
+	}
+	for _, tt := range tests {
+		dst := RotateImage(src, tt.turns)
+		if b := dst.Bounds(); b.Dx() != len(tt.rows[0]) || b.Dy() != len(tt.rows) {
+			t.Errorf("поворот %d: размер %v", tt.turns, b)
+			continue
+		}
+		for y, row := range tt.rows {
+			for x, want := range row {
+				if r, _, _, _ := dst.At(x, y).RGBA(); uint8(r>>8) != want {
+					t.Errorf("поворот %d: пиксель (%d, %d) = %d, ожидалось %d", tt.turns, x, y, r>>8, want)
+				}
+			}
+		}
+	}
+	if RotateImage(src, 4) != image.Image(src) {
+		t.Error("полный оборот должен возвращать исходное изображение")
+	}
+}
@@ -0,0 +1,257 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// InternalPages - встроенные страницы браузера, доступные по адресу mixail://<имя>
+var InternalPages = []string{"about", "settings", "help"}
+
+// fetchFile открывает файл или директорию файловой системы MixailOS по адресу file:///путь
+func (b *Browser) fetchFile(u *url.URL) (*Page, error) {
+	if b.FileSystem == nil {
+		return nil, fmt.Errorf("локальные файлы недоступны")
+	}
+
+	// file://Documents/x.txt воспринимаем как file:///Documents/x.txt
+	name := u.Path
+	if u.Host != "" && u.Host != "localhost" {
+		name = "/" + u.Host + u.Path
+	}
+	if name == "" {
+		name = "/"
+	}
+	u = &url.URL{Scheme: "file", Path: path.Clean(name)}
+
+	info, err := b.FileSystem.Stat(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return b.directoryPage(u)
+	}
+
+	in, err := b.FileSystem.OpenReader(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	page := &Page{URL: u.String(), StatusCode: http.StatusOK}
+	ext := strings.ToLower(path.Ext(u.Path))
+	if ext == ".html" || ext == ".htm" {
+		page.ContentType = "text/html"
+		if err := page.render(io.LimitReader(in, browserMaxBodySize), u); err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := ioutil.ReadAll(io.LimitReader(in, browserMaxBodySize))
+		if err != nil {
+			return nil, err
+		}
+		page.ContentType = http.DetectContentType(data)
+		if strings.HasPrefix(page.ContentType, "text/") {
+			page.Text = string(data)
+		} else {
+			page.Text = fmt.Sprintf("Содержимое типа %s не может быть отображено", page.ContentType)
+		}
+	}
+
+	if page.Title == "" {
+		page.Title = path.Base(u.Path)
+	}
+	return page, nil
+}
+
+// directoryPage формирует страницу со списком файлов директории
+func (b *Browser) directoryPage(u *url.URL) (*Page, error) {
+	dir, err := b.FileSystem.ResolvePath(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	title := "Содержимое " + u.Path
+	sb.WriteString("<html><head><title>" + html.EscapeString(title) + "</title></head><body>")
+	sb.WriteString("<h1>" + html.EscapeString(title) + "</h1><ul>")
+	if u.Path != "/" {
+		sb.WriteString(pageLink(fileURL(path.Dir(u.Path)), ".."))
+	}
+	for _, file := range files {
+		name := file.Name()
+		target := path.Join(u.Path, name)
+		if file.IsDir() {
+			sb.WriteString(pageLink(fileURL(target), name+"/"))
+		} else {
+			sb.WriteString(pageLink(fileURL(target), fmt.Sprintf("%s (%s)", name, FormatSize(file.Size()))))
+		}
+	}
+	sb.WriteString("</ul></body></html>")
+
+	return b.htmlPage(u, sb.String(), http.StatusOK)
+}
+
+// fetchInternal формирует встроенную страницу mixail://
+func (b *Browser) fetchInternal(u *url.URL) (*Page, error) {
+	name := u.Host
+	if name == "" {
+		name = strings.Trim(u.Opaque+u.Path, "/")
+	}
+	u = &url.URL{Scheme: "mixail", Host: name}
+
+	switch name {
+	case "about":
+		return b.htmlPage(u, b.aboutPage(), http.StatusOK)
+	case "settings":
+		return b.htmlPage(u, b.settingsPage(), http.StatusOK)
+	case "help":
+		return b.htmlPage(u, helpPage(), http.StatusOK)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<html><head><title>Страница не найдена</title></head><body>")
+	sb.WriteString("<h1>Страница не найдена</h1><p>Встроенная страница " + html.EscapeString(u.String()) + " не существует. Доступные страницы:</p><ul>")
+	for _, page := range InternalPages {
+		sb.WriteString(pageLink("mixail://"+page, "mixail://"+page))
+	}
+	sb.WriteString("</ul></body></html>")
+	return b.htmlPage(u, sb.String(), http.StatusNotFound)
+}
+
+// aboutPage формирует страницу mixail://about
+func (b *Browser) aboutPage() string {
+	var sb strings.Builder
+	sb.WriteString("<html><head><title>О MixailOS</title></head><body>")
+	sb.WriteString("<h1>MixailOS</h1><p>Эмулятор операционной системы на Go.</p><table>")
+	rows := [][2]string{
+		{"Версия", "1.0.0"},
+		{"Go", runtime.Version()},
+		{"Платформа", runtime.GOOS + "/" + runtime.GOARCH},
+	}
+	if b.FileSystem != nil {
+		rows = append(rows,
+			[2]string{"Пользователь", b.FileSystem.Config.GetUsername()},
+			[2]string{"Рабочая директория", b.FileSystem.Config.RootDir},
+		)
+	}
+	for _, row := range rows {
+		sb.WriteString("<tr><td>" + html.EscapeString(row[0]) + "</td><td>" + html.EscapeString(row[1]) + "</td></tr>")
+	}
+	sb.WriteString("</table><ul>")
+	sb.WriteString(pageLink("mixail://help", "Справка по командам"))
+	sb.WriteString(pageLink("mixail://settings", "Настройки"))
+	sb.WriteString("</ul></body></html>")
+	return sb.String()
+}
+
+// settingsPage формирует страницу mixail://settings с текущими настройками
+func (b *Browser) settingsPage() string {
+	var sb strings.Builder
+	sb.WriteString("<html><head><title>Настройки</title></head><body><h1>Настройки</h1>")
+	if b.FileSystem == nil {
+		sb.WriteString("<p>Настройки недоступны</p></body></html>")
+		return sb.String()
+	}
+
+	config := b.FileSystem.Config
+	layout := "вкладки"
+	if config.DesktopMode {
+		layout = "рабочий стол с окнами"
+	}
+	sb.WriteString("<table>")
+	rows := [][2]string{
+		{"Пользователь", config.GetUsername()},
+		{"Обои", WallpaperTitle(config.GetWallpaper())},
+		{"Режим обоев", config.GetWallpaperMode()},
+		{"Интерфейс", layout},
+		{"Текущая директория", b.FileSystem.VirtualPath(config.CurrentDir)},
+		{"Данные пользователя", strings.TrimSuffix(config.UserDataPath(""), "/")},
+	}
+	for _, row := range rows {
+		sb.WriteString("<tr><td>" + html.EscapeString(row[0]) + "</td><td>" + html.EscapeString(row[1]) + "</td></tr>")
+	}
+	sb.WriteString("</table>")
+
+	if len(config.DefaultApps) > 0 {
+		keys := make([]string, 0, len(config.DefaultApps))
+		for key := range config.DefaultApps {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		sb.WriteString("<h2>Приложения по умолчанию</h2><ul>")
+		for _, key := range keys {
+			sb.WriteString("<li>" + html.EscapeString(key+": "+config.DefaultApps[key]) + "</li>")
+		}
+		sb.WriteString("</ul>")
+	}
+
+	sb.WriteString("<h2>Папки</h2><ul>")
+	for _, dir := range []string{"/", "/Documents", "/Downloads", "/Pictures", "/Music", "/Videos"} {
+		sb.WriteString(pageLink(fileURL(dir), dir))
+	}
+	sb.WriteString("</ul><p>Изменить настройки можно на вкладке \"Настройки\".</p></body></html>")
+	return sb.String()
+}
+
+// helpPage формирует страницу mixail://help из справочника команд консоли
+func helpPage() string {
+	var sb strings.Builder
+	sb.WriteString("<html><head><title>Справка MixailOS</title></head><body><h1>Справка MixailOS</h1>")
+	sb.WriteString("<p>Команды вводятся на вкладке \"Консоль\" или в терминальном режиме (флаг -headless).</p>")
+	sb.WriteString("<h2>Команды консоли</h2><ul>")
+	for _, cmd := range Commands {
+		sb.WriteString("<li><b>" + html.EscapeString(cmd.Usage) + "</b> - " + html.EscapeString(cmd.Description))
+		if len(cmd.Details) > 0 {
+			sb.WriteString("<ul>")
+			for _, detail := range cmd.Details {
+				sb.WriteString("<li>" + html.EscapeString(detail) + "</li>")
+			}
+			sb.WriteString("</ul>")
+		}
+		sb.WriteString("</li>")
+	}
+	sb.WriteString("</ul><h2>Адреса браузера</h2><ul>")
+	sb.WriteString("<li>http:// и https:// - веб-страницы</li>")
+	sb.WriteString("<li>file:///путь - файлы и папки MixailOS, например file:///Documents/welcome.txt</li>")
+	for _, page := range InternalPages {
+		sb.WriteString(pageLink("mixail://"+page, "mixail://"+page))
+	}
+	sb.WriteString("</ul></body></html>")
+	return sb.String()
+}
+
+// htmlPage отображает сформированный HTML-документ как страницу по адресу u
+func (b *Browser) htmlPage(u *url.URL, document string, status int) (*Page, error) {
+	page := &Page{URL: u.String(), StatusCode: status, ContentType: "text/html"}
+	if err := page.render(strings.NewReader(document), u); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// pageLink возвращает элемент списка со ссылкой
+func pageLink(href, text string) string {
+	return "<li><a href=\"" + html.EscapeString(href) + "\">" + html.EscapeString(text) + "</a></li>"
+}
+
+// fileURL возвращает адрес file:// для пути внутри MixailOS
+func fileURL(name string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(name)}
+	return u.String()
+}
@@ -0,0 +1,580 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Terminal описывает терминал, к которому подключена консоль в текстовом режиме
+type Terminal interface {
+	io.Reader
+	io.Writer
+	// Size возвращает ширину и высоту терминала в символах
+	Size() (width, height int, err error)
+	// MakeRaw переводит терминал в "сырой" режим и возвращает функцию восстановления
+	MakeRaw() (restore func(), err error)
+}
+
+// BufferedTerminal - терминал, который сам буферизует ввод. Редактор читает
+// из его буфера, чтобы не потерять байты, уже прочитанные из терминала.
+type BufferedTerminal interface {
+	Terminal
+	// InputReader возвращает буферизованный ввод терминала
+	InputReader() *bufio.Reader
+}
+
+// Коды клавиш, которые редактор получает после разбора управляющих последовательностей
+const (
+	keyCtrlC     = 3
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyEnter     = 13
+	keyCtrlO     = 15
+	keyCtrlS     = 19
+	keyCtrlW     = 23
+	keyCtrlX     = 24
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// Специальные клавиши, не имеющие собственного кода символа. Их коды лежат
+// за пределами Unicode, чтобы не совпадать с буквами, например с кириллицей.
+const (
+	keyArrowUp = unicode.MaxRune + 1 + iota
+	keyArrowDown
+	keyArrowLeft
+	keyArrowRight
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyDeleteForward
+	// keyNone - нераспознанная управляющая последовательность, которая пропускается
+	keyNone
+)
+
+// nanoHelp - подсказка по клавишам в нижней части экрана
+const nanoHelp = "^O/^S Записать  ^W/^F Поиск  ^X Выход"
+
+// Nano - полноэкранный текстовый редактор для терминального режима
+type Nano struct {
+	doc   *Document
+	lines [][]rune
+
+	// Позиция курсора в тексте и смещение видимой области
+	cy, cx         int
+	rowOff, colOff int
+	width, height  int
+
+	modified  bool
+	lastQuery string
+	message   string
+	// eol - перевод строки файла ("\n" или "\r\n"), сохраняемый при записи
+	eol string
+
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewNano создает редактор, работающий через файловую систему MixailOS
+func NewNano(fs *FileSystem) *Nano {
+	return &Nano{
+		doc:   NewDocument(fs),
+		lines: [][]rune{{}},
+		eol:   "\n",
+	}
+}
+
+// Open загружает файл в редактор. Несуществующий файл открывается как новый.
+func (n *Nano) Open(name string) error {
+	if err := n.doc.Open(name); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if err := n.doc.Reset(name); err != nil {
+			return err
+		}
+		n.message = "Новый файл"
+	}
+
+	// Файлы с переводами строк Windows записываются обратно с ними же
+	text := n.doc.Text()
+	n.eol = "\n"
+	if i := strings.Index(text, "\n"); i > 0 && text[i-1] == '\r' {
+		n.eol = "\r\n"
+	}
+
+	n.lines = nil
+	for _, line := range strings.Split(text, "\n") {
+		if n.eol == "\r\n" {
+			line = strings.TrimSuffix(line, "\r")
+		}
+		n.lines = append(n.lines, []rune(line))
+	}
+	n.cy, n.cx = 0, 0
+	n.modified = false
+	return nil
+}
+
+// Run запускает цикл редактора до выхода пользователя (Ctrl+X).
+// Терминал должен быть переведен в "сырой" режим.
+func (n *Nano) Run(term Terminal) error {
+	if buffered, ok := term.(BufferedTerminal); ok {
+		n.in = buffered.InputReader()
+	} else {
+		n.in = bufio.NewReader(term)
+	}
+	n.out = term
+
+	// Очистка экрана при выходе
+	defer io.WriteString(term, "\x1b[2J\x1b[H")
+
+	for {
+		n.width, n.height = 80, 24
+		if w, h, err := term.Size(); err == nil && w > 0 && h > 3 {
+			n.width, n.height = w, h
+		}
+		n.render()
+
+		key, err := n.readKey()
+		if err != nil {
+			return err
+		}
+
+		if key == keyCtrlX {
+			if n.quit() {
+				return nil
+			}
+			continue
+		}
+		n.handleKey(key)
+	}
+}
+
+// readKey читает одну клавишу, разбирая управляющие последовательности ESC [ и ESC O
+func (n *Nano) readKey() (int, error) {
+	r, _, err := n.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != keyEscape || n.in.Buffered() == 0 {
+		return int(r), nil
+	}
+
+	// Последовательности вида ESC O H, ESC [ A, ESC [ 3 ~ и ESC [ 1 ; 5 C.
+	// Параметры читаются целиком, чтобы их цифры не попали в текст.
+	prefix, err := n.in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	var params []byte
+	var final byte
+	switch prefix {
+	case 'O':
+		if final, err = n.in.ReadByte(); err != nil {
+			return 0, err
+		}
+	case '[':
+		for {
+			b, err := n.in.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			if b >= 0x40 && b <= 0x7e {
+				final = b
+				break
+			}
+			params = append(params, b)
+		}
+	default:
+		// Alt+клавиша: символ после ESC не вставляется
+		return keyNone, nil
+	}
+
+	if final == '~' {
+		// Первый параметр - номер клавиши, после ";" идут модификаторы
+		switch strings.SplitN(string(params), ";", 2)[0] {
+		case "1", "7":
+			return keyHome, nil
+		case "3":
+			return keyDeleteForward, nil
+		case "4", "8":
+			return keyEnd, nil
+		case "5":
+			return keyPageUp, nil
+		case "6":
+			return keyPageDown, nil
+		}
+		return keyNone, nil
+	}
+
+	switch final {
+	case 'A':
+		return keyArrowUp, nil
+	case 'B':
+		return keyArrowDown, nil
+	case 'C':
+		return keyArrowRight, nil
+	case 'D':
+		return keyArrowLeft, nil
+	case 'H':
+		return keyHome, nil
+	case 'F':
+		return keyEnd, nil
+	}
+	return keyNone, nil
+}
+
+// handleKey обрабатывает клавишу редактирования или перемещения
+func (n *Nano) handleKey(key int) {
+	n.message = ""
+	textRows := n.height - 3
+
+	switch key {
+	case keyCtrlO, keyCtrlS:
+		n.save()
+	case keyCtrlW, keyCtrlF:
+		n.search()
+	case keyArrowUp:
+		if n.cy > 0 {
+			n.cy--
+		}
+	case keyArrowDown:
+		if n.cy < len(n.lines)-1 {
+			n.cy++
+		}
+	case keyArrowLeft:
+		if n.cx > 0 {
+			n.cx--
+		} else if n.cy > 0 {
+			n.cy--
+			n.cx = len(n.lines[n.cy])
+		}
+	case keyArrowRight:
+		if n.cx < len(n.lines[n.cy]) {
+			n.cx++
+		} else if n.cy < len(n.lines)-1 {
+			n.cy++
+			n.cx = 0
+		}
+	case keyHome:
+		n.cx = 0
+	case keyEnd:
+		n.cx = len(n.lines[n.cy])
+	case keyPageUp:
+		n.cy -= textRows
+		if n.cy < 0 {
+			n.cy = 0
+		}
+	case keyPageDown:
+		n.cy += textRows
+		if n.cy > len(n.lines)-1 {
+			n.cy = len(n.lines) - 1
+		}
+	case keyEnter, '\n':
+		n.insertNewline()
+	case keyBackspace, keyDelete:
+		n.deleteBackward()
+	case keyDeleteForward:
+		n.deleteForward()
+	case keyTab:
+		n.insertRune('\t')
+	default:
+		if key <= unicode.MaxRune && unicode.IsPrint(rune(key)) {
+			n.insertRune(rune(key))
+		}
+	}
+
+	// Курсор не может выходить за конец строки
+	if n.cx > len(n.lines[n.cy]) {
+		n.cx = len(n.lines[n.cy])
+	}
+}
+
+// insertRune вставляет символ в позицию курсора
+func (n *Nano) insertRune(r rune) {
+	line := n.lines[n.cy]
+	line = append(line[:n.cx], append([]rune{r}, line[n.cx:]...)...)
+	n.lines[n.cy] = line
+	n.cx++
+	n.modified = true
+}
+
+// insertNewline разбивает строку в позиции курсора
+func (n *Nano) insertNewline() {
+	line := n.lines[n.cy]
+	head := append([]rune{}, line[:n.cx]...)
+	tail := append([]rune{}, line[n.cx:]...)
+
+	n.lines[n.cy] = head
+	n.lines = append(n.lines[:n.cy+1], append([][]rune{tail}, n.lines[n.cy+1:]...)...)
+	n.cy++
+	n.cx = 0
+	n.modified = true
+}
+
+// deleteBackward удаляет символ перед курсором, объединяя строки при необходимости
+func (n *Nano) deleteBackward() {
+	if n.cx > 0 {
+		line := n.lines[n.cy]
+		n.lines[n.cy] = append(line[:n.cx-1], line[n.cx:]...)
+		n.cx--
+		n.modified = true
+		return
+	}
+	if n.cy == 0 {
+		return
+	}
+
+	prev := n.lines[n.cy-1]
+	n.cx = len(prev)
+	n.lines[n.cy-1] = append(prev, n.lines[n.cy]...)
+	n.lines = append(n.lines[:n.cy], n.lines[n.cy+1:]...)
+	n.cy--
+	n.modified = true
+}
+
+// deleteForward удаляет символ под курсором
+func (n *Nano) deleteForward() {
+	if n.cx < len(n.lines[n.cy]) {
+		n.cx++
+		n.deleteBackward()
+	} else if n.cy < len(n.lines)-1 {
+		n.cy++
+		n.cx = 0
+		n.deleteBackward()
+	}
+}
+
+// save записывает текст в файл, запрашивая имя для нового документа
+func (n *Nano) save() bool {
+	name := n.doc.Path
+	if name == "" {
+		var ok bool
+		name, ok = n.prompt("Имя файла для записи: ")
+		if !ok || name == "" {
+			n.message = "Запись отменена"
+			return false
+		}
+	}
+
+	text := make([]string, len(n.lines))
+	for i, line := range n.lines {
+		text[i] = string(line)
+	}
+	n.doc.SetText(strings.Join(text, n.eol))
+
+	if err := n.doc.SaveAs(name); err != nil {
+		n.message = fmt.Sprintf("Ошибка при записи файла: %v", err)
+		return false
+	}
+	n.modified = false
+	n.message = fmt.Sprintf("Записано строк: %d", len(n.lines))
+	return true
+}
+
+// search ищет строку начиная с позиции после курсора
+func (n *Nano) search() {
+	query, ok := n.prompt(fmt.Sprintf("Поиск [%s]: ", n.lastQuery))
+	if !ok {
+		n.message = "Поиск отменен"
+		return
+	}
+	if query == "" {
+		query = n.lastQuery
+	}
+	if query == "" {
+		return
+	}
+	n.lastQuery = query
+
+	needle := []rune(query)
+	for i := 0; i <= len(n.lines); i++ {
+		row := (n.cy + i) % len(n.lines)
+		from := 0
+		if i == 0 {
+			from = n.cx + 1
+		}
+		if col := indexRunes(n.lines[row], needle, from); col >= 0 {
+			n.cy, n.cx = row, col
+			return
+		}
+	}
+	n.message = fmt.Sprintf("\"%s\" не найдено", query)
+}
+
+// quit завершает работу, предлагая сохранить изменения
+func (n *Nano) quit() bool {
+	if !n.modified {
+		return true
+	}
+
+	for {
+		answer, ok := n.prompt("Сохранить изменения? (Y - да, N - нет, ^C - отмена): ")
+		if !ok {
+			n.message = ""
+			return false
+		}
+		switch strings.ToLower(answer) {
+		case "y", "д", "yes", "да":
+			return n.save()
+		case "n", "н", "no", "нет":
+			return true
+		}
+	}
+}
+
+// prompt запрашивает строку в строке состояния. Ctrl+C или Esc отменяют ввод.
+func (n *Nano) prompt(label string) (string, bool) {
+	var input []rune
+	for {
+		n.message = label + string(input)
+		n.render()
+
+		key, err := n.readKey()
+		if err != nil {
+			n.message = ""
+			return "", false
+		}
+		switch key {
+		case keyCtrlC, keyEscape:
+			n.message = ""
+			return "", false
+		case keyEnter, '\n':
+			n.message = ""
+			return string(input), true
+		case keyBackspace, keyDelete:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		default:
+			if key <= unicode.MaxRune && unicode.IsPrint(rune(key)) {
+				input = append(input, rune(key))
+			}
+		}
+	}
+}
+
+// scroll сдвигает видимую область так, чтобы курсор оставался на экране
+func (n *Nano) scroll() {
+	textRows := n.height - 3
+	if n.cy < n.rowOff {
+		n.rowOff = n.cy
+	}
+	if n.cy >= n.rowOff+textRows {
+		n.rowOff = n.cy - textRows + 1
+	}
+
+	cx := n.displayColumn(n.lines[n.cy], n.cx)
+	if cx < n.colOff {
+		n.colOff = cx
+	}
+	if cx >= n.colOff+n.width {
+		n.colOff = cx - n.width + 1
+	}
+}
+
+// displayColumn переводит позицию в строке в экранный столбец с учетом табуляции
+func (n *Nano) displayColumn(line []rune, pos int) int {
+	col := 0
+	for _, r := range line[:pos] {
+		if r == '\t' {
+			col += 4 - col%4
+		} else {
+			col++
+		}
+	}
+	return col
+}
+
+// render перерисовывает экран: заголовок, текст, строку состояния и подсказку
+func (n *Nano) render() {
+	n.scroll()
+
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[?25l\x1b[H")
+
+	// Заголовок
+	title := "  MixailOS nano  " + n.doc.Name()
+	if n.modified {
+		title += " (изменен)"
+	}
+	buf.WriteString("\x1b[7m" + fitLine(title, n.width) + "\x1b[0m\r\n")
+
+	// Текст
+	textRows := n.height - 3
+	for y := 0; y < textRows; y++ {
+		row := y + n.rowOff
+		if row < len(n.lines) {
+			runes := []rune(expandTabs(n.lines[row]))
+			if n.colOff < len(runes) {
+				runes = runes[n.colOff:]
+			} else {
+				runes = nil
+			}
+			if len(runes) > n.width {
+				runes = runes[:n.width]
+			}
+			buf.WriteString(string(runes))
+		}
+		buf.WriteString("\x1b[K\r\n")
+	}
+
+	// Строка состояния и подсказка
+	status := n.message
+	if status == "" {
+		status = fmt.Sprintf("строка %d/%d, столбец %d", n.cy+1, len(n.lines), n.cx+1)
+	}
+	buf.WriteString("\x1b[7m" + fitLine(status, n.width) + "\x1b[0m\r\n")
+	buf.WriteString(fitLine(nanoHelp, n.width) + "\x1b[K")
+
+	// Позиция курсора
+	cursorRow := n.cy - n.rowOff + 2
+	cursorCol := n.displayColumn(n.lines[n.cy], n.cx) - n.colOff + 1
+	buf.WriteString(fmt.Sprintf("\x1b[%d;%dH\x1b[?25h", cursorRow, cursorCol))
+
+	n.out.Write(buf.Bytes())
+}
+
+// expandTabs заменяет табуляции пробелами до ближайшей позиции, кратной 4
+func expandTabs(line []rune) string {
+	var sb strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			for spaces := 4 - col%4; spaces > 0; spaces-- {
+				sb.WriteRune(' ')
+				col++
+			}
+			continue
+		}
+		sb.WriteRune(r)
+		col++
+	}
+	return sb.String()
+}
+
+// fitLine обрезает или дополняет строку пробелами до заданной ширины
+func fitLine(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// indexRunes ищет needle в line начиная с позиции from
+func indexRunes(line, needle []rune, from int) int {
+	for i := from; i+len(needle) <= len(line); i++ {
+		if string(line[i:i+len(needle)]) == string(needle) {
+			return i
+		}
+	}
+	return -1
+}
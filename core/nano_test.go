@@ -0,0 +1,154 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testTerminal - терминал, ввод которого задан заранее, а вывод собирается в буфер
+type testTerminal struct {
+	*strings.Reader
+	bytes.Buffer
+}
+
+func (t *testTerminal) Read(p []byte) (int, error)  { return t.Reader.Read(p) }
+func (t *testTerminal) Write(p []byte) (int, error) { return t.Buffer.Write(p) }
+func (t *testTerminal) Size() (int, int, error)     { return 80, 24, nil }
+func (t *testTerminal) MakeRaw() (func(), error)    { return func() {}, nil }
+
+// newTestNano создает редактор, читающий клавиши из строки input
+func newTestNano(t *testing.T, input string) (*Nano, string) {
+	fs, root := newTestFileSystem(t)
+	n := NewNano(fs)
+	n.in = bufio.NewReader(strings.NewReader(input))
+	n.out = ioutil.Discard
+	n.width, n.height = 80, 24
+	return n, root
+}
+
+func TestNanoReadKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"\x1b[A\x1b[B\x1b[C\x1b[D", []int{keyArrowUp, keyArrowDown, keyArrowRight, keyArrowLeft}},
+		{"\x1b[H\x1b[F\x1bOH\x1bOF", []int{keyHome, keyEnd, keyHome, keyEnd}},
+		{"\x1b[1~\x1b[4~\x1b[7~\x1b[8~", []int{keyHome, keyEnd, keyHome, keyEnd}},
+		{"\x1b[3~\x1b[5~\x1b[6~", []int{keyDeleteForward, keyPageUp, keyPageDown}},
+		// Параметры модификаторов не попадают в текст
+		{"\x1b[1;5C\x1b[3;2~x", []int{keyArrowRight, keyDeleteForward, 'x'}},
+		{"\x1b[15~\x1bx", []int{keyNone, keyNone}},
+		{"ж\r\x7f", []int{'ж', keyEnter, keyDelete}},
+		// Одиночный Esc без продолжения
+		{"\x1b", []int{keyEscape}},
+	}
+	for _, tt := range tests {
+		n, _ := newTestNano(t, tt.in)
+		var got []int
+		for {
+			key, err := n.readKey()
+			if err != nil {
+				break
+			}
+			got = append(got, key)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("readKey(%q) = %v, ожидалось %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("readKey(%q) = %v, ожидалось %v", tt.in, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// nanoText возвращает текст редактора со строками, разделенными "\n"
+func nanoText(n *Nano) string {
+	lines := make([]string, len(n.lines))
+	for i, line := range n.lines {
+		lines[i] = string(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestNanoSplitJoinLines(t *testing.T) {
+	n, _ := newTestNano(t, "")
+	for _, r := range "приветмир" {
+		n.handleKey(int(r))
+	}
+	for i := 0; i < 3; i++ {
+		n.handleKey(keyArrowLeft)
+	}
+
+	n.handleKey(keyEnter)
+	if got := nanoText(n); got != "привет\nмир" || n.cy != 1 || n.cx != 0 {
+		t.Fatalf("после Enter: %q, курсор %d:%d", got, n.cy, n.cx)
+	}
+
+	n.handleKey(keyBackspace)
+	if got := nanoText(n); got != "приветмир" || n.cy != 0 || n.cx != 6 {
+		t.Fatalf("после Backspace: %q, курсор %d:%d", got, n.cy, n.cx)
+	}
+
+	// Delete в конце строки присоединяет следующую строку
+	n.handleKey(keyEnter)
+	n.handleKey(keyArrowLeft)
+	n.handleKey(keyDeleteForward)
+	if got := nanoText(n); got != "приветмир" || n.cy != 0 || n.cx != 6 {
+		t.Fatalf("после Delete: %q, курсор %d:%d", got, n.cy, n.cx)
+	}
+
+	// Backspace в начале текста ничего не делает
+	n.handleKey(keyHome)
+	n.handleKey(keyBackspace)
+	if got := nanoText(n); got != "приветмир" || n.cx != 0 {
+		t.Errorf("Backspace в начале: %q, курсор %d", got, n.cx)
+	}
+}
+
+func TestNanoKeepsCRLF(t *testing.T) {
+	n, root := newTestNano(t, "")
+	writeTestFile(t, root, "win.txt", "один\r\nдва\r\n", 0644)
+
+	if err := n.Open("/win.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := nanoText(n); got != "один\nдва\n" {
+		t.Fatalf("строки = %q, символы \\r не убраны", got)
+	}
+	n.handleKey(keyEnd)
+	n.handleKey('!')
+	if !n.save() {
+		t.Fatal(n.message)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(root, "win.txt"))
+	if err != nil || string(data) != "один!\r\nдва\r\n" {
+		t.Errorf("записано %q, %v; ожидалось %q", data, err, "один!\r\nдва\r\n")
+	}
+}
+
+func TestNanoRun(t *testing.T) {
+	fs, root := newTestFileSystem(t)
+	n := NewNano(fs)
+	if err := n.Open("/new.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Набор текста, Ctrl+X, ответ "y" на вопрос о сохранении
+	term := &testTerminal{Reader: strings.NewReader("ab\rc\x18y\r")}
+	if err := n.Run(term); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(root, "new.txt"))
+	if err != nil || string(data) != "ab\nc" {
+		t.Errorf("записано %q, %v; ожидалось %q", data, err, "ab\nc")
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// maxNotifications - сколько уведомлений хранится в истории
+const maxNotifications = 100
+
+// Severity - важность уведомления
+type Severity int
+
+const (
+	// SeverityInfo - обычное сообщение
+	SeverityInfo Severity = iota
+	// SeveritySuccess - действие успешно завершено
+	SeveritySuccess
+	// SeverityWarning - предупреждение
+	SeverityWarning
+	// SeverityError - ошибка
+	SeverityError
+)
+
+// severityNames - названия уровней важности для консоли
+var severityNames = map[Severity]string{
+	SeverityInfo:    "info",
+	SeveritySuccess: "success",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+// String возвращает название уровня важности: info, success, warning или error
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity разбирает название уровня важности
+func ParseSeverity(name string) (Severity, bool) {
+	for s, n := range severityNames {
+		if n == name {
+			return s, true
+		}
+	}
+	return SeverityInfo, false
+}
+
+// NotificationAction - кнопка уведомления, например «Открыть»
+type NotificationAction struct {
+	Label string
+	Run   func()
+}
+
+// Notification - уведомление приложения или команды
+type Notification struct {
+	ID       int
+	Title    string
+	Body     string
+	Severity Severity
+	Actions  []NotificationAction
+	Time     time.Time
+	Read     bool
+}
+
+// NotificationCenter хранит историю уведомлений и передает новые уведомления
+// подписчикам (например, интерфейсу для показа всплывающих сообщений).
+// Режим «Не беспокоить» хранится в настройках пользователя.
+type NotificationCenter struct {
+	Config *Config
+
+	mu          sync.Mutex
+	history     []Notification
+	nextID      int
+	subscribers []func(n Notification, popup bool)
+}
+
+// NewNotificationCenter создает центр уведомлений
+func NewNotificationCenter(config *Config) *NotificationCenter {
+	return &NotificationCenter{Config: config, nextID: 1}
+}
+
+// Subscribe добавляет получателя новых уведомлений. popup равен false, если
+// включен режим «Не беспокоить» и уведомление нужно только сохранить в истории.
+func (nc *NotificationCenter) Subscribe(fn func(n Notification, popup bool)) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.subscribers = append(nc.subscribers, fn)
+}
+
+// Post публикует уведомление и возвращает его номер
+func (nc *NotificationCenter) Post(n Notification) int {
+	nc.mu.Lock()
+	n.ID = nc.nextID
+	nc.nextID++
+	n.Time = time.Now()
+	nc.history = append(nc.history, n)
+	if len(nc.history) > maxNotifications {
+		nc.history = nc.history[len(nc.history)-maxNotifications:]
+	}
+	subscribers := make([]func(Notification, bool), len(nc.subscribers))
+	copy(subscribers, nc.subscribers)
+	nc.mu.Unlock()
+
+	popup := !nc.DoNotDisturb()
+	for _, fn := range subscribers {
+		fn(n, popup)
+	}
+	return n.ID
+}
+
+// Notify публикует уведомление без действий
+func (nc *NotificationCenter) Notify(title, body string, severity Severity) int {
+	return nc.Post(Notification{Title: title, Body: body, Severity: severity})
+}
+
+// History возвращает уведомления, начиная с новых
+func (nc *NotificationCenter) History() []Notification {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	list := make([]Notification, len(nc.history))
+	for i, n := range nc.history {
+		list[len(nc.history)-1-i] = n
+	}
+	return list
+}
+
+// Unread возвращает количество непрочитанных уведомлений
+func (nc *NotificationCenter) Unread() int {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	count := 0
+	for _, n := range nc.history {
+		if !n.Read {
+			count++
+		}
+	}
+	return count
+}
+
+// MarkAllRead отмечает все уведомления прочитанными
+func (nc *NotificationCenter) MarkAllRead() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	for i := range nc.history {
+		nc.history[i].Read = true
+	}
+}
+
+// Remove удаляет уведомление из истории
+func (nc *NotificationCenter) Remove(id int) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	for i, n := range nc.history {
+		if n.ID == id {
+			nc.history = append(nc.history[:i], nc.history[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear очищает историю уведомлений
+func (nc *NotificationCenter) Clear() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.history = nil
+}
+
+// DoNotDisturb проверяет, включен ли режим «Не беспокоить»
+func (nc *NotificationCenter) DoNotDisturb() bool {
+	if nc.Config == nil {
+		return false
+	}
+	nc.Config.mu.RLock()
+	defer nc.Config.mu.RUnlock()
+	return nc.Config.DoNotDisturb
+}
+
+// SetDoNotDisturb включает или выключает режим «Не беспокоить»:
+// уведомления сохраняются в истории, но не всплывают
+func (nc *NotificationCenter) SetDoNotDisturb(enabled bool) error {
+	return nc.Config.update(func() error {
+		nc.Config.DoNotDisturb = enabled
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// ownerNames - имена пользователей основной системы по их UID
+var (
+	ownerMu    sync.Mutex
+	ownerNames = map[uint32]string{}
+)
+
+// fileOwner возвращает имя владельца файла в основной системе
+func fileOwner(info os.FileInfo) string {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+
+	ownerMu.Lock()
+	defer ownerMu.Unlock()
+	if name, ok := ownerNames[stat.Uid]; ok {
+		return name
+	}
+	name := strconv.FormatUint(uint64(stat.Uid), 10)
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	ownerNames[stat.Uid] = name
+	return name
+}
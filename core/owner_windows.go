@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package core
+
+import "os"
+
+// fileOwner возвращает имя владельца файла. В Windows владелец задается
+// дескриптором безопасности, который здесь не читается.
+func fileOwner(info os.FileInfo) string {
+	return ""
+}
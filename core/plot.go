@@ -0,0 +1,394 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// PicturesDir - папка для изображений, в которую сохраняются графики
+const PicturesDir = "/Pictures"
+
+const (
+	// plotGridPixels - желаемое расстояние между линиями сетки в пикселях
+	plotGridPixels = 80
+	// plotMinSpan - наименьший размер видимой области (ограничение приближения)
+	plotMinSpan = 1e-9
+	// plotMaxSpan - наибольший размер видимой области (ограничение отдаления)
+	plotMaxSpan = 1e9
+	// plotMinRelativeSpan - наименьший размер области относительно ее координат:
+	// при меньшем размере соседние пиксели неотличимы в float64
+	plotMinRelativeSpan = 1e-9
+	// plotMaxGridLines - наибольшее число линий сетки по одной оси
+	plotMaxGridLines = 1000
+)
+
+// PlotColors - цвета графиков по порядку добавления
+var PlotColors = []color.RGBA{
+	{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+	{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+	{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+	{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+	{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+	{R: 0x8c, G: 0x56, B: 0x4b, A: 0xff},
+	{R: 0xe3, G: 0x77, B: 0xc2, A: 0xff},
+	{R: 0x17, G: 0xbe, B: 0xcf, A: 0xff},
+}
+
+var (
+	plotBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	plotGrid       = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+	plotAxis       = color.RGBA{R: 0x30, G: 0x30, B: 0x30, A: 0xff}
+	plotLabel      = color.RGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff}
+)
+
+// PlotFunction - функция y = f(x) на графике
+type PlotFunction struct {
+	Source string
+	Color  color.RGBA
+	expr   *Expression
+}
+
+// Plot - график функций: список функций и видимая область координат
+type Plot struct {
+	Functions              []*PlotFunction
+	XMin, XMax, YMin, YMax float64
+	// Degrees - тригонометрические функции принимают аргумент в градусах
+	Degrees bool
+	// Vars - пользовательские переменные калькулятора, доступные в выражениях
+	Vars map[string]float64
+}
+
+// NewPlot создает пустой график с областью от -10 до 10 по обеим осям
+func NewPlot() *Plot {
+	p := &Plot{Vars: map[string]float64{}}
+	p.Reset()
+	return p
+}
+
+// Reset возвращает видимую область в исходное положение
+func (p *Plot) Reset() {
+	p.XMin, p.XMax = -10, 10
+	p.YMin, p.YMax = -10, 10
+}
+
+// AddFunction разбирает выражение вида "y = f(x)" или "f(x)" и добавляет его на график
+func (p *Plot) AddFunction(source string) (*PlotFunction, error) {
+	source = strings.TrimSpace(source)
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Assign != "" && expr.Assign != "y" {
+		return nil, fmt.Errorf("ожидается функция вида y = f(x)")
+	}
+	if err := p.checkNames(expr.root); err != nil {
+		return nil, err
+	}
+
+	f := &PlotFunction{
+		Source: source,
+		Color:  PlotColors[len(p.Functions)%len(PlotColors)],
+		expr:   expr,
+	}
+	p.Functions = append(p.Functions, f)
+	return f, nil
+}
+
+// RemoveFunction удаляет функцию с номером i
+func (p *Plot) RemoveFunction(i int) {
+	if i >= 0 && i < len(p.Functions) {
+		p.Functions = append(p.Functions[:i], p.Functions[i+1:]...)
+	}
+}
+
+// checkNames проверяет, что выражение использует только x, константы и переменные калькулятора
+func (p *Plot) checkNames(n calcNode) error {
+	switch n := n.(type) {
+	case *varNode:
+		if _, ok := calcConstants[n.name]; ok || n.name == "x" {
+			return nil
+		}
+		if _, ok := p.Vars[n.name]; ok {
+			return nil
+		}
+		return calcErrorf(n.pos, "неизвестное имя %q: переменная графика - x", n.name)
+	case *unaryNode:
+		return p.checkNames(n.x)
+	case *percentNode:
+		return p.checkNames(n.x)
+	case *factorialNode:
+		return p.checkNames(n.x)
+	case *binaryNode:
+		if err := p.checkNames(n.x); err != nil {
+			return err
+		}
+		return p.checkNames(n.y)
+	case *callNode:
+		for _, arg := range n.args {
+			if err := p.checkNames(arg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Eval вычисляет значение функции в точке x. ok = false, если функция в точке не определена.
+func (p *Plot) Eval(f *PlotFunction, x float64) (float64, bool) {
+	vars := make(map[string]float64, len(p.Vars)+1)
+	for name, v := range p.Vars {
+		vars[name] = v
+	}
+	vars["x"] = x
+	y, err := f.expr.EvalEnv(&Env{Vars: vars, Degrees: p.Degrees})
+	return y, err == nil
+}
+
+// Zoom масштабирует область в factor раз (меньше 1 - приближение) относительно
+// точки, заданной долями ширины и высоты (fx, fy от 0 до 1, отсчет сверху слева)
+func (p *Plot) Zoom(factor, fx, fy float64) {
+	spanX := (p.XMax - p.XMin) * factor
+	spanY := (p.YMax - p.YMin) * factor
+	cx := p.XMin + (p.XMax-p.XMin)*fx
+	cy := p.YMax - (p.YMax-p.YMin)*fy
+	if spanX < minPlotSpan(cx) || spanY < minPlotSpan(cy) || spanX > plotMaxSpan || spanY > plotMaxSpan {
+		return
+	}
+	p.XMin, p.XMax = cx-spanX*fx, cx+spanX*(1-fx)
+	p.YMin, p.YMax = cy-spanY*(1-fy), cy+spanY*fy
+}
+
+// minPlotSpan возвращает наименьший размер области около координаты center
+func minPlotSpan(center float64) float64 {
+	return math.Max(plotMinSpan, math.Abs(center)*plotMinRelativeSpan)
+}
+
+// Pan сдвигает область на доли ее ширины и высоты. Положительный dx сдвигает
+// изображение вправо, положительный dy - вниз (как при перетаскивании мышью).
+func (p *Plot) Pan(dx, dy float64) {
+	shiftX := (p.XMax - p.XMin) * dx
+	shiftY := (p.YMax - p.YMin) * dy
+	p.XMin, p.XMax = p.XMin-shiftX, p.XMax-shiftX
+	p.YMin, p.YMax = p.YMin+shiftY, p.YMax+shiftY
+}
+
+// gridStep подбирает шаг сетки вида 1, 2 или 5 * 10^n для диапазона span
+// и count линий
+func gridStep(span float64, count int) float64 {
+	if count < 1 {
+		count = 1
+	}
+	raw := span / float64(count)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*magnitude {
+			return m * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// gridTicks возвращает значения делений с шагом step в диапазоне [min, max].
+// Деления считаются по номеру, а не прибавлением шага: если шаг меньше
+// погрешности координат, x += step не меняет x и цикл не закончился бы.
+func gridTicks(min, max, step float64) []float64 {
+	first, last := math.Ceil(min/step), math.Floor(max/step)
+	count := last - first
+	if !(count >= 0 && count <= plotMaxGridLines) {
+		return nil
+	}
+	ticks := make([]float64, 0, int(count)+1)
+	for i := 0; i <= int(count); i++ {
+		ticks = append(ticks, (first+float64(i))*step)
+	}
+	return ticks
+}
+
+// formatTick форматирует подпись деления, убирая погрешность шага
+func formatTick(v, step float64) string {
+	if math.Abs(v) < step/2 {
+		return "0"
+	}
+	return FormatNumber(math.Round(v/step) * step)
+}
+
+// Render рисует график размером width x height пикселей: сетку, оси с подписями и функции
+func (p *Plot) Render(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: plotBackground}, image.Point{}, draw.Src)
+	if width < 2 || height < 2 {
+		return img
+	}
+
+	toX := func(x float64) float64 { return (x - p.XMin) / (p.XMax - p.XMin) * float64(width) }
+	toY := func(y float64) float64 { return (p.YMax - y) / (p.YMax - p.YMin) * float64(height) }
+
+	stepX := gridStep(p.XMax-p.XMin, width/plotGridPixels)
+	stepY := gridStep(p.YMax-p.YMin, height/plotGridPixels)
+	ticksX := gridTicks(p.XMin, p.XMax, stepX)
+	ticksY := gridTicks(p.YMin, p.YMax, stepY)
+
+	// Сетка
+	for _, x := range ticksX {
+		px := int(toX(x))
+		drawLine(img, px, 0, px, height-1, plotGrid)
+	}
+	for _, y := range ticksY {
+		py := int(toY(y))
+		drawLine(img, 0, py, width-1, py, plotGrid)
+	}
+
+	// Оси; если ось вне области, подписи прижимаются к краю
+	axisX := clampInt(int(toX(0)), 0, width-1)
+	axisY := clampInt(int(toY(0)), 0, height-1)
+	if p.XMin <= 0 && p.XMax >= 0 {
+		drawLine(img, axisX, 0, axisX, height-1, plotAxis)
+	}
+	if p.YMin <= 0 && p.YMax >= 0 {
+		drawLine(img, 0, axisY, width-1, axisY, plotAxis)
+	}
+
+	// Подписи делений
+	face := basicfont.Face7x13
+	for _, x := range ticksX {
+		label := formatTick(x, stepX)
+		if label == "0" {
+			continue
+		}
+		drawText(img, face, label, int(toX(x))+3, clampInt(axisY+14, 13, height-3))
+	}
+	for _, y := range ticksY {
+		label := formatTick(y, stepY)
+		if label == "0" {
+			continue
+		}
+		drawText(img, face, label, clampInt(axisX+4, 2, width-7*len(label)-2), int(toY(y))-3)
+	}
+	if p.XMin <= 0 && p.XMax >= 0 && p.YMin <= 0 && p.YMax >= 0 {
+		drawText(img, face, "0", axisX+3, axisY+14)
+	}
+
+	// Функции: значение вычисляется в каждом столбце пикселей. Разрывы (точки,
+	// где функция не определена, и скачки больше высоты графика) не соединяются.
+	for _, f := range p.Functions {
+		prevOK := false
+		var prevX, prevY float64
+		for px := 0; px <= width; px++ {
+			x := p.XMin + (p.XMax-p.XMin)*float64(px)/float64(width)
+			y, ok := p.Eval(f, x)
+			py := toY(y)
+			if ok && prevOK && math.Abs(py-prevY) < float64(height) {
+				drawThickLine(img, int(prevX), int(prevY), px, int(py), f.Color)
+			}
+			prevOK, prevX, prevY = ok, float64(px), py
+		}
+	}
+	return img
+}
+
+// clampInt ограничивает v диапазоном [lo, hi]
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// drawLine рисует отрезок алгоритмом Брезенхэма
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	// Отрезки далеко за пределами изображения обрезаются, чтобы не перебирать лишние точки
+	b := img.Bounds()
+	limit := 4 * (b.Dx() + b.Dy())
+	if (y0 < -limit && y1 < -limit) || (y0 > limit && y1 > limit) {
+		return
+	}
+	y0, y1 = clampInt(y0, -limit, limit), clampInt(y1, -limit, limit)
+
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx - dy
+	for {
+		if image.Pt(x0, y0).In(b) {
+			img.SetRGBA(x0, y0, c)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 > -dy {
+			e -= dy
+			x0 += sx
+		}
+		if e2 < dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawThickLine рисует отрезок толщиной 2 пикселя
+func drawThickLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	drawLine(img, x0, y0, x1, y1, c)
+	drawLine(img, x0+1, y0, x1+1, y1, c)
+	drawLine(img, x0, y0+1, x1, y1+1, c)
+}
+
+// drawText выводит текст с базовой линией в точке (x, y)
+func drawText(img *image.RGBA, face font.Face, text string, x, y int) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(plotLabel),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// SavePNG сохраняет график размером width x height в папку изображений
+// и возвращает виртуальный путь к файлу
+func (p *Plot) SavePNG(fs *FileSystem, width, height int) (string, error) {
+	if err := fs.CreateDirectories(PicturesDir); err != nil {
+		return "", err
+	}
+	// Подбираем свободное имя, чтобы графики, сохраненные в одну секунду,
+	// не перезаписывали друг друга: graph-<время>.png, graph-<время>-1.png...
+	base := PicturesDir + "/graph-" + time.Now().Format("20060102-150405")
+	name := base + ".png"
+	for i := 1; fs.Exists(name); i++ {
+		name = fmt.Sprintf("%s-%d.png", base, i)
+	}
+	w, err := fs.OpenWriter(name, false)
+	if err != nil {
+		return "", err
+	}
+	if err := png.Encode(w, p.Render(width, height)); err != nil {
+		w.Close()
+		return "", err
+	}
+	return name, w.Close()
+}
@@ -0,0 +1,56 @@
+package core
+
+import "testing"
+
+func TestPlotRenderTinyStep(t *testing.T) {
+	p := NewPlot()
+	if _, err := p.AddFunction("x"); err != nil {
+		t.Fatal(err)
+	}
+	// Шаг сетки меньше расстояния между соседними float64: x += step не менял бы x
+	p.XMin, p.XMax = 1e12, 1e12+1e-6
+	p.YMin, p.YMax = -1e12-1e-6, -1e12
+	if img := p.Render(400, 300); img.Bounds().Dx() != 400 {
+		t.Errorf("размер изображения %v", img.Bounds())
+	}
+}
+
+func TestPlotZoomLimit(t *testing.T) {
+	p := NewPlot()
+	p.XMin, p.XMax = 1e8, 1e8+1
+	p.Zoom(1e-3, 0.5, 0.5)
+	if p.XMax-p.XMin != 1 {
+		t.Errorf("область [%v, %v] меньше допустимой для координат 1e8", p.XMin, p.XMax)
+	}
+	p.XMin, p.XMax = -1, 1
+	p.Zoom(0.5, 0.5, 0.5)
+	if p.XMax-p.XMin != 1 {
+		t.Errorf("приближение около нуля: область [%v, %v]", p.XMin, p.XMax)
+	}
+}
+
+func TestPlotSavePNGUniqueName(t *testing.T) {
+	fs, _ := newTestFileSystem(t)
+	p := NewPlot()
+	if _, err := p.AddFunction("x^2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Графики, сохраненные подряд, получают разные имена
+	names := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		name, err := p.SavePNG(fs, 40, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names[name] {
+			t.Fatalf("имя %s использовано повторно", name)
+		}
+		names[name] = true
+	}
+	for name := range names {
+		if !fs.Exists(name) {
+			t.Errorf("файл %s не сохранен", name)
+		}
+	}
+}
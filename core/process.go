@@ -0,0 +1,170 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProcessKind описывает тип процесса MixailOS
+type ProcessKind string
+
+const (
+	// ProcessCommand - команда консоли
+	ProcessCommand ProcessKind = "команда"
+	// ProcessApp - открытое приложение
+	ProcessApp ProcessKind = "приложение"
+	// ProcessDownload - фоновая загрузка файла
+	ProcessDownload ProcessKind = "загрузка"
+)
+
+// ProcessState описывает состояние процесса
+type ProcessState string
+
+const (
+	// ProcessRunning - процесс выполняется
+	ProcessRunning ProcessState = "выполняется"
+	// ProcessSleeping - процесс ожидает
+	ProcessSleeping ProcessState = "ожидает"
+	// ProcessStopping - процесс завершается
+	ProcessStopping ProcessState = "завершается"
+)
+
+// Process представляет запись в таблице процессов
+type Process struct {
+	PID       int
+	Name      string
+	Kind      ProcessKind
+	Owner     string
+	StartTime time.Time
+	State     ProcessState
+
+	kill func()
+}
+
+// ProcessTable хранит список запущенных команд и приложений
+type ProcessTable struct {
+	mu      sync.Mutex
+	nextPID int
+	procs   map[int]*Process
+}
+
+// NewProcessTable создает пустую таблицу процессов
+func NewProcessTable() *ProcessTable {
+	return &ProcessTable{
+		nextPID: 1,
+		procs:   map[int]*Process{},
+	}
+}
+
+// Start регистрирует новый процесс и возвращает его PID.
+// kill вызывается при принудительном завершении; nil означает, что процесс завершить нельзя.
+func (pt *ProcessTable) Start(name string, kind ProcessKind, owner string, kill func()) int {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pid := pt.nextPID
+	pt.nextPID++
+	pt.procs[pid] = &Process{
+		PID:       pid,
+		Name:      name,
+		Kind:      kind,
+		Owner:     owner,
+		StartTime: time.Now(),
+		State:     ProcessRunning,
+		kill:      kill,
+	}
+	return pid
+}
+
+// SetState изменяет состояние процесса
+func (pt *ProcessTable) SetState(pid int, state ProcessState) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	if p, ok := pt.procs[pid]; ok {
+		p.State = state
+	}
+}
+
+// Exit удаляет завершившийся процесс из таблицы
+func (pt *ProcessTable) Exit(pid int) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	delete(pt.procs, pid)
+}
+
+// Kill принудительно завершает процесс
+func (pt *ProcessTable) Kill(pid int) error {
+	pt.mu.Lock()
+	p, ok := pt.procs[pid]
+	if !ok {
+		pt.mu.Unlock()
+		return fmt.Errorf("процесс %d не найден", pid)
+	}
+	if p.kill == nil {
+		pt.mu.Unlock()
+		return fmt.Errorf("процесс %d (%s) не может быть завершен", pid, p.Name)
+	}
+	p.State = ProcessStopping
+	kill := p.kill
+	delete(pt.procs, pid)
+	pt.mu.Unlock()
+
+	// Вызываем kill без блокировки, так как он может обращаться к таблице
+	kill()
+	return nil
+}
+
+// List возвращает снимок таблицы процессов, отсортированный по PID
+func (pt *ProcessTable) List() []Process {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	list := make([]Process, 0, len(pt.procs))
+	for _, p := range pt.procs {
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].PID < list[j].PID
+	})
+	return list
+}
+
+// ResourceStats содержит статистику ресурсов среды выполнения Go
+type ResourceStats struct {
+	Goroutines int
+	HeapAlloc  uint64
+	HeapSys    uint64
+	NumGC      uint32
+}
+
+// ReadResourceStats возвращает текущую статистику ресурсов
+func ReadResourceStats() ResourceStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return ResourceStats{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  m.HeapAlloc,
+		HeapSys:    m.HeapSys,
+		NumGC:      m.NumGC,
+	}
+}
+
+// FormatSize форматирует размер в байтах в удобочитаемый вид
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d Б", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cБ", float64(size)/float64(div), []rune("КМГТП")[exp])
+}
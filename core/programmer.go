@@ -0,0 +1,358 @@
+package core
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WordSizes - поддерживаемые размеры слова в битах
+var WordSizes = []int{8, 16, 32, 64}
+
+// IntEnv - окружение целочисленного вычисления в режиме программиста.
+// Значения хранятся как битовые образы слова; знак учитывается при делении,
+// сдвиге вправо и выводе в десятичной системе.
+type IntEnv struct {
+	Vars   map[string]uint64
+	Bits   int
+	Signed bool
+	// Base - основание чисел без префикса
+	Base int
+}
+
+// mask возвращает маску слова
+func (env *IntEnv) mask() uint64 {
+	if env.Bits >= 64 {
+		return ^uint64(0)
+	}
+	return 1<<uint(env.Bits) - 1
+}
+
+// wrap обрезает значение до размера слова (переполнение по модулю 2^Bits)
+func (env *IntEnv) wrap(v uint64) uint64 {
+	return v & env.mask()
+}
+
+// signed возвращает значение слова как знаковое число
+func (env *IntEnv) signed(v uint64) int64 {
+	shift := uint(64 - env.Bits)
+	return int64(v<<shift) >> shift
+}
+
+// negative проверяет, является ли значение отрицательным с учетом знаковости
+func (env *IntEnv) negative(v uint64) bool {
+	return env.Signed && env.signed(v) < 0
+}
+
+// EvalInt вычисляет выражение в целых числах заданного размера слова
+func (e *Expression) EvalInt(env *IntEnv) (uint64, error) {
+	return evalInt(e.root, env)
+}
+
+// parseIntLiteral разбирает целое число с префиксом основания или в системе env.Base.
+// Префикс всегда важнее системы счисления: в шестнадцатеричном режиме 0b1010 -
+// двоичное число, а шестнадцатеричное 0B1010 записывается как 0x0B1010 или B1010.
+func parseIntLiteral(n *numberNode, env *IntEnv) (uint64, error) {
+	text, base := n.text, env.Base
+	if len(text) > 2 && text[0] == '0' {
+		switch text[1] {
+		case 'x', 'X':
+			text, base = text[2:], 16
+		case 'o', 'O':
+			text, base = text[2:], 8
+		case 'b', 'B':
+			text, base = text[2:], 2
+		}
+	}
+	if strings.ContainsAny(text, ".eE") && base != 16 {
+		return 0, calcErrorf(n.pos, "в режиме программиста доступны только целые числа")
+	}
+
+	v, err := strconv.ParseUint(text, base, 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return 0, calcErrorf(n.pos, "число %s не помещается в 64 бита", n.text)
+		}
+		if env.Base == 16 && base == 2 {
+			return 0, calcErrorf(n.pos, "некорректное двоичное число %q: префикс 0b означает двоичное число и в шестнадцатеричном режиме, запишите 0x%s", n.text, n.text)
+		}
+		return 0, calcErrorf(n.pos, "некорректное число %q в системе счисления %d", n.text, base)
+	}
+	return env.wrap(v), nil
+}
+
+// evalInt вычисляет узел в целых числах
+func evalInt(n calcNode, env *IntEnv) (uint64, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return parseIntLiteral(n, env)
+
+	case *varNode:
+		if v, ok := env.Vars[n.name]; ok {
+			return env.wrap(v), nil
+		}
+		return 0, calcErrorf(n.pos, "неизвестное имя %q", n.name)
+
+	case *unaryNode:
+		x, err := evalInt(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		switch n.op {
+		case "-":
+			return env.wrap(-x), nil
+		case "~":
+			return env.wrap(^x), nil
+		}
+		return x, nil
+
+	case *factorialNode:
+		x, err := evalInt(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		if env.negative(x) {
+			return 0, calcErrorf(n.pos, "факториал определен только для неотрицательных чисел")
+		}
+		v := uint64(1)
+		for i := uint64(2); i <= x; i++ {
+			v = env.wrap(v * i)
+			if v == 0 {
+				break
+			}
+		}
+		return v, nil
+
+	case *percentNode:
+		return 0, calcErrorf(n.pos, "проценты недоступны в режиме программиста")
+
+	case *callNode:
+		return 0, calcErrorf(n.pos, "функции недоступны в режиме программиста")
+
+	case *binaryNode:
+		x, err := evalInt(n.x, env)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evalInt(n.y, env)
+		if err != nil {
+			return 0, err
+		}
+
+		switch n.op {
+		case "+":
+			return env.wrap(x + y), nil
+		case "-":
+			return env.wrap(x - y), nil
+		case "*":
+			return env.wrap(x * y), nil
+		case "/", "mod":
+			if y == 0 {
+				return 0, calcErrorf(n.pos, "деление на ноль")
+			}
+			if env.Signed {
+				a, b := env.signed(x), env.signed(y)
+				// MinInt / -1 переполняется и остается MinInt, как в процессоре
+				if b == -1 {
+					if n.op == "mod" {
+						return 0, nil
+					}
+					return env.wrap(uint64(-a)), nil
+				}
+				if n.op == "/" {
+					return env.wrap(uint64(a / b)), nil
+				}
+				return env.wrap(uint64(a % b)), nil
+			}
+			if n.op == "/" {
+				return x / y, nil
+			}
+			return x % y, nil
+		case "^":
+			if env.negative(y) {
+				return 0, calcErrorf(n.pos, "отрицательная степень недоступна в режиме программиста")
+			}
+			v := uint64(1)
+			for base, exp := x, y; exp > 0; exp >>= 1 {
+				if exp&1 == 1 {
+					v = env.wrap(v * base)
+				}
+				base = env.wrap(base * base)
+			}
+			return v, nil
+		case "&":
+			return x & y, nil
+		case "|":
+			return x | y, nil
+		case "xor":
+			return x ^ y, nil
+		case "<<", ">>":
+			if env.negative(y) {
+				return 0, calcErrorf(n.pos, "отрицательный сдвиг")
+			}
+			if y >= uint64(env.Bits) {
+				if n.op == ">>" && env.negative(x) {
+					return env.mask(), nil
+				}
+				return 0, nil
+			}
+			if n.op == "<<" {
+				return env.wrap(x << y), nil
+			}
+			// Для знаковых чисел сдвиг вправо арифметический
+			if env.Signed {
+				return env.wrap(uint64(env.signed(x) >> y)), nil
+			}
+			return x >> y, nil
+		}
+		return 0, calcErrorf(n.pos, "неизвестная операция %q", n.op)
+	}
+	return 0, calcErrorf(n.position(), "неизвестная операция")
+}
+
+// Programmer хранит состояние калькулятора программиста: размер слова,
+// знаковость, систему счисления, переменные и последний результат
+type Programmer struct {
+	Bits   int
+	Signed bool
+	Base   int
+	Vars   map[string]uint64
+	Ans    uint64
+}
+
+// NewProgrammer создает калькулятор программиста: 64 бита, знаковые числа, десятичная система
+func NewProgrammer() *Programmer {
+	return &Programmer{
+		Bits:   64,
+		Signed: true,
+		Base:   10,
+		Vars:   map[string]uint64{},
+	}
+}
+
+// SetBits изменяет размер слова, обрезая сохраненные значения
+func (p *Programmer) SetBits(n int) error {
+	valid := false
+	for _, size := range WordSizes {
+		valid = valid || size == n
+	}
+	if !valid {
+		return fmt.Errorf("размер слова должен быть 8, 16, 32 или 64 бита")
+	}
+
+	p.Bits = n
+	env := p.env()
+	p.Ans = env.wrap(p.Ans)
+	for name, v := range p.Vars {
+		p.Vars[name] = env.wrap(v)
+	}
+	return nil
+}
+
+// SetBase изменяет систему счисления ввода и вывода
+func (p *Programmer) SetBase(base int) error {
+	switch base {
+	case 2, 8, 10, 16:
+		p.Base = base
+		return nil
+	}
+	return fmt.Errorf("поддерживаются системы счисления 2, 8, 10 и 16")
+}
+
+// Eval вычисляет выражение и запоминает результат в ans.
+// Выражение вида "x = 0xFF & y" сохраняет результат в переменную x.
+func (p *Programmer) Eval(source string) (uint64, error) {
+	return p.EvalBase(source, p.Base)
+}
+
+// EvalBase вычисляет выражение, в котором числа без префикса записаны
+// в системе счисления base; сохраненная система счисления не меняется
+func (p *Programmer) EvalBase(source string, base int) (uint64, error) {
+	expr, err := ParseExpressionBase(source, base)
+	if err != nil {
+		return 0, err
+	}
+
+	env := p.env()
+	env.Base = base
+	v, err := expr.EvalInt(env)
+	if err != nil {
+		return 0, err
+	}
+	if expr.Assign != "" {
+		p.Vars[expr.Assign] = v
+	}
+	p.Ans = v
+	return v, nil
+}
+
+// env возвращает окружение вычисления с переменными и ans
+func (p *Programmer) env() *IntEnv {
+	vars := make(map[string]uint64, len(p.Vars)+1)
+	for name, v := range p.Vars {
+		vars[name] = v
+	}
+	vars["ans"] = p.Ans
+	return &IntEnv{Vars: vars, Bits: p.Bits, Signed: p.Signed, Base: p.Base}
+}
+
+// VarNames возвращает отсортированные имена переменных
+func (p *Programmer) VarNames() []string {
+	names := make([]string, 0, len(p.Vars))
+	for name := range p.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Format форматирует значение в системе счисления base без префикса.
+// Десятичные числа выводятся со знаком, остальные - как битовый образ слова.
+func (p *Programmer) Format(v uint64, base int) string {
+	env := p.env()
+	v = env.wrap(v)
+	if base == 10 && p.Signed {
+		return strconv.FormatInt(env.signed(v), 10)
+	}
+	return strings.ToUpper(strconv.FormatUint(v, base))
+}
+
+// FormatPrefixed форматирует значение с префиксом системы счисления (0x, 0o, 0b)
+func (p *Programmer) FormatPrefixed(v uint64, base int) string {
+	prefix := map[int]string{2: "0b", 8: "0o", 16: "0x"}[base]
+	return prefix + p.Format(v, base)
+}
+
+// HistoryText форматирует результат для истории вычислений так, чтобы он
+// читался одинаково в любом режиме: отрицательные знаковые числа
+// записываются со знаком минус ("-0x5", а не битовый образ "0xFB")
+func (p *Programmer) HistoryText(v uint64, base int) string {
+	env := p.env()
+	v = env.wrap(v)
+	if env.negative(v) && base != 10 {
+		return "-" + p.FormatPrefixed(uint64(-env.signed(v)), base)
+	}
+	return p.FormatPrefixed(v, base)
+}
+
+// FormatBits возвращает двоичное представление слова, разбитое на группы по 4 бита
+func (p *Programmer) FormatBits(v uint64) string {
+	text := fmt.Sprintf("%0*b", p.Bits, p.env().wrap(v))
+	var groups []string
+	for i := 0; i < len(text); i += 4 {
+		groups = append(groups, text[i:i+4])
+	}
+	return strings.Join(groups, " ")
+}
+
+// ToggleBit инвертирует бит с номером bit (0 - младший)
+func (p *Programmer) ToggleBit(v uint64, bit int) uint64 {
+	return p.env().wrap(v ^ 1<<uint(bit))
+}
+
+// BitCount возвращает количество установленных битов
+func (p *Programmer) BitCount(v uint64) int {
+	return bits.OnesCount64(p.env().wrap(v))
+}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// maxFactorial - наибольшее число, факториал которого помещается в float64
+const maxFactorial = 170
+
+// calcConstants - встроенные константы калькулятора
+var calcConstants = map[string]float64{
+	"pi": math.Pi,
+	"π":  math.Pi,
+	"e":  math.E,
+}
+
+// calcFunction описывает встроенную функцию калькулятора.
+// fn возвращает результат или непустое сообщение об ошибке.
+type calcFunction struct {
+	arity int
+	fn    func(args []float64, env *Env) (float64, string)
+}
+
+// unaryFunction оборачивает функцию одного аргумента
+func unaryFunction(f func(x float64) (float64, string)) calcFunction {
+	return calcFunction{arity: 1, fn: func(args []float64, env *Env) (float64, string) {
+		return f(args[0])
+	}}
+}
+
+// trigFunction оборачивает тригонометрическую функцию с учетом единиц углов.
+// В градусах значения в точках, кратных 90°, вычисляются точно.
+func trigFunction(f func(float64) float64, zeroAt, undefinedAt float64) calcFunction {
+	return calcFunction{arity: 1, fn: func(args []float64, env *Env) (float64, string) {
+		x := args[0]
+		if env.Degrees {
+			if undefinedAt >= 0 && math.Mod(math.Abs(x)-undefinedAt, 180) == 0 {
+				return 0, "функция не определена для этого угла"
+			}
+			if zeroAt >= 0 && math.Mod(math.Abs(x)-zeroAt, 180) == 0 {
+				return 0, ""
+			}
+			x = x * math.Pi / 180
+		}
+		return f(x), ""
+	}}
+}
+
+// inverseTrigFunction оборачивает обратную тригонометрическую функцию с учетом единиц углов
+func inverseTrigFunction(f func(float64) float64, limited bool) calcFunction {
+	return calcFunction{arity: 1, fn: func(args []float64, env *Env) (float64, string) {
+		if limited && (args[0] < -1 || args[0] > 1) {
+			return 0, "аргумент должен быть от -1 до 1"
+		}
+		v := f(args[0])
+		if env.Degrees {
+			v = v * 180 / math.Pi
+		}
+		return v, ""
+	}}
+}
+
+// calcFunctions - встроенные функции калькулятора
+var calcFunctions map[string]calcFunction
+
+func init() {
+	calcFunctions = map[string]calcFunction{
+		"sin":  trigFunction(math.Sin, 0, -1),
+		"cos":  trigFunction(math.Cos, 90, -1),
+		"tan":  trigFunction(math.Tan, 0, 90),
+		"asin": inverseTrigFunction(math.Asin, true),
+		"acos": inverseTrigFunction(math.Acos, true),
+		"atan": inverseTrigFunction(math.Atan, false),
+		"sqrt": unaryFunction(func(x float64) (float64, string) {
+			if x < 0 {
+				return 0, "корень из отрицательного числа"
+			}
+			return math.Sqrt(x), ""
+		}),
+		"ln": unaryFunction(func(x float64) (float64, string) {
+			if x <= 0 {
+				return 0, "логарифм определен только для положительных чисел"
+			}
+			return math.Log(x), ""
+		}),
+		"log": unaryFunction(func(x float64) (float64, string) {
+			if x <= 0 {
+				return 0, "логарифм определен только для положительных чисел"
+			}
+			return math.Log10(x), ""
+		}),
+		"exp": unaryFunction(func(x float64) (float64, string) {
+			return math.Exp(x), ""
+		}),
+		"abs": unaryFunction(func(x float64) (float64, string) {
+			return math.Abs(x), ""
+		}),
+		"fact": unaryFunction(factorial),
+	}
+}
+
+// factorial вычисляет факториал неотрицательного целого числа
+func factorial(x float64) (float64, string) {
+	if x < 0 || x != math.Trunc(x) {
+		return 0, "факториал определен только для неотрицательных целых чисел"
+	}
+	if x > maxFactorial {
+		return 0, fmt.Sprintf("факториал слишком велик (максимум %d!)", maxFactorial)
+	}
+	v := 1.0
+	for i := 2.0; i <= x; i++ {
+		v *= i
+	}
+	return v, ""
+}
+
+// lookupName ищет константу или переменную
+func lookupName(name string, env *Env) (float64, bool) {
+	if v, ok := calcConstants[name]; ok {
+		return v, true
+	}
+	v, ok := env.Vars[name]
+	return v, ok
+}
+
+// checkAssignable проверяет, можно ли присвоить значение переменной с таким именем
+func checkAssignable(name string) error {
+	if _, ok := calcConstants[name]; ok {
+		return fmt.Errorf("нельзя изменить константу %s", name)
+	}
+	if _, ok := calcFunctions[name]; ok {
+		return fmt.Errorf("имя %s занято функцией", name)
+	}
+	if name == "ans" {
+		return fmt.Errorf("переменная ans хранит последний результат и не может быть изменена")
+	}
+	return nil
+}
+
+// Calculator хранит состояние инженерного калькулятора: переменные,
+// последний результат (ans), ячейку памяти и единицы измерения углов.
+// В точном режиме (Exact) вычисления ведутся в рациональных числах math/big,
+// а неточные результаты выводятся с Precision знаками после запятой.
+type Calculator struct {
+	Vars      map[string]float64
+	Ans       float64
+	Memory    float64
+	Degrees   bool
+	Exact     bool
+	Precision int
+
+	// exactVars и exactAns хранят точные значения, полученные в точном режиме
+	exactVars map[string]Number
+	exactAns  *Number
+}
+
+// NewCalculator создает калькулятор с пустыми переменными и памятью
+func NewCalculator() *Calculator {
+	return &Calculator{
+		Vars:      map[string]float64{},
+		Precision: DefaultPrecision,
+		exactVars: map[string]Number{},
+	}
+}
+
+// SetPrecision задает количество знаков после запятой точного режима
+func (c *Calculator) SetPrecision(n int) error {
+	if n < 1 || n > MaxPrecision {
+		return fmt.Errorf("точность должна быть от 1 до %d знаков", MaxPrecision)
+	}
+	c.Precision = n
+	return nil
+}
+
+// Eval вычисляет выражение и запоминает результат в ans.
+// Выражение вида "x = 2 * pi" сохраняет результат в переменную x.
+// В точном режиме возвращается ближайшее к точному результату значение float64.
+func (c *Calculator) Eval(source string) (float64, error) {
+	if c.Exact {
+		n, err := c.evalExact(source)
+		if err != nil {
+			return 0, err
+		}
+		return n.Float64(), nil
+	}
+
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := expr.EvalEnv(c.env())
+	if err != nil {
+		return 0, err
+	}
+	if expr.Assign != "" {
+		c.Vars[expr.Assign] = v
+		delete(c.exactVars, expr.Assign)
+	}
+	c.Ans = v
+	c.exactAns = nil
+	return v, nil
+}
+
+// EvalText вычисляет выражение и форматирует результат в текущем режиме:
+// 15 значащих цифр для float64, точная дробь или Precision знаков в точном режиме
+func (c *Calculator) EvalText(source string) (string, error) {
+	if c.Exact {
+		n, err := c.evalExact(source)
+		if err != nil {
+			return "", err
+		}
+		return n.Format(c.Precision), nil
+	}
+
+	v, err := c.Eval(source)
+	if err != nil {
+		return "", err
+	}
+	return FormatNumber(v), nil
+}
+
+// evalExact вычисляет выражение в точном режиме и запоминает результат
+func (c *Calculator) evalExact(source string) (Number, error) {
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return Number{}, err
+	}
+
+	n, err := expr.EvalExact(c.exactEnv())
+	if err != nil {
+		return Number{}, err
+	}
+	v := n.Float64()
+	if expr.Assign != "" {
+		c.Vars[expr.Assign] = v
+		c.exactVars[expr.Assign] = n
+	}
+	c.Ans = v
+	c.exactAns = &n
+	return n, nil
+}
+
+// env возвращает окружение вычисления с переменными и ans
+func (c *Calculator) env() *Env {
+	vars := make(map[string]float64, len(c.Vars)+1)
+	for name, v := range c.Vars {
+		vars[name] = v
+	}
+	vars["ans"] = c.Ans
+	return &Env{Vars: vars, Degrees: c.Degrees}
+}
+
+// exactEnv возвращает окружение точного вычисления. Значения, полученные
+// в обычном режиме, переводятся в дроби по их десятичной записи.
+func (c *Calculator) exactEnv() *ExactEnv {
+	toNumber := func(v float64) Number {
+		if r, ok := new(big.Rat).SetString(FormatNumber(v)); ok {
+			return Number{Rat: r}
+		}
+		return Number{Rat: new(big.Rat)}
+	}
+
+	vars := make(map[string]Number, len(c.Vars)+1)
+	for name, v := range c.Vars {
+		if n, ok := c.exactVars[name]; ok {
+			vars[name] = n
+		} else {
+			vars[name] = toNumber(v)
+		}
+	}
+	if c.exactAns != nil {
+		vars["ans"] = *c.exactAns
+	} else {
+		vars["ans"] = toNumber(c.Ans)
+	}
+	return &ExactEnv{Vars: vars, Degrees: c.Degrees, Precision: c.Precision}
+}
+
+// VarText форматирует значение переменной или ans: точное значение, если оно
+// получено в точном режиме, иначе значение float64
+func (c *Calculator) VarText(name string) string {
+	if name == "ans" {
+		if c.exactAns != nil {
+			return c.exactAns.Format(c.Precision)
+		}
+		return FormatNumber(c.Ans)
+	}
+	if n, ok := c.exactVars[name]; ok {
+		return n.Format(c.Precision)
+	}
+	return FormatNumber(c.Vars[name])
+}
+
+// VarNames возвращает отсортированные имена пользовательских переменных
+func (c *Calculator) VarNames() []string {
+	names := make([]string, 0, len(c.Vars))
+	for name := range c.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MemoryAdd прибавляет значение к памяти (M+)
+func (c *Calculator) MemoryAdd(v float64) {
+	c.Memory += v
+}
+
+// MemorySubtract вычитает значение из памяти (M-)
+func (c *Calculator) MemorySubtract(v float64) {
+	c.Memory -= v
+}
+
+// MemoryRecall возвращает значение памяти (MR)
+func (c *Calculator) MemoryRecall() float64 {
+	return c.Memory
+}
+
+// MemoryClear очищает память (MC)
+func (c *Calculator) MemoryClear() {
+	c.Memory = 0
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unit - единица измерения. Значение переводится в базовую единицу категории
+// по формуле value * Factor + Offset (смещение нужно только для температур).
+type Unit struct {
+	Name     string
+	Title    string
+	Aliases  []string
+	Category string
+	Factor   float64
+	Offset   float64
+}
+
+// UnitCategories - категории единиц в порядке отображения
+var UnitCategories = []string{"Длина", "Масса", "Температура", "Данные", "Время"}
+
+// Units - поддерживаемые единицы измерения. Базовые единицы: метр, килограмм,
+// градус Цельсия, байт и секунда.
+var Units = []Unit{
+	{Name: "mm", Title: "миллиметр", Aliases: []string{"мм"}, Category: "Длина", Factor: 0.001},
+	{Name: "cm", Title: "сантиметр", Aliases: []string{"см"}, Category: "Длина", Factor: 0.01},
+	{Name: "m", Title: "метр", Aliases: []string{"м"}, Category: "Длина", Factor: 1},
+	{Name: "km", Title: "километр", Aliases: []string{"км"}, Category: "Длина", Factor: 1000},
+	{Name: "in", Title: "дюйм", Aliases: []string{"inch", "дюйм"}, Category: "Длина", Factor: 0.0254},
+	{Name: "ft", Title: "фут", Aliases: []string{"foot", "фут"}, Category: "Длина", Factor: 0.3048},
+	{Name: "yd", Title: "ярд", Aliases: []string{"yard", "ярд"}, Category: "Длина", Factor: 0.9144},
+	{Name: "mi", Title: "миля", Aliases: []string{"mile", "миля"}, Category: "Длина", Factor: 1609.344},
+	{Name: "nmi", Title: "морская миля", Category: "Длина", Factor: 1852},
+
+	{Name: "mg", Title: "миллиграмм", Aliases: []string{"мг"}, Category: "Масса", Factor: 1e-6},
+	{Name: "g", Title: "грамм", Aliases: []string{"г"}, Category: "Масса", Factor: 0.001},
+	{Name: "kg", Title: "килограмм", Aliases: []string{"кг"}, Category: "Масса", Factor: 1},
+	{Name: "t", Title: "тонна", Aliases: []string{"т"}, Category: "Масса", Factor: 1000},
+	{Name: "oz", Title: "унция", Aliases: []string{"унция"}, Category: "Масса", Factor: 0.028349523125},
+	{Name: "lb", Title: "фунт", Aliases: []string{"фунт"}, Category: "Масса", Factor: 0.45359237},
+
+	{Name: "C", Title: "градус Цельсия", Aliases: []string{"°C"}, Category: "Температура", Factor: 1},
+	{Name: "F", Title: "градус Фаренгейта", Aliases: []string{"°F"}, Category: "Температура", Factor: 5.0 / 9, Offset: -32 * 5.0 / 9},
+	{Name: "K", Title: "кельвин", Category: "Температура", Factor: 1, Offset: -273.15},
+
+	{Name: "bit", Title: "бит", Aliases: []string{"бит"}, Category: "Данные", Factor: 0.125},
+	{Name: "B", Title: "байт", Aliases: []string{"байт"}, Category: "Данные", Factor: 1},
+	{Name: "KB", Title: "килобайт", Aliases: []string{"КБ"}, Category: "Данные", Factor: 1e3},
+	{Name: "MB", Title: "мегабайт", Aliases: []string{"МБ"}, Category: "Данные", Factor: 1e6},
+	{Name: "GB", Title: "гигабайт", Aliases: []string{"ГБ"}, Category: "Данные", Factor: 1e9},
+	{Name: "TB", Title: "терабайт", Aliases: []string{"ТБ"}, Category: "Данные", Factor: 1e12},
+	{Name: "KiB", Title: "кибибайт", Category: "Данные", Factor: 1 << 10},
+	{Name: "MiB", Title: "мебибайт", Category: "Данные", Factor: 1 << 20},
+	{Name: "GiB", Title: "гибибайт", Category: "Данные", Factor: 1 << 30},
+	{Name: "TiB", Title: "тебибайт", Category: "Данные", Factor: 1 << 40},
+
+	{Name: "ms", Title: "миллисекунда", Aliases: []string{"мс"}, Category: "Время", Factor: 0.001},
+	{Name: "s", Title: "секунда", Aliases: []string{"sec", "с"}, Category: "Время", Factor: 1},
+	{Name: "min", Title: "минута", Aliases: []string{"мин"}, Category: "Время", Factor: 60},
+	{Name: "h", Title: "час", Aliases: []string{"hr", "ч"}, Category: "Время", Factor: 3600},
+	{Name: "d", Title: "сутки", Aliases: []string{"day", "сут"}, Category: "Время", Factor: 86400},
+	{Name: "wk", Title: "неделя", Aliases: []string{"week", "нед"}, Category: "Время", Factor: 604800},
+	{Name: "yr", Title: "год (365 дней)", Aliases: []string{"year", "год"}, Category: "Время", Factor: 31536000},
+}
+
+// FindUnit ищет единицу по имени или синониму. Сначала учитывается регистр,
+// затем ищется единственное совпадение без учета регистра. Единицы данных
+// без учета регистра не ищутся: b и Mb обычно обозначают биты, а не байты.
+func FindUnit(name string) (Unit, error) {
+	for _, u := range Units {
+		if u.Name == name {
+			return u, nil
+		}
+		for _, alias := range u.Aliases {
+			if alias == name {
+				return u, nil
+			}
+		}
+	}
+
+	var found []Unit
+	for _, u := range Units {
+		match := strings.EqualFold(u.Name, name)
+		for _, alias := range u.Aliases {
+			match = match || strings.EqualFold(alias, name)
+		}
+		if match {
+			found = append(found, u)
+		}
+	}
+	if len(found) == 1 && found[0].Category != "Данные" {
+		return found[0], nil
+	}
+	if len(found) == 1 {
+		return Unit{}, fmt.Errorf("неизвестная единица измерения: %s (единицы данных различаются регистром, например %s)", name, found[0].Name)
+	}
+	return Unit{}, fmt.Errorf("неизвестная единица измерения: %s", name)
+}
+
+// UnitsIn возвращает единицы категории
+func UnitsIn(category string) []Unit {
+	var units []Unit
+	for _, u := range Units {
+		if u.Category == category {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// ConvertUnits переводит значение из одной единицы в другую той же категории
+func ConvertUnits(value float64, from, to string) (float64, error) {
+	src, err := FindUnit(from)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := FindUnit(to)
+	if err != nil {
+		return 0, err
+	}
+	if src.Category != dst.Category {
+		return 0, fmt.Errorf("нельзя перевести %s (%s) в %s (%s)", src.Name, strings.ToLower(src.Category), dst.Name, strings.ToLower(dst.Category))
+	}
+
+	base := value*src.Factor + src.Offset
+	return (base - dst.Offset) / dst.Factor, nil
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFindUnit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"MB", "MB"},
+		{"МБ", "MB"},
+		{"B", "B"},
+		{"bit", "bit"},
+		{"KiB", "KiB"},
+		{"°C", "C"},
+		{"KM", "km"},
+		{"Min", "min"},
+		{"Кг", "kg"},
+	}
+	for _, tt := range tests {
+		u, err := FindUnit(tt.in)
+		if err != nil || u.Name != tt.want {
+			t.Errorf("FindUnit(%q) = %q, %v; ожидалось %q", tt.in, u.Name, err, tt.want)
+		}
+	}
+
+	// Единицы данных различаются регистром
+	for _, in := range []string{"b", "mb", "Mb", "kib", "BIT"} {
+		if u, err := FindUnit(in); err == nil {
+			t.Errorf("FindUnit(%q) = %q; ожидалась ошибка", in, u.Name)
+		}
+	}
+	if _, err := FindUnit("mb"); err == nil || !strings.Contains(err.Error(), "MB") {
+		t.Errorf("FindUnit(mb): err = %v, ожидалась подсказка MB", err)
+	}
+	if _, err := FindUnit("parsec"); err == nil {
+		t.Error("FindUnit(parsec): ожидалась ошибка")
+	}
+}
+
+func TestConvertUnits(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{1, "km", "m", 1000},
+		{1, "mi", "km", 1.609344},
+		{2, "MB", "KB", 2000},
+		{1, "MiB", "KiB", 1024},
+		{1, "GB", "MB", 1000},
+		{8, "bit", "B", 1},
+		{1, "B", "bit", 8},
+		{1, "h", "min", 60},
+		{0, "C", "K", 273.15},
+		{0, "K", "C", -273.15},
+		{100, "C", "F", 212},
+		{-40, "F", "C", -40},
+		{32, "°F", "K", 273.15},
+		{0, "K", "F", -459.67},
+	}
+	for _, tt := range tests {
+		got, err := ConvertUnits(tt.value, tt.from, tt.to)
+		if err != nil || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("ConvertUnits(%v, %s, %s) = %v, %v; ожидалось %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := ConvertUnits(1, "kg", "m"); err == nil || !strings.Contains(err.Error(), "нельзя перевести") {
+		t.Errorf("ConvertUnits(kg, m): err = %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// loadUserJSON читает JSON-файл name из папки данных приложения app текущего
+// пользователя. Отсутствующий файл не считается ошибкой: v остается без изменений.
+func (fs *FileSystem) loadUserJSON(app, name string, v interface{}) error {
+	data, err := fs.ReadFile(fs.Config.UserDataPath(app) + "/" + name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// saveUserJSON записывает v в JSON-файл name папки данных приложения app
+// текущего пользователя, создавая папку при необходимости
+func (fs *FileSystem) saveUserJSON(app, name string, v interface{}) error {
+	dir := fs.Config.UserDataPath(app)
+	if err := fs.CreateDirectories(dir); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFile(dir+"/"+name, data)
+}
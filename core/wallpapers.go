@@ -0,0 +1,340 @@
+package core
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// builtinWallpapers - обои, встроенные в MixailOS
+//
+//go:embed wallpapers/*.jpg
+var builtinWallpapers embed.FS
+
+// WallpapersDir - системная папка, в которую копируются выбранные пользователем обои
+const WallpapersDir = "/System/Wallpapers"
+
+// Значения Config.Wallpaper, кроме путей к файлам, начинаются с префикса
+const (
+	// wallpaperBuiltin - встроенные обои: "builtin:default.jpg"
+	wallpaperBuiltin = "builtin:"
+	// wallpaperColor - сплошной цвет: "color:#1e3a5f"
+	wallpaperColor = "color:"
+	// wallpaperGradient - вертикальный градиент: "gradient:#0f2027,#2c5364"
+	wallpaperGradient = "gradient:"
+)
+
+// DefaultWallpaper - обои нового пользователя
+const DefaultWallpaper = wallpaperBuiltin + "default.jpg"
+
+// WallpaperChoice - вариант обоев в галерее настроек
+type WallpaperChoice struct {
+	// Value - значение для Config.Wallpaper
+	Value string
+	Title string
+}
+
+// builtinWallpaperTitles - названия встроенных обоев
+var builtinWallpaperTitles = map[string]string{
+	"default.jpg": "Горы",
+	"sunset.jpg":  "Закат",
+	"forest.jpg":  "Лес",
+}
+
+// WallpaperPresets - готовые цвета и градиенты
+var WallpaperPresets = []WallpaperChoice{
+	{wallpaperColor + "#1e3a5f", "Синий"},
+	{wallpaperColor + "#2b2b2b", "Графит"},
+	{wallpaperColor + "#2e4d3a", "Хвоя"},
+	{wallpaperGradient + "#0f2027,#2c5364", "Ночное небо"},
+	{wallpaperGradient + "#ff7e5f,#feb47b", "Персик"},
+	{wallpaperGradient + "#43cea2,#185a9d", "Морская волна"},
+	{wallpaperGradient + "#41295a,#2f0743", "Сумерки"},
+}
+
+// WallpaperIntervals - варианты периода смены обоев в минутах; 0 - не менять
+var WallpaperIntervals = []struct {
+	Minutes int
+	Title   string
+}{
+	{0, "Не менять"},
+	{1, "Каждую минуту"},
+	{5, "Каждые 5 минут"},
+	{15, "Каждые 15 минут"},
+	{30, "Каждые 30 минут"},
+	{60, "Каждый час"},
+}
+
+// Wallpaper - обои, готовые к отрисовке: изображение, сплошной цвет или градиент
+type Wallpaper struct {
+	// Image - изображение; nil для цвета и градиента
+	Image image.Image
+	// From и To - цвета градиента сверху вниз; у сплошного цвета совпадают
+	From color.RGBA
+	To   color.RGBA
+}
+
+// Render рисует обои размером width x height. Режим mode применяется к изображениям.
+func (w *Wallpaper) Render(mode string, width, height int) *image.RGBA {
+	if w == nil || w.Image != nil {
+		var src image.Image
+		if w != nil {
+			src = w.Image
+		}
+		return RenderWallpaper(src, mode, width, height)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		t := 0.0
+		if height > 1 {
+			t = float64(y) / float64(height-1)
+		}
+		c := color.RGBA{
+			R: mixChannel(w.From.R, w.To.R, t),
+			G: mixChannel(w.From.G, w.To.G, t),
+			B: mixChannel(w.From.B, w.To.B, t),
+			A: 0xff,
+		}
+		for x := 0; x < width; x++ {
+			dst.SetRGBA(x, y, c)
+		}
+	}
+	return dst
+}
+
+// mixChannel смешивает компоненты цвета a и b в пропорции t
+func mixChannel(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5)
+}
+
+// ParseColor разбирает цвет в формате "#rrggbb"
+func ParseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("некорректный цвет: %s", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("некорректный цвет: %s", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// FormatColor записывает цвет в формате "#rrggbb"
+func FormatColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// ColorWallpaper возвращает значение Config.Wallpaper для сплошного цвета
+func ColorWallpaper(c color.Color) string {
+	return wallpaperColor + FormatColor(c)
+}
+
+// GradientWallpaper возвращает значение Config.Wallpaper для градиента сверху вниз
+func GradientWallpaper(from, to color.Color) string {
+	return wallpaperGradient + FormatColor(from) + "," + FormatColor(to)
+}
+
+// WallpaperTitle возвращает понятное название обоев для настроек
+func WallpaperTitle(value string) string {
+	for _, p := range WallpaperPresets {
+		if p.Value == value {
+			return p.Title
+		}
+	}
+	switch {
+	case strings.HasPrefix(value, wallpaperBuiltin):
+		name := strings.TrimPrefix(value, wallpaperBuiltin)
+		if title, ok := builtinWallpaperTitles[name]; ok {
+			return title
+		}
+		return name
+	case strings.HasPrefix(value, wallpaperColor):
+		return "Цвет " + strings.TrimPrefix(value, wallpaperColor)
+	case strings.HasPrefix(value, wallpaperGradient):
+		return "Градиент " + strings.Replace(strings.TrimPrefix(value, wallpaperGradient), ",", " - ", 1)
+	}
+	return filepath.Base(value)
+}
+
+// Wallpapers возвращает обои-изображения: встроенные и импортированные в WallpapersDir
+func (fs *FileSystem) Wallpapers() []WallpaperChoice {
+	var choices []WallpaperChoice
+	entries, _ := builtinWallpapers.ReadDir("wallpapers")
+	for _, e := range entries {
+		value := wallpaperBuiltin + e.Name()
+		choices = append(choices, WallpaperChoice{Value: value, Title: WallpaperTitle(value)})
+	}
+
+	dir, err := fs.ResolvePath(WallpapersDir)
+	if err != nil {
+		return choices
+	}
+	files, _ := ioutil.ReadDir(dir)
+	sort.Slice(files, func(i, j int) bool {
+		return strings.ToLower(files[i].Name()) < strings.ToLower(files[j].Name())
+	})
+	for _, f := range files {
+		if !f.IsDir() && IsImageFile(f.Name()) {
+			choices = append(choices, WallpaperChoice{Value: WallpapersDir + "/" + f.Name(), Title: f.Name()})
+		}
+	}
+	return choices
+}
+
+// ImportWallpaper копирует изображение в WallpapersDir и возвращает его путь
+// внутри MixailOS. Путь может быть путем основной системы или MixailOS. Если
+// такой же файл уже импортирован, возвращается его путь.
+func (fs *FileSystem) ImportWallpaper(name string) (string, error) {
+	src := name
+	if path, err := fs.ResolvePath(name); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			src = path
+		}
+	}
+	if !IsImageFile(src) {
+		return "", fmt.Errorf("%s не является изображением (PNG, JPEG, GIF, BMP или WebP)", filepath.Base(src))
+	}
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return "", fmt.Errorf("не удалось прочитать изображение %s: %v", filepath.Base(src), err)
+	}
+	if err := fs.CreateDirectories(WallpapersDir); err != nil {
+		return "", err
+	}
+
+	// Подбираем свободное имя: sea.jpg, sea-1.jpg, sea-2.jpg...
+	ext := filepath.Ext(src)
+	base := strings.TrimSuffix(filepath.Base(src), ext)
+	for i := 0; ; i++ {
+		fileName := base + ext
+		if i > 0 {
+			fileName = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		target := WallpapersDir + "/" + fileName
+		existing, err := fs.ReadFile(target)
+		if os.IsNotExist(err) {
+			return target, fs.WriteFile(target, data)
+		}
+		if err == nil && bytes.Equal(existing, data) {
+			return target, nil
+		}
+	}
+}
+
+// NextWallpaper возвращает обои, следующие в галерее за текущими
+func (fs *FileSystem) NextWallpaper() string {
+	choices := fs.Wallpapers()
+	if len(choices) == 0 {
+		return DefaultWallpaper
+	}
+	for i, c := range choices {
+		if c.Value == fs.Config.GetWallpaper() {
+			return choices[(i+1)%len(choices)].Value
+		}
+	}
+	return choices[0].Value
+}
+
+// WallpaperFile возвращает путь к файлу обоев в основной системе. Путь в настройках
+// может быть путем MixailOS ("/System/Wallpapers/sea.jpg") или, в старых
+// настройках, абсолютным путем основной системы.
+func (fs *FileSystem) WallpaperFile() (string, error) {
+	name := fs.Config.GetWallpaper()
+	if name == "" {
+		return "", fmt.Errorf("обои не выбраны")
+	}
+	if path, err := fs.ResolvePath(name); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	if filepath.IsAbs(name) {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("файл обоев не найден: %s", name)
+}
+
+// LoadWallpaper готовит обои из настроек: встроенное или импортированное
+// изображение (PNG, JPEG, GIF, BMP или WebP), цвет или градиент
+func (fs *FileSystem) LoadWallpaper() (*Wallpaper, error) {
+	value := fs.Config.GetWallpaper()
+	switch {
+	case strings.HasPrefix(value, wallpaperColor):
+		c, err := ParseColor(strings.TrimPrefix(value, wallpaperColor))
+		if err != nil {
+			return nil, err
+		}
+		return &Wallpaper{From: c, To: c}, nil
+
+	case strings.HasPrefix(value, wallpaperGradient):
+		parts := strings.Split(strings.TrimPrefix(value, wallpaperGradient), ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("некорректный градиент: %s", value)
+		}
+		from, err := ParseColor(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := ParseColor(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		return &Wallpaper{From: from, To: to}, nil
+
+	case strings.HasPrefix(value, wallpaperBuiltin):
+		f, err := builtinWallpapers.Open(path.Join("wallpapers", strings.TrimPrefix(value, wallpaperBuiltin)))
+		if err != nil {
+			return nil, fmt.Errorf("встроенные обои не найдены: %s", value)
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, err
+		}
+		return &Wallpaper{Image: img}, nil
+	}
+
+	file, err := fs.WallpaperFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать изображение %s: %v", filepath.Base(file), err)
+	}
+	return &Wallpaper{Image: img}, nil
+}
+
+// SetWallpaperInterval задает период смены обоев в минутах; 0 отключает смену
+func (c *Config) SetWallpaperInterval(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("некорректный период смены обоев: %d", minutes)
+	}
+	return c.update(func() error {
+		c.WallpaperInterval = minutes
+		return nil
+	})
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchDebounce - пауза после последнего изменения в папке, после которой
+// вызывается обработчик: пачка изменений (распаковка, копирование) дает один вызов
+const WatchDebounce = 200 * time.Millisecond
+
+// DirWatcher следит за содержимым папки, в том числе за изменениями,
+// сделанными в основной системе в обход MixailOS
+type DirWatcher struct {
+	dir      string
+	watcher  *fsnotify.Watcher
+	onChange func()
+	onError  func(error)
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// WatchDir начинает следить за папкой name и вызывает onChange в отдельной
+// горутине, когда изменения затихают на время debounce. Ошибки наблюдения
+// передаются в onError, который может быть nil. Наблюдение нужно
+// остановить методом Close.
+func (fs *FileSystem) WatchDir(name string, debounce time.Duration, onChange func(), onError func(error)) (*DirWatcher, error) {
+	path, err := fs.ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(path); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("не удалось следить за папкой %s: %v", fs.VirtualPath(path), err)
+	}
+
+	w := &DirWatcher{
+		dir:      fs.VirtualPath(path),
+		watcher:  watcher,
+		onChange: onChange,
+		onError:  onError,
+		done:     make(chan struct{}),
+	}
+	go w.run(debounce)
+	return w, nil
+}
+
+// Dir возвращает путь папки внутри MixailOS
+func (w *DirWatcher) Dir() string {
+	return w.dir
+}
+
+// Close останавливает наблюдение. Метод можно вызывать повторно и из обработчика onChange.
+func (w *DirWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
+
+// run получает события папки и вызывает onChange после паузы debounce
+func (w *DirWatcher) run(debounce time.Duration) {
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// Смена прав доступа не меняет список файлов
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			if w.onError != nil {
+				w.onError(fmt.Errorf("ошибка наблюдения за папкой %s: %v", w.dir, err))
+			}
+		case <-timer.C:
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+			w.onChange()
+		}
+	}
+}
@@ -4,5 +4,9 @@ go 1.16
 
 require (
 	fyne.io/fyne/v2 v2.4.5
-	github.com/fsnotify/fsnotify v1.7.0 // indirect
+	github.com/fsnotify/fsnotify v1.7.0
+	golang.org/x/image v0.11.0
+	golang.org/x/net v0.17.0
+	golang.org/x/term v0.14.0
+	golang.org/x/text v0.13.0
 )
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// stdioTerminal подключает консоль MixailOS к стандартному вводу и выводу процесса
+type stdioTerminal struct {
+	reader *bufio.Reader
+}
+
+// newStdioTerminal создает терминал поверх os.Stdin и os.Stdout
+func newStdioTerminal() *stdioTerminal {
+	return &stdioTerminal{
+		reader: bufio.NewReader(os.Stdin),
+	}
+}
+
+// Read читает данные со стандартного ввода
+func (t *stdioTerminal) Read(p []byte) (int, error) {
+	return t.reader.Read(p)
+}
+
+// Write пишет данные в стандартный вывод
+func (t *stdioTerminal) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// Size возвращает размер окна терминала
+func (t *stdioTerminal) Size() (int, int, error) {
+	return term.GetSize(int(os.Stdout.Fd()))
+}
+
+// InputReader возвращает буферизованный стандартный ввод, общий для консоли и редактора nano
+func (t *stdioTerminal) InputReader() *bufio.Reader {
+	return t.reader
+}
+
+// MakeRaw переводит стандартный ввод в "сырой" режим
+func (t *stdioTerminal) MakeRaw() (func(), error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("стандартный ввод не является терминалом")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { term.Restore(fd, state) }, nil
+}
+
+// runHeadless запускает консоль MixailOS без графического интерфейса
+func runHeadless(console *core.Console) {
+	terminal := newStdioTerminal()
+	console.Terminal = terminal
+
+	// Уведомления выводятся строкой в терминал
+	console.Notifications.Subscribe(func(n core.Notification, popup bool) {
+		if !popup {
+			return
+		}
+		text := n.Title
+		if n.Body != "" {
+			text += ": " + n.Body
+		}
+		fmt.Printf("[%s] %s\n", n.Severity, text)
+	})
+
+	fmt.Println("Добро пожаловать в консоль MixailOS!")
+	fmt.Println("Введите 'help' для получения списка доступных команд, 'exit' для выхода.")
+
+	for {
+		fmt.Printf("%s:%s>> ", console.Config.GetUsername(), console.FileSystem.VirtualPath(console.Config.CurrentDir))
+
+		line, err := terminal.reader.ReadString('\n')
+		cmd := strings.TrimSpace(line)
+		if cmd == "exit" {
+			return
+		}
+
+		if cmd != "" {
+			result := console.Execute(cmd)
+			if result == "clear" {
+				fmt.Print("\x1b[2J\x1b[H")
+			} else if result != "" {
+				fmt.Println(result)
+			}
+		}
+
+		if err == io.EOF {
+			fmt.Println()
+			return
+		}
+		if err != nil {
+			fmt.Println("Ошибка при чтении команды:", err)
+			return
+		}
+	}
+}
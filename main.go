@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,9 @@ var (
 )
 
 func main() {
+	headless := flag.Bool("headless", false, "запуск консоли MixailOS в терминале без графического интерфейса")
+	flag.Parse()
+	
 	fmt.Println("Initializing MixailOS...")
 	
 	// Инициализация рабочей директории
@@ -45,6 +49,12 @@ func main() {
 	fileSystem := core.NewFileSystem(configInstance)
 	console := core.NewConsole(fileSystem, configInstance)
 	
+	// Терминальный режим без GUI
+	if *headless {
+		runHeadless(console)
+		return
+	}
+	
 	// Запуск GUI интерфейса
 	ui.RunUI(configInstance, fileSystem, console)
 } 
\ No newline at end of file
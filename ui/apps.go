@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// AppContext передается приложению при запуске
+type AppContext struct {
+	UI *MixailOSUI
+}
+
+// App - приложение MixailOS. Чтобы добавить приложение, достаточно реализовать
+// этот интерфейс и вызвать RegisterApp в init() файла приложения: вкладки,
+// меню «Пуск», команды консоли и ассоциации файлов подхватят его автоматически.
+type App interface {
+	// Info возвращает идентификатор, название, категорию и типы файлов приложения
+	Info() core.AppInfo
+	// Icon возвращает значок приложения
+	Icon() fyne.Resource
+	// Launch создает содержимое окна приложения; вызывается один раз,
+	// при первом открытии приложения
+	Launch(ctx *AppContext) fyne.CanvasObject
+}
+
+// FileOpener реализуют приложения, которые умеют открывать файлы
+type FileOpener interface {
+	// OpenFile открывает файл по пути внутри MixailOS в запущенном приложении
+	OpenFile(ctx *AppContext, path string) error
+}
+
+// Stopper реализуют приложения с фоновой работой, которую нужно остановить,
+// когда их вкладку или окно закрывают
+type Stopper interface {
+	// Stop останавливает фоновую работу приложения
+	Stop(ctx *AppContext)
+}
+
+// registeredApps - приложения, зарегистрированные через RegisterApp
+var registeredApps []App
+
+// RegisterApp добавляет приложение в систему
+func RegisterApp(app App) {
+	registeredApps = append(registeredApps, app)
+}
+
+// builtinApp - встроенное приложение, описанное функциями
+type builtinApp struct {
+	info   core.AppInfo
+	icon   func() fyne.Resource
+	launch func(ui *MixailOSUI) fyne.CanvasObject
+	// open открывает файл; nil, если приложение не работает с файлами
+	open func(ui *MixailOSUI, path string) error
+	// stop останавливает фоновую работу при закрытии; может быть nil
+	stop func(ui *MixailOSUI)
+}
+
+// Info возвращает описание приложения
+func (b *builtinApp) Info() core.AppInfo {
+	return b.info
+}
+
+// Icon возвращает значок приложения
+func (b *builtinApp) Icon() fyne.Resource {
+	return b.icon()
+}
+
+// Launch создает содержимое приложения
+func (b *builtinApp) Launch(ctx *AppContext) fyne.CanvasObject {
+	return b.launch(ctx.UI)
+}
+
+// OpenFile открывает файл в приложении
+func (b *builtinApp) OpenFile(ctx *AppContext, path string) error {
+	if b.open == nil {
+		return fmt.Errorf("приложение %s не открывает файлы", b.info.Name)
+	}
+	return b.open(ctx.UI, path)
+}
+
+// Stop останавливает фоновую работу приложения
+func (b *builtinApp) Stop(ctx *AppContext) {
+	if b.stop != nil {
+		b.stop(ctx.UI)
+	}
+}
+
+// launcherApp - приложение, которое показывается вкладкой или окном
+// и открывается из меню «Пуск» или с панели задач
+type launcherApp struct {
+	core.AppInfo
+	App  App
+	Item *container.TabItem
+
+	// content - содержимое вкладки, в которое приложение добавляется при первом открытии
+	content  *fyne.Container
+	launched bool
+}
+
+// startApps добавляет зарегистрированные приложения в порядке Order в реестр
+// консоли. Сами приложения создаются при первом открытии.
+func (ui *MixailOSUI) startApps() {
+	apps := append([]App(nil), registeredApps...)
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].Info().Order < apps[j].Info().Order
+	})
+
+	for _, app := range apps {
+		info := app.Info()
+		_, info.OpensFiles = app.(FileOpener)
+		if err := ui.Console.Apps.Register(info); err != nil {
+			ui.notify("Ошибка регистрации приложения", err.Error(), core.SeverityError)
+			continue
+		}
+		content := container.NewMax()
+		ui.Apps = append(ui.Apps, &launcherApp{
+			AppInfo: info,
+			App:     app,
+			Item:    container.NewTabItemWithIcon(info.Name, app.Icon(), content),
+			content: content,
+		})
+	}
+}
+
+// launch создает содержимое приложения, если оно еще не запускалось
+func (ui *MixailOSUI) launch(app *launcherApp) {
+	if app.launched {
+		return
+	}
+	app.launched = true
+	app.content.Objects = []fyne.CanvasObject{app.App.Launch(&AppContext{UI: ui})}
+	app.content.Refresh()
+}
+
+// stopItem останавливает фоновую работу приложения закрытой вкладки или окна item
+func (ui *MixailOSUI) stopItem(item *container.TabItem) {
+	for _, app := range ui.Apps {
+		if app.Item != item || !app.launched {
+			continue
+		}
+		if stopper, ok := app.App.(Stopper); ok {
+			stopper.Stop(&AppContext{UI: ui})
+		}
+		return
+	}
+}
+
+// launchItem запускает приложение вкладки или окна item
+func (ui *MixailOSUI) launchItem(item *container.TabItem) {
+	for _, app := range ui.Apps {
+		if app.Item == item {
+			ui.launch(app)
+			return
+		}
+	}
+}
+
+// launchApp показывает приложение и, если path не пуст, открывает в нем файл
+func (ui *MixailOSUI) launchApp(id, path string) error {
+	app := ui.findApp(id)
+	if app == nil {
+		return fmt.Errorf("приложение не найдено: %s", id)
+	}
+	ui.showApp(id)
+	if path == "" {
+		return nil
+	}
+	opener, ok := app.App.(FileOpener)
+	if !ok {
+		return fmt.Errorf("приложение %s не открывает файлы", app.Name)
+	}
+	return opener.OpenFile(&AppContext{UI: ui}, path)
+}
+
+// startApp запускает приложение из меню «Пуск» или с панели задач
+func (ui *MixailOSUI) startApp(id string) {
+	if err := ui.Console.Launch(id, ""); err != nil {
+		dialog.ShowError(err, ui.MainWindow)
+	}
+}
+
+// openFile открывает файл в приложении, назначенном для его типа
+func (ui *MixailOSUI) openFile(path string) {
+	app, err := ui.Console.Apps.DefaultFor(ui.FileSystem.MIMEType(path), ui.Config)
+	if err == nil {
+		err = ui.Console.Launch(app.ID, path)
+	}
+	if err != nil {
+		dialog.ShowError(err, ui.MainWindow)
+	}
+}
+
+// findApp ищет приложение по идентификатору
+func (ui *MixailOSUI) findApp(id string) *launcherApp {
+	for _, app := range ui.Apps {
+		if app.ID == id {
+			return app
+		}
+	}
+	return nil
+}
+
+// showApp показывает приложение: открывает окно на рабочем столе
+// или переключается на вкладку. Вкладка, закрытая командой kill, добавляется заново.
+func (ui *MixailOSUI) showApp(id string) {
+	app := ui.findApp(id)
+	if app == nil {
+		return
+	}
+	ui.launch(app)
+	if ui.Desktop != nil {
+		ui.Desktop.open(app.Item)
+		return
+	}
+	if ui.MainTabs != nil {
+		if !ui.hasTab(app.Item) {
+			ui.MainTabs.Append(app.Item)
+			ui.registerAppProcess(app.Item)
+		}
+		ui.MainTabs.Select(app.Item)
+	}
+}
+
+// hasTab проверяет, открыта ли вкладка item
+func (ui *MixailOSUI) hasTab(item *container.TabItem) bool {
+	for _, tab := range ui.MainTabs.Items {
+		if tab == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,439 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// browserTabTitleLength - максимальная длина названия вкладки браузера
+const browserTabTitleLength = 24
+
+// browserTab - вкладка браузера со своей историей переходов
+type browserTab struct {
+	ui      *MixailOSUI
+	browser *core.Browser
+	store   *core.BrowserStore
+	nav     core.Navigator
+	page    *core.Page
+	// loadID отличает текущую загрузку от устаревших, завершившихся позже;
+	// читается в горутине загрузки, поэтому изменяется атомарно
+	loadID int64
+
+	item           *container.TabItem
+	tabs           *container.DocTabs
+	urlEntry       *widget.Entry
+	titleLabel     *widget.Label
+	statusLabel    *widget.Label
+	content        *widget.RichText
+	contentScroll  *container.Scroll
+	backButton     *widget.Button
+	forwardButton  *widget.Button
+	bookmarkButton *widget.Button
+}
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "browser", Name: "Браузер", Category: "Интернет", MIMETypes: []string{"text/html", "application/xhtml+xml", "*/*"}, Order: 4},
+		icon:   theme.SearchIcon,
+		launch: (*MixailOSUI).createBrowserTab,
+		open: func(ui *MixailOSUI, path string) error {
+			ui.openInBrowser("file://" + path)
+			return nil
+		},
+	})
+}
+
+// createBrowserTab создает вкладку с браузером
+func (ui *MixailOSUI) createBrowserTab() fyne.CanvasObject {
+	browser := core.NewBrowser(ui.FileSystem)
+	store := core.NewBrowserStore(ui.FileSystem)
+
+	// Вкладки браузера, кнопка "+" открывает новую
+	tabs := container.NewDocTabs()
+	tabs.CreateTab = func() *container.TabItem {
+		return ui.newBrowserTab(browser, store, tabs).item
+	}
+	tabs.Append(ui.newBrowserTab(browser, store, tabs).item)
+
+	ui.Browser = browser
+	ui.BrowserStore = store
+	ui.BrowserTabs = tabs
+	return tabs
+}
+
+// openInBrowser открывает адрес в новой вкладке браузера
+func (ui *MixailOSUI) openInBrowser(address string) {
+	ui.showApp("browser")
+	bt := ui.newBrowserTab(ui.Browser, ui.BrowserStore, ui.BrowserTabs)
+	ui.BrowserTabs.Append(bt.item)
+	ui.BrowserTabs.Select(bt.item)
+	bt.open(address)
+}
+
+// newBrowserTab создает новую вкладку браузера
+func (ui *MixailOSUI) newBrowserTab(browser *core.Browser, store *core.BrowserStore, tabs *container.DocTabs) *browserTab {
+	bt := &browserTab{
+		ui:      ui,
+		browser: browser,
+		store:   store,
+		tabs:    tabs,
+	}
+
+	// Поле для ввода URL
+	bt.urlEntry = widget.NewEntry()
+	bt.urlEntry.SetPlaceHolder("Введите URL...")
+	bt.urlEntry.OnSubmitted = func(address string) { bt.open(address) }
+
+	// Заголовок и состояние загрузки
+	bt.titleLabel = widget.NewLabelWithStyle("Браузер MixailOS", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	bt.statusLabel = widget.NewLabel("")
+
+	// Поле для отображения содержимого страницы
+	bt.content = widget.NewRichTextWithText("Добро пожаловать в браузер MixailOS!\nВведите URL в поле выше и нажмите 'Перейти' для начала работы.\nСправка по системе: mixail://help, файлы: file:///Documents")
+	bt.content.Wrapping = fyne.TextWrapWord
+	bt.contentScroll = container.NewScroll(bt.content)
+
+	// Кнопки навигации
+	bt.backButton = widget.NewButtonWithIcon("", theme.NavigateBackIcon(), bt.back)
+	bt.forwardButton = widget.NewButtonWithIcon("", theme.NavigateNextIcon(), bt.forward)
+	reloadButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), bt.reload)
+	bt.bookmarkButton = widget.NewButtonWithIcon("", theme.ContentAddIcon(), bt.toggleBookmark)
+	bt.updateButtons()
+
+	// Кнопка для перехода по URL
+	goButton := widget.NewButtonWithIcon("Перейти", theme.MailForwardIcon(), func() {
+		bt.open(bt.urlEntry.Text)
+	})
+
+	// URL bar
+	urlBar := container.NewBorder(
+		nil, // top
+		nil, // bottom
+		container.NewHBox(bt.backButton, bt.forwardButton, reloadButton), // left
+		container.NewHBox(
+			goButton,
+			bt.bookmarkButton,
+			widget.NewButtonWithIcon("", theme.DownloadIcon(), bt.showSaveLink),
+			widget.NewButtonWithIcon("Закладки", theme.ListIcon(), bt.showBookmarks),
+			widget.NewButtonWithIcon("История", theme.HistoryIcon(), bt.showHistory),
+		), // right
+		bt.urlEntry,
+	)
+
+	// Размещение элементов в контейнере
+	bt.item = container.NewTabItem("Новая вкладка", container.NewBorder(
+		container.NewVBox(
+			urlBar,
+			bt.titleLabel,
+		), // top
+		bt.statusLabel, // bottom
+		nil,            // left
+		nil,            // right
+		bt.contentScroll,
+	))
+	return bt
+}
+
+// open переходит по адресу, добавляя его в историю вкладки
+func (bt *browserTab) open(rawURL string) {
+	address, err := core.NormalizeURL(rawURL)
+	if err != nil {
+		bt.statusLabel.SetText(err.Error())
+		return
+	}
+
+	bt.nav.Visit(address)
+	bt.load(address)
+}
+
+// back возвращается на предыдущую страницу
+func (bt *browserTab) back() {
+	if address, ok := bt.nav.Back(); ok {
+		bt.load(address)
+	}
+}
+
+// forward переходит на следующую страницу
+func (bt *browserTab) forward() {
+	if address, ok := bt.nav.Forward(); ok {
+		bt.load(address)
+	}
+}
+
+// reload перезагружает текущую страницу
+func (bt *browserTab) reload() {
+	if address := bt.nav.Current(); address != "" {
+		bt.load(address)
+	}
+}
+
+// load загружает страницу в фоне, не блокируя интерфейс
+func (bt *browserTab) load(address string) {
+	bt.urlEntry.SetText(address)
+	bt.statusLabel.SetText("Загрузка " + address + "...")
+	bt.updateButtons()
+
+	id := atomic.AddInt64(&bt.loadID, 1)
+	go func() {
+		page, err := bt.browser.Fetch(address)
+		if id != atomic.LoadInt64(&bt.loadID) {
+			return
+		}
+		if err != nil {
+			bt.statusLabel.SetText(fmt.Sprintf("Ошибка при загрузке страницы: %v", err))
+			return
+		}
+
+		// После перенаправления запоминаем итоговый адрес
+		bt.nav.Replace(page.URL)
+		bt.page = page
+		if err := bt.store.AddHistory(page.Title, page.URL); err != nil {
+			bt.ui.notify("История браузера", fmt.Sprintf("Не удалось сохранить посещение: %v", err), core.SeverityWarning)
+		}
+
+		bt.urlEntry.SetText(page.URL)
+		bt.titleLabel.SetText(page.Title)
+		bt.content.Segments = bt.pageSegments(page)
+		bt.content.Refresh()
+		bt.contentScroll.ScrollToTop()
+		bt.statusLabel.SetText(fmt.Sprintf("Готово: HTTP %d, ссылок: %d", page.StatusCode, len(page.Links)))
+
+		bt.item.Text = shortTitle(page.Title)
+		bt.tabs.Refresh()
+		bt.updateButtons()
+	}()
+}
+
+// pageSegments превращает метки ссылок [n] в тексте страницы в кликабельные ссылки.
+// Такой же текст "[n]" на самой странице остается текстом.
+func (bt *browserTab) pageSegments(page *core.Page) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	appendText := func(text string) {
+		if text != "" {
+			segments = append(segments, &widget.TextSegment{Style: widget.RichTextStyleInline, Text: text})
+		}
+	}
+
+	// Метки всех ссылок в порядке их расположения в тексте
+	type mark struct {
+		offset int
+		link   core.Link
+		marker string
+	}
+	var marks []mark
+	for i, link := range page.Links {
+		for _, offset := range link.Offsets {
+			marks = append(marks, mark{offset, link, "[" + strconv.Itoa(i+1) + "]"})
+		}
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].offset < marks[j].offset })
+
+	last := 0
+	for _, m := range marks {
+		end := m.offset + len(m.marker)
+		if m.offset < last || end > len(page.Text) || page.Text[m.offset:end] != m.marker {
+			continue
+		}
+		appendText(page.Text[last:m.offset])
+		link := m.link
+		segments = append(segments, &widget.HyperlinkSegment{
+			Text: m.marker,
+			OnTapped: func() {
+				bt.open(link.URL)
+			},
+		})
+		last = end
+	}
+	appendText(page.Text[last:])
+	return segments
+}
+
+// updateButtons обновляет доступность кнопок навигации и значок закладки
+func (bt *browserTab) updateButtons() {
+	if bt.nav.CanGoBack() {
+		bt.backButton.Enable()
+	} else {
+		bt.backButton.Disable()
+	}
+	if bt.nav.CanGoForward() {
+		bt.forwardButton.Enable()
+	} else {
+		bt.forwardButton.Disable()
+	}
+
+	if bt.nav.Current() != "" && bt.store.IsBookmarked(bt.nav.Current()) {
+		bt.bookmarkButton.SetIcon(theme.ContentRemoveIcon())
+	} else {
+		bt.bookmarkButton.SetIcon(theme.ContentAddIcon())
+	}
+}
+
+// toggleBookmark добавляет текущую страницу в закладки или удаляет ее оттуда
+func (bt *browserTab) toggleBookmark() {
+	address := bt.nav.Current()
+	if address == "" {
+		return
+	}
+
+	var err error
+	if bt.store.IsBookmarked(address) {
+		err = bt.store.RemoveBookmark(address)
+	} else {
+		title := address
+		if bt.page != nil && bt.page.URL == address {
+			title = bt.page.Title
+		}
+		err = bt.store.AddBookmark(title, address)
+	}
+	if err != nil {
+		dialog.ShowError(err, bt.ui.MainWindow)
+	}
+	bt.updateButtons()
+}
+
+// showBookmarks показывает список закладок
+func (bt *browserTab) showBookmarks() {
+	bookmarks, err := bt.store.Bookmarks()
+	if err != nil {
+		dialog.ShowError(err, bt.ui.MainWindow)
+		return
+	}
+
+	links := make([]core.Link, len(bookmarks))
+	for i, b := range bookmarks {
+		links[i] = core.Link{Text: b.Title, URL: b.URL}
+	}
+	bt.showLinkList("Закладки", links, func(link core.Link) {
+		bt.store.RemoveBookmark(link.URL)
+		bt.updateButtons()
+	})
+}
+
+// showHistory показывает историю посещений
+func (bt *browserTab) showHistory() {
+	history, err := bt.store.History()
+	if err != nil {
+		dialog.ShowError(err, bt.ui.MainWindow)
+		return
+	}
+
+	links := make([]core.Link, len(history))
+	for i, h := range history {
+		links[i] = core.Link{Text: h.Visited.Format("02.01 15:04") + "  " + h.Title, URL: h.URL}
+	}
+	bt.showLinkList("История", links, nil)
+}
+
+// showSaveLink показывает диалог сохранения текущей страницы или одной из ее ссылок в /Downloads
+func (bt *browserTab) showSaveLink() {
+	urlEntry := widget.NewEntry()
+	urlEntry.SetText(bt.nav.Current())
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя файла (необязательно)")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Адрес", urlEntry),
+		widget.NewFormItem("Имя файла", nameEntry),
+	}
+
+	// Ссылки страницы можно выбрать из списка вместо ввода адреса
+	if page := bt.page; page != nil && len(page.Links) > 0 {
+		options := make([]string, len(page.Links))
+		for i, link := range page.Links {
+			options[i] = fmt.Sprintf("[%d] %s", i+1, link.URL)
+		}
+		links := widget.NewSelect(options, func(selected string) {
+			for i, option := range options {
+				if option == selected {
+					urlEntry.SetText(page.Links[i].URL)
+					nameEntry.SetText("")
+				}
+			}
+		})
+		links.PlaceHolder = "Выберите ссылку..."
+		items = append(items, widget.NewFormItem("Ссылка", links))
+	}
+
+	d := dialog.NewForm("Сохранить ссылку", "Скачать", "Отмена", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := bt.ui.startDownload(urlEntry.Text, nameEntry.Text, ""); err != nil {
+			dialog.ShowError(err, bt.ui.MainWindow)
+		}
+	}, bt.ui.MainWindow)
+	d.Resize(fyne.NewSize(600, 250))
+	d.Show()
+}
+
+// showLinkList показывает диалог со списком ссылок. Выбор ссылки открывает ее во вкладке.
+// Если задан onRemove, у каждой ссылки есть кнопка удаления.
+func (bt *browserTab) showLinkList(title string, links []core.Link, onRemove func(core.Link)) {
+	if len(links) == 0 {
+		dialog.ShowInformation(title, "Список пуст", bt.ui.MainWindow)
+		return
+	}
+
+	var d dialog.Dialog
+	list := widget.NewList(
+		func() int {
+			return len(links)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				widget.NewLabel("Template Item"),
+			)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			// Проверяем границы массива
+			if id < 0 || id >= len(links) {
+				return
+			}
+
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			remove := row.Objects[1].(*widget.Button)
+			label.SetText(links[id].Text + " - " + links[id].URL)
+
+			if onRemove == nil {
+				remove.Hide()
+				return
+			}
+			link := links[id]
+			remove.OnTapped = func() {
+				onRemove(link)
+				d.Hide()
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		if id >= 0 && id < len(links) {
+			d.Hide()
+			bt.open(links[id].URL)
+		}
+	}
+
+	d = dialog.NewCustom(title, "Закрыть", list, bt.ui.MainWindow)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+}
+
+// shortTitle сокращает название страницы для заголовка вкладки
+func shortTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) > browserTabTitleLength {
+		return string(runes[:browserTabTitleLength-1]) + "…"
+	}
+	return title
+}
@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// calculatorModes - режимы калькулятора
+var calculatorModes = []string{"Обычный", "Инженерный", "Программист", "Конвертер"}
+
+// calculator - калькулятор, вычисляющий выражения движком core
+type calculator struct {
+	ui *MixailOSUI
+	// engine хранит переменные, ans и память; общий с командой calc консоли
+	engine *core.Calculator
+	// prog - калькулятор программиста, также общий с консолью
+	prog *core.Programmer
+	mode string
+
+	input      *widget.Entry
+	result     *widget.Label
+	status     *widget.Label
+	angle      *widget.Button
+	exact      *fyne.Container
+	keypad     *fyne.Container
+	scientific *fyne.Container
+	programmer *programmerPanel
+	converter  *converterPanel
+
+	// history - сохраненная история вычислений пользователя, от новых к старым
+	history     []core.CalcHistoryEntry
+	historyList *widget.List
+}
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "calculator", Name: "Калькулятор", Category: "Стандартные", Order: 6},
+		icon:   theme.GridIcon,
+		launch: (*MixailOSUI).createCalculatorTab,
+	})
+}
+
+// createCalculatorTab создает вкладку с калькулятором
+func (ui *MixailOSUI) createCalculatorTab() fyne.CanvasObject {
+	calc := &calculator{
+		ui:     ui,
+		engine: ui.Console.Calculator,
+		prog:   ui.Console.Programmer,
+	}
+
+	// Поле ввода выражения: можно набирать с клавиатуры или кнопками
+	calc.input = widget.NewEntry()
+	calc.input.SetPlaceHolder("0")
+	calc.input.OnSubmitted = func(string) { calc.calculate() }
+
+	// Результат или сообщение об ошибке
+	calc.result = widget.NewLabelWithStyle("", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true})
+
+	// История вычислений: нажатие на запись подставляет результат в выражение
+	calc.historyList = widget.NewList(
+		func() int { return len(calc.history) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			e := calc.history[id]
+			item.(*widget.Label).SetText(e.Expression + " = " + e.Result)
+		},
+	)
+	calc.historyList.OnSelected = func(id widget.ListItemID) {
+		calc.historyList.Unselect(id)
+		calc.reuse(calc.history[id])
+	}
+	calc.loadHistory()
+	// История хранится отдельно для каждого пользователя
+	ui.Config.Events.Subscribe(func(core.Event) { calc.loadHistory() }, core.EventUserSwitched)
+
+	button := func(label, text string) *widget.Button {
+		return widget.NewButton(label, func() { calc.insert(text) })
+	}
+
+	// Кнопки цифр и операций
+	calc.keypad = container.NewGridWithColumns(5,
+		button("7", "7"), button("8", "8"), button("9", "9"), button("÷", " / "), button("(", "("),
+		button("4", "4"), button("5", "5"), button("6", "6"), button("×", " * "), button(")", ")"),
+		button("1", "1"), button("2", "2"), button("3", "3"), button("−", " - "), button("xʸ", "^"),
+		button("0", "0"), button(".", "."), button("%", "%"), button("+", " + "), widget.NewButton("=", calc.calculate),
+	)
+
+	// Инженерные функции, константы и память
+	calc.angle = widget.NewButton("", func() {
+		calc.engine.Degrees = !calc.engine.Degrees
+		calc.refreshStatus()
+	})
+	calc.scientific = container.NewGridWithColumns(5,
+		button("sin", "sin("), button("cos", "cos("), button("tan", "tan("), button("log", "log("), button("ln", "ln("),
+		button("asin", "asin("), button("acos", "acos("), button("atan", "atan("), button("eˣ", "exp("), button("√", "sqrt("),
+		button("π", "pi"), button("e", "e"), button("ans", "ans"), button("x!", "!"), calc.angle,
+		widget.NewButton("MC", func() {
+			calc.engine.MemoryClear()
+			calc.refreshStatus()
+		}),
+		widget.NewButton("MR", func() { calc.insert(core.FormatNumber(calc.engine.MemoryRecall())) }),
+		widget.NewButton("M+", func() { calc.memory(calc.engine.MemoryAdd) }),
+		widget.NewButton("M-", func() { calc.memory(calc.engine.MemorySubtract) }),
+		button("=", " = "),
+	)
+	calc.scientific.Hide()
+
+	// Точный режим: дроби и произвольная точность math/big
+	precisions := []string{"10", "20", "30", "50", "100", "500"}
+	precision := widget.NewSelect(precisions, func(selected string) {
+		n, _ := strconv.Atoi(selected)
+		calc.engine.SetPrecision(n)
+	})
+	precision.SetSelected(strconv.Itoa(calc.engine.Precision))
+	exactCheck := widget.NewCheck("Точные дроби", func(checked bool) {
+		calc.engine.Exact = checked
+		if checked {
+			precision.Enable()
+		} else {
+			precision.Disable()
+		}
+	})
+	exactCheck.SetChecked(calc.engine.Exact)
+	if !calc.engine.Exact {
+		precision.Disable()
+	}
+	calc.exact = container.NewHBox(exactCheck, widget.NewLabel("Знаков после запятой:"), precision)
+
+	// Режим программиста: системы счисления, размер слова, битовые операции
+	calc.programmer = calc.newProgrammerPanel()
+	calc.programmer.content.Hide()
+
+	// Конвертер единиц измерения
+	calc.converter = calc.newConverterPanel()
+	calc.converter.content.Hide()
+
+	// Переключение режимов
+	mode := widget.NewRadioGroup(calculatorModes, func(selected string) {
+		calc.mode = selected
+		calc.scientific.Hide()
+		calc.programmer.content.Hide()
+		calc.converter.content.Hide()
+		calc.exact.Show()
+		calc.keypad.Show()
+		switch selected {
+		case "Инженерный":
+			calc.scientific.Show()
+		case "Программист":
+			calc.exact.Hide()
+			calc.programmer.content.Show()
+			calc.programmer.refresh()
+		case "Конвертер":
+			calc.exact.Hide()
+			calc.keypad.Hide()
+			calc.converter.content.Show()
+		}
+	})
+	mode.Horizontal = true
+	mode.Required = true
+	mode.SetSelected(calculatorModes[0])
+
+	// Кнопки редактирования
+	edit := container.NewGridWithColumns(2,
+		widget.NewButton("C", calc.clear),
+		widget.NewButton("⌫", calc.backspace),
+	)
+
+	calc.status = widget.NewLabel("")
+	calc.refreshStatus()
+
+	// Размещение элементов в контейнере
+	return container.NewBorder(
+		container.NewVBox(
+			mode,
+			calc.exact,
+			calc.input,
+			calc.result,
+			calc.status,
+			edit,
+		), // top
+		nil, // bottom
+		nil, // left
+		nil, // right
+		container.NewGridWithRows(2,
+			container.NewVBox(calc.scientific, calc.programmer.content, calc.converter.content, calc.keypad),
+			container.NewBorder(
+				container.NewBorder(nil, nil, nil,
+					widget.NewButtonWithIcon("Очистить", theme.DeleteIcon(), calc.clearHistory),
+					widget.NewLabel("История (нажмите на запись, чтобы подставить результат)"),
+				),
+				nil, nil, nil,
+				calc.historyList,
+			),
+		),
+	)
+}
+
+// refreshStatus показывает единицы углов, содержимое памяти и переменные
+func (calc *calculator) refreshStatus() {
+	angle := "RAD"
+	if calc.engine.Degrees {
+		angle = "DEG"
+	}
+	calc.angle.SetText(angle)
+
+	status := "Углы: " + angle
+	if calc.engine.Memory != 0 {
+		status += "   M = " + core.FormatNumber(calc.engine.Memory)
+	}
+	var vars []string
+	for _, name := range calc.engine.VarNames() {
+		vars = append(vars, name+" = "+calc.engine.VarText(name))
+	}
+	if len(vars) > 0 {
+		status += "   " + strings.Join(vars, ", ")
+	}
+	calc.status.SetText(status)
+}
+
+// memory вычисляет текущее выражение (или берет ans) и применяет к нему операцию с памятью
+func (calc *calculator) memory(apply func(float64)) {
+	v := calc.engine.Ans
+	if calc.input.Text != "" {
+		var err error
+		if v, err = calc.engine.Eval(calc.input.Text); err != nil {
+			calc.result.SetText(err.Error())
+			return
+		}
+	}
+	apply(v)
+	calc.refreshStatus()
+}
+
+// insert добавляет текст в конец выражения
+func (calc *calculator) insert(text string) {
+	calc.input.SetText(calc.input.Text + text)
+	calc.input.CursorColumn = len([]rune(calc.input.Text))
+	calc.input.Refresh()
+}
+
+// clear очищает выражение и результат
+func (calc *calculator) clear() {
+	calc.input.SetText("")
+	calc.result.SetText("")
+}
+
+// backspace удаляет последний символ выражения, пропуская пробелы вокруг операций
+func (calc *calculator) backspace() {
+	runes := []rune(calc.input.Text)
+	for len(runes) > 0 && runes[len(runes)-1] == ' ' {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+	}
+	for len(runes) > 0 && runes[len(runes)-1] == ' ' {
+		runes = runes[:len(runes)-1]
+	}
+	calc.input.SetText(string(runes))
+}
+
+// calculate вычисляет выражение и добавляет его в историю.
+// При ошибке курсор ставится на место ошибки.
+func (calc *calculator) calculate() {
+	source := calc.input.Text
+	if source == "" {
+		return
+	}
+
+	text, err := calc.evaluate(source)
+	if err != nil {
+		calc.result.SetText(err.Error())
+		if ce, ok := err.(*core.CalcError); ok {
+			calc.input.CursorColumn = ce.Pos
+			calc.input.Refresh()
+			calc.ui.MainWindow.Canvas().Focus(calc.input)
+		}
+		return
+	}
+
+	calc.result.SetText(text)
+	// В поле ввода остается точная дробь без приближенного значения
+	value := strings.SplitN(text, " ≈ ", 2)[0]
+	if calc.mode == "Программист" {
+		calc.addHistory(source, calc.prog.HistoryText(calc.prog.Ans, calc.prog.Base))
+	} else {
+		calc.addHistory(source, value)
+	}
+	calc.input.SetText(value)
+	calc.input.CursorColumn = len([]rune(value))
+	calc.input.Refresh()
+	calc.refreshStatus()
+}
+
+// loadHistory загружает сохраненную историю текущего пользователя
+func (calc *calculator) loadHistory() {
+	entries, err := calc.ui.Console.CalcHistory.Entries()
+	if err != nil {
+		calc.result.SetText("Не удалось загрузить историю: " + err.Error())
+	}
+	// Новые вычисления показываются первыми
+	calc.history = make([]core.CalcHistoryEntry, len(entries))
+	for i, e := range entries {
+		calc.history[len(entries)-1-i] = e
+	}
+	calc.historyList.Refresh()
+}
+
+// addHistory сохраняет вычисление в историю пользователя
+func (calc *calculator) addHistory(expression, result string) {
+	if err := calc.ui.Console.CalcHistory.Add(expression, result, calc.mode); err != nil {
+		calc.result.SetText("Не удалось сохранить историю: " + err.Error())
+	}
+	calc.loadHistory()
+}
+
+// clearHistory очищает историю пользователя
+func (calc *calculator) clearHistory() {
+	if err := calc.ui.Console.CalcHistory.Clear(); err != nil {
+		calc.result.SetText("Не удалось очистить историю: " + err.Error())
+	}
+	calc.loadHistory()
+}
+
+// reuse подставляет результат из истории в выражение. Результаты программиста
+// записаны с префиксом системы счисления и знаком и поэтому читаются в любом режиме.
+func (calc *calculator) reuse(e core.CalcHistoryEntry) {
+	if calc.mode == "Конвертер" {
+		calc.converter.value.SetText(e.Result)
+		return
+	}
+	calc.insert(e.Result)
+}
+
+// evaluate вычисляет выражение в текущем режиме и возвращает результат в виде текста
+func (calc *calculator) evaluate(source string) (string, error) {
+	if calc.mode == "Программист" {
+		v, err := calc.prog.Eval(source)
+		if err != nil {
+			return "", err
+		}
+		calc.programmer.refresh()
+		return calc.prog.Format(v, calc.prog.Base), nil
+	}
+
+	return calc.engine.EvalText(source)
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// converterPanel - панель перевода величин между единицами измерения
+type converterPanel struct {
+	calc *calculator
+
+	content *fyne.Container
+	value   *widget.Entry
+	from    *widget.Select
+	to      *widget.Select
+	result  *widget.Label
+}
+
+// newConverterPanel создает панель конвертера единиц
+func (calc *calculator) newConverterPanel() *converterPanel {
+	cp := &converterPanel{calc: calc}
+
+	cp.value = widget.NewEntry()
+	cp.value.SetPlaceHolder("1")
+	cp.value.OnChanged = func(string) { cp.convert() }
+	cp.value.OnSubmitted = func(string) { cp.save() }
+
+	cp.from = widget.NewSelect(nil, func(string) { cp.convert() })
+	cp.to = widget.NewSelect(nil, func(string) { cp.convert() })
+	cp.result = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	// Выбор категории заменяет списки единиц
+	category := widget.NewSelect(core.UnitCategories, func(selected string) {
+		var names []string
+		for _, u := range core.UnitsIn(selected) {
+			names = append(names, u.Name+" - "+u.Title)
+		}
+		cp.from.Options = names
+		cp.to.Options = names
+		cp.from.SetSelected(names[0])
+		cp.to.SetSelected(names[1])
+	})
+	category.SetSelected(core.UnitCategories[0])
+
+	swap := widget.NewButton("⇅", func() {
+		from := cp.from.Selected
+		cp.from.SetSelected(cp.to.Selected)
+		cp.to.SetSelected(from)
+	})
+
+	cp.content = container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Величина:"), nil, category),
+		container.NewGridWithColumns(2, cp.value, cp.from),
+		container.NewBorder(nil, nil, swap, nil, cp.to),
+		cp.result,
+		widget.NewButton("Сохранить в историю", cp.save),
+	)
+	return cp
+}
+
+// unitName возвращает обозначение единицы из пункта списка "km - километр"
+func unitName(option string) string {
+	return strings.SplitN(option, " ", 2)[0]
+}
+
+// convert пересчитывает значение при любом изменении
+func (cp *converterPanel) convert() (string, bool) {
+	if cp.from.Selected == "" || cp.to.Selected == "" {
+		return "", false
+	}
+	source := cp.value.Text
+	if source == "" {
+		source = "1"
+	}
+
+	value, err := core.Evaluate(source)
+	if err == nil {
+		var result float64
+		if result, err = core.ConvertUnits(value, unitName(cp.from.Selected), unitName(cp.to.Selected)); err == nil {
+			text := core.FormatNumber(value) + " " + unitName(cp.from.Selected) + " = " + core.FormatNumber(result) + " " + unitName(cp.to.Selected)
+			cp.result.SetText(text)
+			return core.FormatNumber(result), true
+		}
+	}
+	cp.result.SetText("Ошибка: " + err.Error())
+	return "", false
+}
+
+// save добавляет текущий перевод в историю калькулятора
+func (cp *converterPanel) save() {
+	result, ok := cp.convert()
+	if !ok {
+		return
+	}
+	value := cp.value.Text
+	if value == "" {
+		value = "1"
+	}
+	cp.calc.addHistory(value+" "+unitName(cp.from.Selected)+" "+unitName(cp.to.Selected), result)
+}
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+const (
+	// desktopIconSize - размер ячейки значка на рабочем столе
+	desktopIconWidth  = 96
+	desktopIconHeight = 80
+	// windowCascade - смещение каждого нового окна относительно предыдущего
+	windowCascade = 28
+	// wallpaperCheckInterval - как часто проверяется, не пора ли сменить обои
+	wallpaperCheckInterval = 10 * time.Second
+)
+
+// defaultWindowSize - размер нового окна приложения
+var defaultWindowSize = fyne.NewSize(720, 480)
+
+// desktopView - рабочий стол: обои, значки приложений и файлов из папки Desktop,
+// и окна приложений. Открытые окна показываются на панели задач.
+type desktopView struct {
+	ui      *MixailOSUI
+	apps    []*container.TabItem
+	content *fyne.Container
+
+	wallpaper *canvas.Raster
+	icons     *fyne.Container
+	layer     *fyne.Container
+
+	// wallpaperImage - загруженные обои; читаются при отрисовке растра
+	mu             sync.Mutex
+	wallpaperImage *core.Wallpaper
+	// wallpaperValue и wallpaperMode - показанные обои и режим из настроек
+	wallpaperValue string
+	wallpaperMode  string
+	// wallpaperChanged - время последней смены обоев для их периодической смены
+	wallpaperChanged time.Time
+
+	windows []*innerWindow
+	opened  int
+}
+
+// newDesktopView создает рабочий стол для приложений apps
+func (ui *MixailOSUI) newDesktopView(apps []*container.TabItem) *desktopView {
+	d := &desktopView{ui: ui, apps: apps}
+
+	d.wallpaper = canvas.NewRaster(func(w, h int) image.Image {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.wallpaperImage.Render(ui.Config.GetWallpaperMode(), w, h)
+	})
+	d.icons = container.NewGridWrap(fyne.NewSize(desktopIconWidth, desktopIconHeight))
+	d.layer = container.NewWithoutLayout()
+
+	d.reloadWallpaper()
+	d.refreshIcons()
+	ui.Config.Events.Subscribe(func(e core.Event) {
+		if e.InDir(core.DesktopDir) {
+			d.refreshIcons()
+		}
+	}, core.EventFileCreated, core.EventFileChanged, core.EventFileDeleted, core.EventFileRenamed)
+	ui.Config.Events.Subscribe(func(core.Event) { d.applyConfig() }, core.EventConfigChanged)
+
+	d.content = container.NewMax(d.wallpaper, container.NewHBox(d.icons), d.layer)
+	go d.runWallpaperRotation()
+	return d
+}
+
+// reloadWallpaper перечитывает файл обоев из настроек
+func (d *desktopView) reloadWallpaper() {
+	img, err := d.ui.FileSystem.LoadWallpaper()
+	if err != nil {
+		img = nil
+	}
+	d.mu.Lock()
+	d.wallpaperImage = img
+	d.wallpaperValue = d.ui.Config.GetWallpaper()
+	d.wallpaperMode = d.ui.Config.GetWallpaperMode()
+	d.wallpaperChanged = time.Now()
+	d.mu.Unlock()
+	d.wallpaper.Refresh()
+}
+
+// applyConfig перерисовывает обои, если после сохранения настроек изменились обои или их режим
+func (d *desktopView) applyConfig() {
+	wallpaper, mode := d.ui.Config.GetWallpaper(), d.ui.Config.GetWallpaperMode()
+	d.mu.Lock()
+	wallpaperChanged := d.wallpaperValue != wallpaper
+	modeChanged := d.wallpaperMode != mode
+	d.wallpaperMode = mode
+	d.mu.Unlock()
+
+	switch {
+	case wallpaperChanged:
+		d.reloadWallpaper()
+	case modeChanged:
+		d.wallpaper.Refresh()
+	}
+}
+
+// runWallpaperRotation меняет обои на следующие из галереи с периодом из настроек.
+// Ошибки показываются уведомлением; горутина завершается при закрытии окна.
+func (d *desktopView) runWallpaperRotation() {
+	ticker := time.NewTicker(wallpaperCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ui.done:
+			return
+		case <-ticker.C:
+		}
+		interval := time.Duration(d.ui.Config.GetWallpaperInterval()) * time.Minute
+		d.mu.Lock()
+		due := interval > 0 && time.Since(d.wallpaperChanged) >= interval
+		d.mu.Unlock()
+		if !due {
+			continue
+		}
+		// Новые обои загрузятся по событию изменения настроек
+		if err := d.ui.Config.ChangeWallpaper(d.ui.FileSystem.NextWallpaper()); err != nil {
+			// Следующая попытка - через полный период, а не при каждой проверке
+			d.mu.Lock()
+			d.wallpaperChanged = time.Now()
+			d.mu.Unlock()
+			d.ui.notify("Обои", "Не удалось сменить обои: "+err.Error(), core.SeverityError)
+		}
+	}
+}
+
+// refreshIcons показывает значки приложений и содержимое папки рабочего стола
+func (d *desktopView) refreshIcons() {
+	d.icons.Objects = nil
+	for _, item := range d.apps {
+		app := item
+		d.icons.Add(d.newIcon(app.Icon, app.Text, func() { d.open(app) }))
+	}
+
+	items, err := d.ui.FileSystem.DesktopItems()
+	if err != nil {
+		dialog.ShowError(err, d.ui.MainWindow)
+	}
+	for _, item := range items {
+		file := item
+		icon := theme.FileIcon()
+		if file.IsDir {
+			icon = theme.FolderIcon()
+		}
+		d.icons.Add(d.newIcon(icon, file.Name, func() { d.openFile(file) }))
+	}
+	d.icons.Refresh()
+}
+
+// newIcon создает значок рабочего стола, открывающийся двойным нажатием
+func (d *desktopView) newIcon(icon fyne.Resource, name string, open func()) fyne.CanvasObject {
+	label := widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{})
+	label.Truncation = fyne.TextTruncateEllipsis
+	area := newTouchArea(container.NewBorder(nil, label, nil, nil, widget.NewIcon(icon)))
+	area.onDoubleTap = open
+	return area
+}
+
+// openFile открывает файл или папку с рабочего стола в приложении,
+// назначенном для типа файла
+func (d *desktopView) openFile(item core.DesktopItem) {
+	d.ui.openFile(item.Path)
+}
+
+// open показывает окно приложения, создавая его при первом открытии
+func (d *desktopView) open(item *container.TabItem) {
+	d.ui.launchItem(item)
+	for _, w := range d.windows {
+		if w.item == item {
+			if w.minimized {
+				w.minimized = false
+				w.frame.Show()
+			}
+			d.raise(w)
+			return
+		}
+	}
+
+	w := newInnerWindow(d, item)
+	area := d.layer.Size()
+	size := defaultWindowSize
+	if area.Width > 0 && area.Height > 0 {
+		size = size.Min(area)
+	}
+	offset := float32(windowCascade * (d.opened % 8))
+	w.frame.Resize(size)
+	w.frame.Move(fyne.NewPos(desktopIconWidth+offset, offset))
+	d.opened++
+
+	w.pid = d.ui.Console.Processes.Start(item.Text, core.ProcessApp, d.ui.Config.GetUsername(), func() {
+		d.close(w)
+	})
+	d.windows = append(d.windows, w)
+	d.layer.Add(w.frame)
+	d.raise(w)
+}
+
+// raise делает окно активным и выводит его поверх остальных
+func (d *desktopView) raise(w *innerWindow) {
+	objects := d.layer.Objects[:0]
+	for _, obj := range d.layer.Objects {
+		if obj != w.frame {
+			objects = append(objects, obj)
+		}
+	}
+	d.layer.Objects = append(objects, w.frame)
+	d.layer.Refresh()
+
+	// Активное окно выполняется, остальные ожидают
+	for _, other := range d.windows {
+		other.setActive(other == w)
+		if other == w {
+			d.ui.Console.Processes.SetState(other.pid, core.ProcessRunning)
+		} else {
+			d.ui.Console.Processes.SetState(other.pid, core.ProcessSleeping)
+		}
+	}
+	d.refreshTaskbar()
+}
+
+// minimize сворачивает окно на панель задач
+func (d *desktopView) minimize(w *innerWindow) {
+	w.minimized = true
+	w.frame.Hide()
+	d.ui.Console.Processes.SetState(w.pid, core.ProcessSleeping)
+	d.refreshTaskbar()
+}
+
+// close закрывает окно. Содержимое приложения сохраняется и будет показано
+// при следующем открытии.
+func (d *desktopView) close(w *innerWindow) {
+	for i, other := range d.windows {
+		if other == w {
+			d.windows = append(d.windows[:i], d.windows[i+1:]...)
+			break
+		}
+	}
+	d.layer.Remove(w.frame)
+	d.ui.Console.Processes.Exit(w.pid)
+	d.ui.stopItem(w.item)
+	d.refreshTaskbar()
+}
+
+// active возвращает верхнее видимое окно или nil
+func (d *desktopView) active() *innerWindow {
+	for i := len(d.layer.Objects) - 1; i >= 0; i-- {
+		for _, w := range d.windows {
+			if w.frame == d.layer.Objects[i] && !w.minimized {
+				return w
+			}
+		}
+	}
+	return nil
+}
+
+// refreshTaskbar обновляет кнопки открытых окон на панели задач. Нажатие на кнопку
+// окна сворачивает активное окно или выводит на передний план неактивное.
+func (d *desktopView) refreshTaskbar() {
+	active := d.active()
+	var buttons []fyne.CanvasObject
+	for _, w := range d.windows {
+		win := w
+		button := widget.NewButtonWithIcon(win.item.Text, win.item.Icon, func() {
+			if win == d.active() {
+				d.minimize(win)
+			} else {
+				d.open(win.item)
+			}
+		})
+		if win == active {
+			button.Importance = widget.HighImportance
+		}
+		buttons = append(buttons, button)
+	}
+	d.ui.Taskbar.setWindows(buttons)
+}
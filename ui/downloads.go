@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "downloads", Name: "Загрузки", Category: "Интернет", Order: 5},
+		icon:   theme.DownloadIcon,
+		launch: (*MixailOSUI).createDownloadsTab,
+	})
+}
+
+// watchDownloads сообщает о завершении загрузок и обновляет окно загрузок, если оно открыто
+func (ui *MixailOSUI) watchDownloads() {
+	states := map[int]core.DownloadState{}
+	ui.Console.Downloads.OnChange = func(d core.Download) {
+		ui.downloadsMu.Lock()
+		finished := d.State != states[d.ID] && (d.State == core.DownloadDone || d.State == core.DownloadFailed)
+		states[d.ID] = d.State
+		changed := ui.downloadsChanged
+		ui.downloadsMu.Unlock()
+		if changed != nil {
+			changed()
+		}
+		if finished {
+			ui.notifyDownload(d)
+		}
+	}
+}
+
+// createDownloadsTab создает вкладку менеджера загрузок
+func (ui *MixailOSUI) createDownloadsTab() fyne.CanvasObject {
+	manager := ui.Console.Downloads
+
+	// downloads читается списком и обновляется из горутин загрузок, поэтому защищен мьютексом
+	var mu sync.Mutex
+	downloads := manager.List()
+
+	list := widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(downloads)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(
+				nil, // top
+				nil, // bottom
+				nil, // left
+				container.NewHBox(
+					widget.NewButtonWithIcon("", theme.CancelIcon(), nil),
+					widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), nil),
+				), // right
+				container.NewVBox(
+					widget.NewLabel("Template Item"),
+					widget.NewProgressBar(),
+				),
+			)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			mu.Lock()
+			// Проверяем границы массива
+			if id < 0 || id >= len(downloads) {
+				mu.Unlock()
+				return
+			}
+			d := downloads[id]
+			mu.Unlock()
+
+			row := obj.(*fyne.Container)
+			info := row.Objects[0].(*fyne.Container)
+			buttons := row.Objects[1].(*fyne.Container)
+			label := info.Objects[0].(*widget.Label)
+			progress := info.Objects[1].(*widget.ProgressBar)
+			cancel := buttons.Objects[0].(*widget.Button)
+			retry := buttons.Objects[1].(*widget.Button)
+
+			text := fmt.Sprintf("%s - %s [%s]", d.Name, d.URL, d.State)
+			if d.Err != nil {
+				text += fmt.Sprintf(": %v", d.Err)
+			}
+			label.SetText(text)
+
+			if p := d.Progress(); p >= 0 {
+				progress.SetValue(p)
+			} else {
+				progress.SetValue(0)
+			}
+
+			cancel.OnTapped = func() {
+				if err := manager.Cancel(d.ID); err != nil {
+					dialog.ShowError(err, ui.MainWindow)
+				}
+			}
+			retry.OnTapped = func() {
+				if err := manager.Retry(d.ID); err != nil {
+					dialog.ShowError(err, ui.MainWindow)
+				}
+			}
+			if d.Finished() {
+				cancel.Disable()
+			} else {
+				cancel.Enable()
+			}
+			if d.State == core.DownloadFailed || d.State == core.DownloadCancelled {
+				retry.Enable()
+			} else {
+				retry.Disable()
+			}
+		},
+	)
+
+	// Обновляем список при любом изменении загрузок
+	ui.downloadsMu.Lock()
+	ui.downloadsChanged = func() {
+		mu.Lock()
+		downloads = manager.List()
+		mu.Unlock()
+		list.Refresh()
+	}
+	ui.downloadsMu.Unlock()
+
+	// Поля для добавления новой загрузки
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("Адрес файла...")
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("Имя файла (необязательно)")
+	sumEntry := widget.NewEntry()
+	sumEntry.SetPlaceHolder("Контрольная сумма, например sha256:... (необязательно)")
+
+	addDownload := func() {
+		if err := ui.startDownload(urlEntry.Text, nameEntry.Text, sumEntry.Text); err != nil {
+			dialog.ShowError(err, ui.MainWindow)
+			return
+		}
+		urlEntry.SetText("")
+		nameEntry.SetText("")
+		sumEntry.SetText("")
+	}
+	urlEntry.OnSubmitted = func(string) { addDownload() }
+	addButton := widget.NewButtonWithIcon("Скачать", theme.DownloadIcon(), addDownload)
+
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Загрузки сохраняются в "+core.DownloadsDir, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			container.NewBorder(nil, nil, nil, addButton, urlEntry),
+			container.NewGridWithColumns(2, nameEntry, sumEntry),
+		), // top
+		nil, // bottom
+		nil, // left
+		nil, // right
+		list,
+	)
+}
+
+// notifyDownload сообщает о завершении загрузки или ошибке
+func (ui *MixailOSUI) notifyDownload(d core.Download) {
+	if d.State == core.DownloadFailed {
+		id := d.ID
+		ui.Console.Notifications.Post(core.Notification{
+			Title:    "Ошибка загрузки",
+			Body:     fmt.Sprintf("%s: %v", d.Name, d.Err),
+			Severity: core.SeverityError,
+			Actions: []core.NotificationAction{
+				{Label: "Повторить", Run: func() {
+					if err := ui.Console.Downloads.Retry(id); err != nil {
+						ui.notify("Ошибка загрузки", err.Error(), core.SeverityError)
+					}
+				}},
+			},
+		})
+		return
+	}
+
+	path := d.Path()
+	ui.Console.Notifications.Post(core.Notification{
+		Title:    "Загрузка завершена",
+		Body:     path,
+		Severity: core.SeveritySuccess,
+		Actions: []core.NotificationAction{
+			{Label: "Открыть", Run: func() { ui.openFile(path) }},
+			{Label: "Показать в папке", Run: func() {
+				if err := ui.Console.Launch("files", path); err != nil {
+					ui.notify("Файлы", err.Error(), core.SeverityError)
+				}
+			}},
+		},
+	})
+}
+
+// startDownload ставит файл в очередь загрузки и переключается на вкладку загрузок
+func (ui *MixailOSUI) startDownload(address, name, checksum string) error {
+	if _, err := ui.Console.Downloads.Add(address, name, checksum); err != nil {
+		return err
+	}
+	ui.showApp("downloads")
+	return nil
+}
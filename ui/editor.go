@@ -0,0 +1,405 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// editorEntry - многострочное поле редактора, перехватывающее горячие клавиши
+type editorEntry struct {
+	widget.Entry
+	onShortcut func(shortcut fyne.Shortcut) bool
+}
+
+// newEditorEntry создает поле редактора без собственной прокрутки,
+// чтобы оно прокручивалось вместе с номерами строк
+func newEditorEntry() *editorEntry {
+	entry := &editorEntry{}
+	entry.MultiLine = true
+	entry.Wrapping = fyne.TextWrapOff
+	entry.Scroll = container.ScrollNone
+	entry.TextStyle = fyne.TextStyle{Monospace: true}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+// TypedShortcut обрабатывает горячие клавиши редактора до стандартной обработки поля ввода
+func (e *editorEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if e.onShortcut != nil && e.onShortcut(shortcut) {
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+// textEditor - встроенный текстовый редактор
+type textEditor struct {
+	ui  *MixailOSUI
+	doc *core.Document
+
+	entry        *editorEntry
+	gutter       *widget.Label
+	title        *widget.Label
+	status       *widget.Label
+	findEntry    *widget.Entry
+	replaceEntry *widget.Entry
+	findBar      *fyne.Container
+
+	// lastFound - позиция последнего найденного вхождения (в рунах)
+	lastFound int
+	// updating подавляет OnChanged при программной установке текста
+	updating bool
+}
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "editor", Name: "Редактор", Category: "Стандартные", MIMETypes: []string{"text/*", "application/json", "application/xml"}, Order: 3},
+		icon:   theme.DocumentIcon,
+		launch: (*MixailOSUI).createEditorTab,
+		open:   (*MixailOSUI).openInEditor,
+	})
+}
+
+// createEditorTab создает вкладку с текстовым редактором
+func (ui *MixailOSUI) createEditorTab() fyne.CanvasObject {
+	ed := &textEditor{
+		ui:        ui,
+		doc:       core.NewDocument(ui.FileSystem),
+		lastFound: -1,
+	}
+	ui.Editor = ed
+
+	ed.title = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	ed.status = widget.NewLabel("")
+
+	// Номера строк
+	ed.gutter = widget.NewLabelWithStyle("1", fyne.TextAlignTrailing, fyne.TextStyle{Monospace: true})
+
+	ed.entry = newEditorEntry()
+	ed.entry.OnChanged = func(text string) {
+		if ed.updating {
+			return
+		}
+		ed.doc.SetText(text)
+		ed.refreshInfo()
+	}
+	ed.entry.OnCursorChanged = ed.refreshStatus
+	ed.entry.onShortcut = ed.handleShortcut
+
+	// Панель поиска и замены
+	ed.findEntry = widget.NewEntry()
+	ed.findEntry.SetPlaceHolder("Найти...")
+	ed.findEntry.OnSubmitted = func(string) { ed.findNext() }
+	ed.replaceEntry = widget.NewEntry()
+	ed.replaceEntry.SetPlaceHolder("Заменить на...")
+	ed.findBar = container.NewBorder(
+		nil, // top
+		nil, // bottom
+		nil, // left
+		container.NewHBox(
+			widget.NewButton("Найти далее", ed.findNext),
+			widget.NewButton("Заменить", ed.replace),
+			widget.NewButton("Заменить все", ed.replaceAll),
+			widget.NewButtonWithIcon("", theme.CancelIcon(), func() { ed.findBar.Hide() }),
+		), // right
+		container.NewGridWithColumns(2, ed.findEntry, ed.replaceEntry),
+	)
+	ed.findBar.Hide()
+
+	// Toolbar для редактора
+	toolbar := container.NewHBox(
+		widget.NewButtonWithIcon("Новый", theme.DocumentCreateIcon(), ed.newDocument),
+		widget.NewButtonWithIcon("Открыть", theme.FolderOpenIcon(), ed.showOpenDialog),
+		widget.NewButtonWithIcon("Сохранить", theme.DocumentSaveIcon(), ed.save),
+		widget.NewButton("Сохранить как...", ed.showSaveAsDialog),
+		widget.NewSeparator(),
+		widget.NewButtonWithIcon("", theme.ContentUndoIcon(), ed.undo),
+		widget.NewButtonWithIcon("", theme.ContentRedoIcon(), ed.redo),
+		widget.NewSeparator(),
+		widget.NewButtonWithIcon("Найти и заменить", theme.SearchReplaceIcon(), ed.showFindBar),
+	)
+
+	ed.applyDocument()
+
+	// Размещение элементов в контейнере
+	return container.NewBorder(
+		container.NewVBox(
+			toolbar,
+			ed.title,
+			ed.findBar,
+		), // top
+		ed.status, // bottom
+		nil,       // left
+		nil,       // right
+		container.NewScroll(container.NewBorder(
+			nil,       // top
+			nil,       // bottom
+			ed.gutter, // left
+			nil,       // right
+			ed.entry,
+		)),
+	)
+}
+
+// openInEditor открывает файл в редакторе и переключается на его вкладку.
+// Несуществующий файл открывается как новый документ с этим именем.
+func (ui *MixailOSUI) openInEditor(name string) error {
+	if app := ui.findApp("editor"); app != nil {
+		ui.launch(app)
+	}
+	ed := ui.Editor
+	if ed == nil {
+		return fmt.Errorf("текстовый редактор не запущен")
+	}
+
+	path, err := ui.FileSystem.ResolvePath(name)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("%s является директорией", name)
+	}
+
+	ed.confirmDiscard(func() {
+		if err := ed.doc.Open(path); err != nil {
+			if !os.IsNotExist(err) {
+				dialog.ShowError(err, ui.MainWindow)
+				return
+			}
+			// Новый файл: открываем пустой документ, который создастся при сохранении
+			if err := ed.doc.Reset(path); err != nil {
+				dialog.ShowError(err, ui.MainWindow)
+				return
+			}
+		}
+		ed.applyDocument()
+	})
+
+	ui.showApp("editor")
+	return nil
+}
+
+// handleShortcut обрабатывает Ctrl+S, Ctrl+Z, Ctrl+Y, Ctrl+F, Ctrl+O и Ctrl+N
+func (ed *textEditor) handleShortcut(shortcut fyne.Shortcut) bool {
+	custom, ok := shortcut.(*desktop.CustomShortcut)
+	if !ok || custom.Modifier != fyne.KeyModifierShortcutDefault {
+		return false
+	}
+
+	switch custom.KeyName {
+	case fyne.KeyS:
+		ed.save()
+	case fyne.KeyZ:
+		ed.undo()
+	case fyne.KeyY:
+		ed.redo()
+	case fyne.KeyF:
+		ed.showFindBar()
+	case fyne.KeyO:
+		ed.showOpenDialog()
+	case fyne.KeyN:
+		ed.newDocument()
+	default:
+		return false
+	}
+	return true
+}
+
+// applyDocument переносит текст документа в поле редактора
+func (ed *textEditor) applyDocument() {
+	ed.updating = true
+	ed.entry.SetText(ed.doc.Text())
+	ed.updating = false
+	ed.lastFound = -1
+	ed.refreshInfo()
+}
+
+// refreshInfo обновляет заголовок, номера строк и строку состояния
+func (ed *textEditor) refreshInfo() {
+	title := ed.doc.Name()
+	if ed.doc.Path != "" {
+		title = ed.ui.FileSystem.VirtualPath(ed.doc.Path)
+	}
+	if ed.doc.Dirty() {
+		title += " *"
+	}
+	ed.title.SetText(title)
+
+	count := ed.doc.LineCount()
+	numbers := make([]string, count)
+	for i := range numbers {
+		numbers[i] = strconv.Itoa(i + 1)
+	}
+	ed.gutter.SetText(strings.Join(numbers, "\n"))
+
+	ed.refreshStatus()
+}
+
+// refreshStatus обновляет позицию курсора в строке состояния
+func (ed *textEditor) refreshStatus() {
+	ed.status.SetText(fmt.Sprintf("Строка %d, столбец %d | Строк: %d",
+		ed.entry.CursorRow+1, ed.entry.CursorColumn+1, ed.doc.LineCount()))
+}
+
+// confirmDiscard выполняет action, предварительно спросив о несохраненных изменениях
+func (ed *textEditor) confirmDiscard(action func()) {
+	if !ed.doc.Dirty() {
+		action()
+		return
+	}
+
+	dialog.ShowConfirm("Несохраненные изменения",
+		"Документ "+ed.doc.Name()+" изменен. Отменить изменения?",
+		func(confirm bool) {
+			if confirm {
+				action()
+			}
+		},
+		ed.ui.MainWindow,
+	)
+}
+
+// newDocument создает новый пустой документ
+func (ed *textEditor) newDocument() {
+	ed.confirmDiscard(func() {
+		ed.doc.Reset("")
+		ed.applyDocument()
+	})
+}
+
+// showOpenDialog запрашивает имя файла и открывает его
+func (ed *textEditor) showOpenDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("имя_файла.txt или /Documents/файл.txt")
+
+	dialog.ShowForm("Открыть файл", "Открыть", "Отмена",
+		[]*widget.FormItem{
+			widget.NewFormItem("Файл:", nameEntry),
+		},
+		func(confirm bool) {
+			if !confirm || nameEntry.Text == "" {
+				return
+			}
+			ed.confirmDiscard(func() {
+				if err := ed.doc.Open(nameEntry.Text); err != nil {
+					dialog.ShowError(err, ed.ui.MainWindow)
+					return
+				}
+				ed.applyDocument()
+			})
+		},
+		ed.ui.MainWindow,
+	)
+}
+
+// save сохраняет документ, запрашивая имя файла для нового документа
+func (ed *textEditor) save() {
+	if ed.doc.Path == "" {
+		ed.showSaveAsDialog()
+		return
+	}
+
+	if err := ed.doc.Save(); err != nil {
+		dialog.ShowError(err, ed.ui.MainWindow)
+		return
+	}
+	ed.refreshInfo()
+}
+
+// showSaveAsDialog сохраняет документ под новым именем
+func (ed *textEditor) showSaveAsDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(ed.doc.Name())
+	if ed.doc.Path == "" {
+		nameEntry.SetText("")
+		nameEntry.SetPlaceHolder("имя_файла.txt")
+	}
+
+	dialog.ShowForm("Сохранить как", "Сохранить", "Отмена",
+		[]*widget.FormItem{
+			widget.NewFormItem("Файл:", nameEntry),
+		},
+		func(confirm bool) {
+			if !confirm || nameEntry.Text == "" {
+				return
+			}
+			if err := ed.doc.SaveAs(nameEntry.Text); err != nil {
+				dialog.ShowError(err, ed.ui.MainWindow)
+				return
+			}
+			ed.refreshInfo()
+		},
+		ed.ui.MainWindow,
+	)
+}
+
+// undo отменяет последнее изменение
+func (ed *textEditor) undo() {
+	if ed.doc.Undo() {
+		ed.applyDocument()
+	}
+}
+
+// redo повторяет отмененное изменение
+func (ed *textEditor) redo() {
+	if ed.doc.Redo() {
+		ed.applyDocument()
+	}
+}
+
+// showFindBar показывает панель поиска и замены
+func (ed *textEditor) showFindBar() {
+	ed.findBar.Show()
+	ed.ui.MainWindow.Canvas().Focus(ed.findEntry)
+}
+
+// moveCursor ставит курсор в позицию pos (в рунах)
+func (ed *textEditor) moveCursor(pos int) {
+	ed.entry.CursorRow, ed.entry.CursorColumn = ed.doc.Position(pos)
+	ed.entry.Refresh()
+	ed.refreshStatus()
+}
+
+// findNext ищет следующее вхождение строки поиска
+func (ed *textEditor) findNext() {
+	query := ed.findEntry.Text
+	pos := ed.doc.Find(query, ed.lastFound+1)
+	if pos < 0 {
+		ed.lastFound = -1
+		ed.status.SetText(fmt.Sprintf("Не найдено: %s", query))
+		return
+	}
+
+	ed.lastFound = pos
+	ed.moveCursor(pos)
+}
+
+// replace заменяет найденное вхождение и переходит к следующему
+func (ed *textEditor) replace() {
+	if ed.lastFound < 0 || !ed.doc.Replace(ed.lastFound, ed.findEntry.Text, ed.replaceEntry.Text) {
+		ed.findNext()
+		return
+	}
+
+	pos := ed.lastFound + len([]rune(ed.replaceEntry.Text)) - 1
+	ed.applyDocument()
+	ed.lastFound = pos
+	ed.findNext()
+}
+
+// replaceAll заменяет все вхождения строки поиска
+func (ed *textEditor) replaceAll() {
+	count := ed.doc.ReplaceAll(ed.findEntry.Text, ed.replaceEntry.Text)
+	ed.applyDocument()
+	ed.status.SetText(fmt.Sprintf("Заменено вхождений: %d", count))
+}
@@ -0,0 +1,451 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"path"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// fileClipboard - файлы, вырезанные или скопированные в файловом менеджере
+type fileClipboard struct {
+	// paths - пути внутри MixailOS
+	paths []string
+	cut   bool
+}
+
+// fileKeys принимает клавиатурные команды файлового менеджера. Он получает фокус
+// при выборе файла, поэтому сочетания клавиш не мешают другим приложениям.
+type fileKeys struct {
+	widget.BaseWidget
+	fm *fileManager
+}
+
+// newFileKeys создает невидимый приемник клавиатурных команд
+func newFileKeys(fm *fileManager) *fileKeys {
+	keys := &fileKeys{fm: fm}
+	keys.ExtendBaseWidget(keys)
+	return keys
+}
+
+// CreateRenderer возвращает пустой рендерер
+func (k *fileKeys) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+// FocusGained нужен для интерфейса fyne.Focusable
+func (k *fileKeys) FocusGained() {
+}
+
+// FocusLost нужен для интерфейса fyne.Focusable
+func (k *fileKeys) FocusLost() {
+}
+
+// TypedRune нужен для интерфейса fyne.Focusable
+func (k *fileKeys) TypedRune(rune) {
+}
+
+// TypedKey обрабатывает F2 (переименовать), Delete (удалить), Enter (открыть)
+// и Escape (снять выбор)
+func (k *fileKeys) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyF2:
+		k.fm.startRename()
+	case fyne.KeyDelete:
+		k.fm.deleteSelected()
+	case fyne.KeyReturn, fyne.KeyEnter:
+		if entries := k.fm.selectedEntries(); len(entries) == 1 {
+			k.fm.ui.openEntry(entries[0].Name, entries[0].IsDir)
+		}
+	case fyne.KeyEscape:
+		k.fm.clearSelection()
+	}
+}
+
+// TypedShortcut обрабатывает Ctrl+X, Ctrl+C, Ctrl+V и Ctrl+A
+func (k *fileKeys) TypedShortcut(shortcut fyne.Shortcut) {
+	switch shortcut.(type) {
+	case *fyne.ShortcutCut:
+		k.fm.copySelected(true)
+	case *fyne.ShortcutCopy:
+		k.fm.copySelected(false)
+	case *fyne.ShortcutPaste:
+		k.fm.paste(k.fm.currentDir())
+	case *fyne.ShortcutSelectAll:
+		k.fm.selectAll()
+	}
+}
+
+// focus передает фокус клавиатуры файловому менеджеру
+func (fm *fileManager) focus() {
+	fm.ui.MainWindow.Canvas().Focus(fm.keys)
+}
+
+// showItemMenu показывает контекстное меню файла e в точке pos. Если файл не
+// выбран, выбирается только он; иначе действия относятся ко всем выбранным файлам.
+func (fm *fileManager) showItemMenu(e core.FileEntry, pos fyne.Position) {
+	if !fm.isSelected(e.Name) {
+		fm.selectEntry(e.Name, 0)
+	}
+	fm.focus()
+	single := len(fm.selectedNames()) == 1
+
+	var items []*fyne.MenuItem
+	if single {
+		items = append(items, fyne.NewMenuItem("Открыть", func() { fm.ui.openEntry(e.Name, e.IsDir) }))
+		if !e.IsDir {
+			items = append(items, fyne.NewMenuItem("Открыть с помощью...", func() {
+				fm.ui.showOpenWithDialog(fm.path(e.Name))
+			}))
+		}
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+
+	items = append(items,
+		fyne.NewMenuItem("Вырезать", func() { fm.copySelected(true) }),
+		fyne.NewMenuItem("Копировать", func() { fm.copySelected(false) }),
+	)
+	if single && e.IsDir && fm.hasClipboard() {
+		dir := fm.path(e.Name)
+		items = append(items, fyne.NewMenuItem("Вставить в папку", func() { fm.paste(dir) }))
+	}
+	items = append(items, fyne.NewMenuItemSeparator())
+	if single {
+		items = append(items, fyne.NewMenuItem("Переименовать", fm.startRename))
+	}
+	items = append(items,
+		fyne.NewMenuItem("Дублировать", fm.duplicateSelected),
+		fyne.NewMenuItem("Удалить", fm.deleteSelected),
+	)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), fm.ui.MainWindow.Canvas(), pos)
+}
+
+// copySelected помещает выбранные файлы в буфер; при cut они будут перемещены при вставке
+func (fm *fileManager) copySelected(cut bool) {
+	names := fm.selectedNames()
+	if len(names) == 0 {
+		return
+	}
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = fm.path(name)
+	}
+
+	fm.mu.Lock()
+	fm.clipboard = fileClipboard{paths: paths, cut: cut}
+	fm.mu.Unlock()
+	fm.apply()
+}
+
+// hasClipboard проверяет, есть ли файлы в буфере
+func (fm *fileManager) hasClipboard() bool {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return len(fm.clipboard.paths) > 0
+}
+
+// isCut проверяет, вырезан ли файл name показанной папки
+func (fm *fileManager) isCut(name string) bool {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	if !fm.clipboard.cut {
+		return false
+	}
+	filePath := path.Join(fm.dir, name)
+	for _, p := range fm.clipboard.paths {
+		if p == filePath {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictChoice - решение при вставке файла, имя которого уже занято
+type conflictChoice int
+
+const (
+	// conflictAsk - спросить пользователя
+	conflictAsk conflictChoice = iota
+	// conflictReplace - удалить существующий файл и вставить новый
+	conflictReplace
+	// conflictSkip - не вставлять файл
+	conflictSkip
+	// conflictKeepBoth - вставить файл под именем копии
+	conflictKeepBoth
+)
+
+// pasteJob - вставка файлов из буфера в папку dir. Файлы вставляются по очереди;
+// при конфликте имен вставка ждет ответа пользователя.
+type pasteJob struct {
+	fm        *fileManager
+	clipboard fileClipboard
+	dir       string
+	// choice - решение, выбранное для всех оставшихся конфликтов
+	choice conflictChoice
+	errs   []string
+	// failed - файлы, которые не удалось вставить; вырезанные остаются в буфере
+	failed []string
+}
+
+// paste вставляет файлы из буфера в папку dir (путь внутри MixailOS).
+// Скопированные файлы остаются в буфере, вырезанные после вставки удаляются из него,
+// кроме тех, которые переместить не удалось.
+func (fm *fileManager) paste(dir string) {
+	fm.mu.Lock()
+	clipboard := fm.clipboard
+	fm.mu.Unlock()
+	if len(clipboard.paths) == 0 {
+		return
+	}
+	job := &pasteJob{fm: fm, clipboard: clipboard, dir: dir}
+	job.run(0)
+}
+
+// run вставляет файлы, начиная с номера i
+func (job *pasteJob) run(i int) {
+	fs := job.fm.ui.FileSystem
+	for ; i < len(job.clipboard.paths); i++ {
+		src := job.clipboard.paths[i]
+		dst := path.Join(job.dir, path.Base(src))
+		if dst != src && fs.Exists(dst) && job.choice == conflictAsk {
+			next := i + 1
+			more := next < len(job.clipboard.paths)
+			job.fm.askConflict(path.Base(src), more, func(choice conflictChoice, forAll bool) {
+				if forAll {
+					job.choice = choice
+				}
+				job.transfer(src, dst, choice)
+				job.run(next)
+			})
+			return
+		}
+		job.transfer(src, dst, job.choice)
+	}
+	job.finish()
+}
+
+// transfer копирует или перемещает src в dst, разрешая конфликт имен решением choice.
+// При замене файл сначала вставляется под временным именем копии, и существующий
+// dst удаляется только после успешной вставки.
+func (job *pasteJob) transfer(src, dst string, choice conflictChoice) {
+	fs := job.fm.ui.FileSystem
+	name := path.Base(src)
+	replace := ""
+	switch {
+	case dst == src && job.clipboard.cut:
+		// Перемещение в ту же папку ничего не меняет
+		return
+	case dst == src:
+		// Копия в той же папке получает новое имя, как при дублировании
+		dst = fs.CopyName(job.dir, name)
+	case strings.HasPrefix(dst, src+"/"):
+		job.fail(src, fmt.Errorf("нельзя вставить папку %s в саму себя", name))
+		return
+	case !fs.Exists(dst):
+	case choice == conflictSkip:
+		return
+	case choice == conflictKeepBoth:
+		dst = fs.CopyName(job.dir, name)
+	case strings.HasPrefix(src, dst+"/"):
+		job.fail(src, fmt.Errorf("нельзя заменить %s: папка содержит вставляемый файл", dst))
+		return
+	default:
+		replace, dst = dst, fs.CopyName(job.dir, name)
+	}
+
+	var err error
+	if job.clipboard.cut {
+		err = fs.MovePath(src, dst)
+	} else {
+		err = fs.CopyPath(src, dst)
+	}
+	if err != nil {
+		job.fail(src, err)
+		return
+	}
+	if replace == "" {
+		return
+	}
+	if err := fs.RemovePath(replace); err != nil {
+		// Вставленный файл остается под именем копии, в буфере - уже он
+		job.fail(dst, err)
+		return
+	}
+	if err := fs.MovePath(dst, replace); err != nil {
+		job.fail(dst, err)
+	}
+}
+
+// fail запоминает ошибку вставки файла src; о них сообщается после вставки всех файлов
+func (job *pasteJob) fail(src string, err error) {
+	job.errs = append(job.errs, err.Error())
+	job.failed = append(job.failed, src)
+}
+
+// finish завершает вставку: после перемещения оставляет в буфере только
+// файлы, которые переместить не удалось, и показывает ошибки
+func (job *pasteJob) finish() {
+	fm := job.fm
+	if job.clipboard.cut {
+		fm.mu.Lock()
+		fm.clipboard = fileClipboard{paths: job.failed, cut: true}
+		fm.mu.Unlock()
+	}
+	fm.ui.refreshFileList()
+	if len(job.errs) > 0 {
+		dialog.ShowError(errors.New(strings.Join(job.errs, "\n")), fm.ui.MainWindow)
+	}
+}
+
+// askConflict спрашивает, что делать с файлом name, имя которого уже занято.
+// Если конфликтов может быть больше (more), решение можно применить ко всем.
+func (fm *fileManager) askConflict(name string, more bool, done func(choice conflictChoice, forAll bool)) {
+	forAll := widget.NewCheck("Применить ко всем конфликтам", nil)
+	message := widget.NewLabel("В папке уже есть «" + name + "».\nЗаменить его, пропустить или оставить оба файла?")
+	content := container.NewVBox(message)
+	if more {
+		content.Add(forAll)
+	}
+
+	var d *dialog.CustomDialog
+	choose := func(title string, choice conflictChoice) *widget.Button {
+		return widget.NewButton(title, func() {
+			d.Hide()
+			done(choice, forAll.Checked)
+		})
+	}
+	content.Add(container.NewHBox(
+		choose("Заменить", conflictReplace),
+		choose("Пропустить", conflictSkip),
+		choose("Оставить оба", conflictKeepBoth),
+	))
+	d = dialog.NewCustomWithoutButtons("Конфликт имен", content, fm.ui.MainWindow)
+	d.Show()
+}
+
+// duplicateSelected создает копии выбранных файлов в той же папке
+func (fm *fileManager) duplicateSelected() {
+	fs := fm.ui.FileSystem
+	dir := fm.currentDir()
+	var errs []string
+	for _, name := range fm.selectedNames() {
+		if err := fs.CopyPath(path.Join(dir, name), fs.CopyName(dir, name)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		dialog.ShowError(errors.New(strings.Join(errs, "\n")), fm.ui.MainWindow)
+	}
+}
+
+// deleteSelected спрашивает подтверждение и удаляет выбранные файлы; папки удаляются вместе с содержимым
+func (fm *fileManager) deleteSelected() {
+	entries := fm.selectedEntries()
+	if len(entries) == 0 {
+		return
+	}
+
+	message := "Вы уверены, что хотите удалить " + entries[0].Name + "?"
+	if len(entries) > 1 {
+		message = fmt.Sprintf("Вы уверены, что хотите удалить выбранные объекты (%d)?", len(entries))
+	}
+	for _, e := range entries {
+		if e.IsDir {
+			message += "\nПапки будут удалены вместе с содержимым."
+			break
+		}
+	}
+
+	dialog.ShowConfirm("Подтверждение", message,
+		func(confirm bool) {
+			if !confirm {
+				return
+			}
+			var errs []string
+			for _, e := range entries {
+				if err := fm.ui.FileSystem.RemovePath(e.Path); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+			if len(errs) > 0 {
+				dialog.ShowError(errors.New(strings.Join(errs, "\n")), fm.ui.MainWindow)
+			}
+		},
+		fm.ui.MainWindow,
+	)
+}
+
+// startRename показывает поле ввода имени у единственного выбранного файла
+func (fm *fileManager) startRename() {
+	names := fm.selectedNames()
+	if len(names) != 1 {
+		return
+	}
+	fm.mu.Lock()
+	fm.renaming = names[0]
+	fm.mu.Unlock()
+	fm.apply()
+}
+
+// isRenaming проверяет, редактируется ли имя файла name
+func (fm *fileManager) isRenaming(name string) bool {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.renaming == name
+}
+
+// cancelRename отменяет переименование, оставляя прежнее имя
+func (fm *fileManager) cancelRename() {
+	fm.mu.Lock()
+	fm.renaming = ""
+	fm.mu.Unlock()
+	fm.apply()
+	fm.focus()
+}
+
+// finishRename переименовывает файл oldName в newName и выбирает его. Повторные
+// вызовы (например, Enter и следующая за ним потеря фокуса) ничего не делают.
+func (fm *fileManager) finishRename(oldName, newName string) {
+	fm.mu.Lock()
+	if fm.renaming != oldName {
+		fm.mu.Unlock()
+		return
+	}
+	fm.renaming = ""
+	fm.mu.Unlock()
+
+	if err := fm.rename(oldName, strings.TrimSpace(newName)); err != nil {
+		dialog.ShowError(err, fm.ui.MainWindow)
+	}
+	fm.ui.refreshFileList()
+}
+
+// rename переименовывает файл показанной папки
+func (fm *fileManager) rename(oldName, newName string) error {
+	switch {
+	case newName == "" || newName == oldName:
+		return nil
+	case newName == "." || newName == ".." || strings.ContainsAny(newName, `/\`):
+		return fmt.Errorf("недопустимое имя: %s", newName)
+	case !strings.EqualFold(newName, oldName) && fm.ui.FileSystem.Exists(fm.path(newName)):
+		return fmt.Errorf("%s уже существует", newName)
+	}
+
+	if err := fm.ui.FileSystem.RenameFile(fm.path(oldName), fm.path(newName)); err != nil {
+		return err
+	}
+	fm.mu.Lock()
+	fm.selected = map[string]bool{newName: true}
+	fm.anchor = newName
+	fm.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,545 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "files", Name: "Файлы", Category: "Система", MIMETypes: []string{core.MIMEDirectory}, Order: 2},
+		icon:   theme.FolderIcon,
+		launch: (*MixailOSUI).createFileManagerTab,
+		open:   (*MixailOSUI).openInFileManager,
+	})
+}
+
+// fileManager - файловый менеджер: содержимое текущей папки в виде таблицы,
+// сетки или крупных значков с фильтром по имени. Режим, сортировка и показ
+// скрытых файлов запоминаются для каждой папки. Файлы можно выбирать группой,
+// вырезать, копировать и вставлять в другую папку, переименовывать и дублировать.
+type fileManager struct {
+	ui    *MixailOSUI
+	views *core.FolderViewStore
+
+	// mu защищает содержимое папки: его читают виджеты, а обновляют
+	// наблюдение за папкой и события файловой системы из других горутин
+	mu sync.Mutex
+	// dir - показанная папка (путь внутри MixailOS), view - ее настройки
+	dir  string
+	view core.FolderView
+	// files - все файлы папки, shown - отфильтрованные и отсортированные
+	files []core.FileEntry
+	shown []core.FileEntry
+	// selected - имена выбранных файлов, anchor - файл, от которого
+	// отсчитывается выбор диапазона с Shift
+	selected map[string]bool
+	anchor   string
+	// renaming - файл, имя которого сейчас редактируется
+	renaming  string
+	clipboard fileClipboard
+
+	pathLabel *widget.Label
+	status    *widget.Label
+	keys      *fileKeys
+	filter    *widget.Entry
+	hidden    *widget.Check
+	mode      *widget.Select
+	content   *fyne.Container
+	table     *widget.Table
+	grid      *widget.GridWrap
+	icons     *widget.GridWrap
+}
+
+// createFileManagerTab создает вкладку с файловым менеджером
+func (ui *MixailOSUI) createFileManagerTab() fyne.CanvasObject {
+	fm := &fileManager{ui: ui, views: core.NewFolderViewStore(ui.FileSystem), selected: map[string]bool{}}
+	ui.FileManager = fm
+
+	// Создаем метку с текущим путем
+	fm.pathLabel = widget.NewLabel(ui.Config.GetCurrentDir())
+
+	// Кнопка для перехода в родительскую директорию
+	upButton := widget.NewButtonWithIcon("Вверх", theme.NavigateBackIcon(), func() {
+		parent := filepath.Dir(ui.Config.GetCurrentDir())
+		if strings.HasPrefix(parent, ui.Config.RootDir) {
+			ui.FileSystem.ChangeDirectory("..")
+			ui.refreshFileList()
+		}
+	})
+
+	// Кнопка для создания новой директории
+	mkdirButton := widget.NewButtonWithIcon("Новая папка", theme.FolderNewIcon(), func() {
+		// Диалог для создания директории
+		dirNameEntry := widget.NewEntry()
+		dirNameEntry.SetPlaceHolder("Имя новой папки")
+
+		dialog.ShowForm("Создать новую папку", "Создать", "Отмена",
+			[]*widget.FormItem{
+				widget.NewFormItem("Имя:", dirNameEntry),
+			},
+			func(confirm bool) {
+				if confirm && dirNameEntry.Text != "" {
+					if err := ui.FileSystem.CreateDirectory(dirNameEntry.Text); err != nil {
+						dialog.ShowError(err, ui.MainWindow)
+					}
+				}
+			},
+			ui.MainWindow,
+		)
+	})
+
+	// Toolbar для файлового менеджера
+	fileToolbar := container.NewHBox(
+		upButton,
+		mkdirButton,
+		widget.NewButtonWithIcon("Удалить", theme.DeleteIcon(), func() {
+			// Проверяем, выбран ли файл
+			if len(fm.selectedNames()) == 0 {
+				dialog.ShowInformation("Внимание", "Выберите файл для удаления", ui.MainWindow)
+				return
+			}
+			fm.deleteSelected()
+		}),
+		widget.NewButtonWithIcon("Обновить", theme.ViewRefreshIcon(), func() {
+			ui.refreshFileList()
+		}),
+		widget.NewSeparator(),
+		widget.NewButtonWithIcon("", theme.ContentCutIcon(), func() { fm.copySelected(true) }),
+		widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() { fm.copySelected(false) }),
+		widget.NewButtonWithIcon("", theme.ContentPasteIcon(), func() { fm.paste(fm.currentDir()) }),
+		widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), fm.startRename),
+	)
+
+	// Фильтр по имени, скрытые файлы и режим отображения
+	fm.filter = widget.NewEntry()
+	fm.filter.SetPlaceHolder("Фильтр по имени...")
+	fm.filter.OnChanged = func(string) { fm.apply() }
+	fm.hidden = widget.NewCheck("Скрытые файлы", func(show bool) {
+		fm.changeView(func(v *core.FolderView) { v.ShowHidden = show })
+	})
+	fm.mode = fm.newViewSelect()
+
+	fm.table = fm.newTable()
+	fm.grid = fm.newGrid(core.ViewGrid)
+	fm.icons = fm.newGrid(core.ViewIcons)
+	fm.content = container.NewMax(fm.table)
+	fm.keys = newFileKeys(fm)
+	fm.status = widget.NewLabel("")
+
+	// Список обновляется, когда файлы текущей папки меняют консоль или другие приложения.
+	// Пока работает наблюдение за папкой, эти изменения приходят от него.
+	ui.Config.Events.Subscribe(func(e core.Event) {
+		if !ui.watchingFiles() && e.InDir(ui.FileSystem.VirtualPath(ui.Config.GetCurrentDir())) {
+			ui.refreshFileList()
+		}
+	}, core.EventFileCreated, core.EventFileChanged, core.EventFileDeleted, core.EventFileRenamed)
+
+	// Настройки папок хранятся отдельно для каждого пользователя
+	ui.Config.Events.Subscribe(func(core.Event) {
+		fm.mu.Lock()
+		fm.dir = ""
+		fm.mu.Unlock()
+		ui.refreshFileList()
+	}, core.EventUserSwitched)
+
+	ui.refreshFileList()
+
+	// Размещение элементов в контейнере
+	return container.NewBorder(
+		container.NewVBox(
+			fm.pathLabel,
+			fileToolbar,
+			container.NewBorder(nil, nil, nil, container.NewHBox(fm.hidden, fm.mode), fm.filter),
+		), // top
+		fm.status, // bottom
+		nil,       // left
+		nil,       // right
+		container.NewMax(fm.keys, fm.content),
+	)
+}
+
+// reload перечитывает текущую папку. При переходе в другую папку загружаются
+// ее настройки отображения, а фильтр и выбор сбрасываются.
+func (fm *fileManager) reload() {
+	dir := fm.ui.FileSystem.VirtualPath(fm.ui.Config.GetCurrentDir())
+	files, err := fm.ui.FileSystem.ListFiles()
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("Ошибка при получении списка файлов: %v", err), fm.ui.MainWindow)
+		return
+	}
+
+	fm.mu.Lock()
+	changedDir := dir != fm.dir
+	if changedDir {
+		fm.dir = dir
+		fm.view = fm.views.Get(dir)
+		fm.selected = map[string]bool{}
+		fm.anchor, fm.renaming = "", ""
+	}
+	fm.files = files
+	view := fm.view
+	fm.mu.Unlock()
+
+	// Обновляем текст текущего пути и элементы настроек папки
+	fm.pathLabel.SetText(fm.ui.Config.GetCurrentDir())
+	if changedDir {
+		fm.hidden.SetChecked(view.ShowHidden)
+		fm.mode.SetSelected(viewModeTitle(view.Mode))
+		fm.filter.SetText("")
+	}
+	fm.apply()
+}
+
+// apply фильтрует и сортирует файлы и показывает их в выбранном режиме
+func (fm *fileManager) apply() {
+	fm.mu.Lock()
+	shown := core.FilterFileEntries(fm.files, fm.filter.Text, fm.view.ShowHidden)
+	core.SortFileEntries(shown, fm.view.SortBy, fm.view.Descending)
+	fm.shown = shown
+	// Выбор остается только у показанных файлов
+	selected := map[string]bool{}
+	for _, e := range shown {
+		if fm.selected[e.Name] {
+			selected[e.Name] = true
+		}
+	}
+	fm.selected = selected
+	mode := fm.view.Mode
+	fm.mu.Unlock()
+
+	var view fyne.CanvasObject
+	switch mode {
+	case core.ViewGrid:
+		view = fm.grid
+	case core.ViewIcons:
+		view = fm.icons
+	default:
+		view = fm.table
+	}
+	if fm.content.Objects[0] != view {
+		fm.content.Objects = []fyne.CanvasObject{view}
+		fm.content.Refresh()
+	}
+	view.Refresh()
+	fm.updateStatus()
+}
+
+// updateStatus показывает в строке состояния число файлов, выбранных файлов и файлов в буфере
+func (fm *fileManager) updateStatus() {
+	fm.mu.Lock()
+	text := fmt.Sprintf("Объектов: %d", len(fm.shown))
+	if n := len(fm.selected); n > 0 {
+		text += fmt.Sprintf(", выбрано: %d", n)
+	}
+	if n := len(fm.clipboard.paths); n > 0 {
+		action := "скопировано"
+		if fm.clipboard.cut {
+			action = "вырезано"
+		}
+		text += fmt.Sprintf(" · в буфере: %d (%s)", n, action)
+	}
+	fm.mu.Unlock()
+	fm.status.SetText(text)
+}
+
+// changeView изменяет настройки текущей папки и сохраняет их
+func (fm *fileManager) changeView(change func(v *core.FolderView)) {
+	fm.mu.Lock()
+	view := fm.view
+	change(&view)
+	if view == fm.view || fm.dir == "" {
+		fm.mu.Unlock()
+		return
+	}
+	fm.view = view
+	dir := fm.dir
+	fm.mu.Unlock()
+
+	if err := fm.views.Set(dir, view); err != nil {
+		dialog.ShowError(err, fm.ui.MainWindow)
+	}
+	fm.apply()
+}
+
+// sortBy сортирует по колонке; повторное нажатие меняет направление сортировки
+func (fm *fileManager) sortBy(column string) {
+	fm.changeView(func(v *core.FolderView) {
+		if v.SortBy == column {
+			v.Descending = !v.Descending
+		} else {
+			v.SortBy, v.Descending = column, false
+		}
+	})
+}
+
+// entry возвращает показанный файл с номером id
+func (fm *fileManager) entry(id int) (core.FileEntry, bool) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	// Проверяем границы массива
+	if id < 0 || id >= len(fm.shown) {
+		return core.FileEntry{}, false
+	}
+	return fm.shown[id], true
+}
+
+// selectEntry выбирает файл текущей папки. С Ctrl выбор файла переключается,
+// не затрагивая остальные, с Shift выбираются все файлы от предыдущего выбранного.
+func (fm *fileManager) selectEntry(name string, modifier fyne.KeyModifier) {
+	fm.mu.Lock()
+	switch {
+	case modifier&fyne.KeyModifierShift != 0 && fm.indexOf(fm.anchor) >= 0:
+		from, to := fm.indexOf(fm.anchor), fm.indexOf(name)
+		if from > to {
+			from, to = to, from
+		}
+		fm.selected = map[string]bool{}
+		for _, e := range fm.shown[from : to+1] {
+			fm.selected[e.Name] = true
+		}
+	case modifier&fyne.KeyModifierShortcutDefault != 0:
+		if fm.selected[name] {
+			delete(fm.selected, name)
+		} else {
+			fm.selected[name] = true
+		}
+		fm.anchor = name
+	default:
+		fm.selected = map[string]bool{name: true}
+		fm.anchor = name
+	}
+	fm.mu.Unlock()
+	fm.refreshSelection()
+}
+
+// selectAll выбирает все показанные файлы
+func (fm *fileManager) selectAll() {
+	fm.mu.Lock()
+	fm.selected = map[string]bool{}
+	for _, e := range fm.shown {
+		fm.selected[e.Name] = true
+	}
+	fm.mu.Unlock()
+	fm.refreshSelection()
+}
+
+// clearSelection снимает выбор со всех файлов
+func (fm *fileManager) clearSelection() {
+	fm.mu.Lock()
+	fm.selected = map[string]bool{}
+	fm.anchor = ""
+	fm.mu.Unlock()
+	fm.refreshSelection()
+}
+
+// refreshSelection перерисовывает подсветку выбранных файлов
+func (fm *fileManager) refreshSelection() {
+	fm.content.Objects[0].Refresh()
+	fm.updateStatus()
+}
+
+// indexOf возвращает номер показанного файла name или -1; вызывается под fm.mu
+func (fm *fileManager) indexOf(name string) int {
+	for i, e := range fm.shown {
+		if e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// isSelected проверяет, выбран ли файл
+func (fm *fileManager) isSelected(name string) bool {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.selected[name]
+}
+
+// selectedNames возвращает имена выбранных файлов в порядке их показа
+func (fm *fileManager) selectedNames() []string {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	var names []string
+	for _, e := range fm.shown {
+		if fm.selected[e.Name] {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// selectedEntries возвращает выбранные файлы в порядке их показа
+func (fm *fileManager) selectedEntries() []core.FileEntry {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	var entries []core.FileEntry
+	for _, e := range fm.shown {
+		if fm.selected[e.Name] {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// currentDir возвращает показанную папку (путь внутри MixailOS)
+func (fm *fileManager) currentDir() string {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	return fm.dir
+}
+
+// path возвращает путь внутри MixailOS к файлу name показанной папки
+func (fm *fileManager) path(name string) string {
+	return path.Join(fm.currentDir(), name)
+}
+
+// openEntry открывает папку в файловом менеджере, а файл - в приложении,
+// назначенном для его типа
+func (ui *MixailOSUI) openEntry(name string, isDir bool) {
+	if isDir {
+		ui.FileSystem.ChangeDirectory(name)
+		ui.refreshFileList()
+		return
+	}
+	path, err := ui.currentFilePath(name)
+	if err != nil {
+		dialog.ShowError(err, ui.MainWindow)
+		return
+	}
+	ui.openFile(path)
+}
+
+// currentFilePath возвращает путь внутри MixailOS к файлу текущей директории
+func (ui *MixailOSUI) currentFilePath(name string) (string, error) {
+	path, err := ui.FileSystem.ResolvePath(name)
+	if err != nil {
+		return "", err
+	}
+	return ui.FileSystem.VirtualPath(path), nil
+}
+
+// showOpenWithDialog предлагает выбрать приложение для файла. Сначала идут
+// приложения, поддерживающие тип файла, затем остальные приложения, умеющие
+// открывать файлы. Выбор можно запомнить для всех файлов этого типа.
+func (ui *MixailOSUI) showOpenWithDialog(path string) {
+	mimeType := ui.FileSystem.MIMEType(path)
+	apps := ui.Console.Apps.ForMIME(mimeType)
+	for _, app := range ui.Console.Apps.FileOpeners() {
+		if !app.Supports(mimeType) {
+			apps = append(apps, app)
+		}
+	}
+
+	names := make([]string, len(apps))
+	for i, app := range apps {
+		names[i] = app.Name
+	}
+	choice := widget.NewRadioGroup(names, nil)
+	if app, err := ui.Console.Apps.DefaultFor(mimeType, ui.Config); err == nil {
+		choice.SetSelected(app.Name)
+	}
+	remember := widget.NewCheck("Всегда открывать файлы "+mimeType+" в этом приложении", nil)
+
+	content := container.NewBorder(
+		widget.NewLabel(filepath.Base(path)+" ("+mimeType+")"), // top
+		remember, // bottom
+		nil,      // left
+		nil,      // right
+		container.NewVScroll(choice),
+	)
+	d := dialog.NewCustomConfirm("Открыть с помощью", "Открыть", "Отмена", content, func(ok bool) {
+		if !ok || choice.Selected == "" {
+			return
+		}
+		app, _ := ui.Console.Apps.Find(choice.Selected)
+		if remember.Checked {
+			if err := ui.Config.SetAssociation(mimeType, app); err != nil {
+				dialog.ShowError(err, ui.MainWindow)
+			}
+		}
+		if err := ui.Console.Launch(app.ID, path); err != nil {
+			dialog.ShowError(err, ui.MainWindow)
+		}
+	}, ui.MainWindow)
+	d.Resize(fyne.NewSize(360, 400))
+	d.Show()
+}
+
+// openInFileManager показывает в файловом менеджере папку path или папку, содержащую файл path
+func (ui *MixailOSUI) openInFileManager(path string) error {
+	dir, err := ui.FileSystem.ResolvePath(path)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	if err := ui.FileSystem.ChangeDirectory(dir); err != nil {
+		return err
+	}
+	ui.refreshFileList()
+	return nil
+}
+
+// watchCurrentDir следит за текущей папкой, чтобы список обновлялся и при изменениях
+// из основной системы. При переходе в другую папку прежнее наблюдение закрывается.
+func (ui *MixailOSUI) watchCurrentDir() {
+	dir := ui.FileSystem.VirtualPath(ui.Config.GetCurrentDir())
+	ui.fileWatcherMu.Lock()
+	defer ui.fileWatcherMu.Unlock()
+
+	if ui.fileWatcher != nil {
+		if ui.fileWatcher.Dir() == dir {
+			return
+		}
+		ui.fileWatcher.Close()
+		ui.fileWatcher = nil
+	}
+	// Об ошибках сообщается один раз для папки, чтобы каждое обновление
+	// списка не показывало новое уведомление
+	var reportOnce sync.Once
+	report := func(err error) {
+		reportOnce.Do(func() {
+			ui.notify("Файлы", err.Error()+"\nИзменения из основной системы могут не отображаться", core.SeverityWarning)
+		})
+	}
+	watcher, err := ui.FileSystem.WatchDir(dir, core.WatchDebounce, ui.refreshFileList, report)
+	if err != nil {
+		// Список по-прежнему обновляется по событиям MixailOS
+		if ui.fileWatchFailed != dir {
+			ui.fileWatchFailed = dir
+			report(err)
+		}
+		return
+	}
+	ui.fileWatchFailed = ""
+	ui.fileWatcher = watcher
+}
+
+// watchingFiles проверяет, работает ли наблюдение за текущей папкой
+func (ui *MixailOSUI) watchingFiles() bool {
+	ui.fileWatcherMu.Lock()
+	defer ui.fileWatcherMu.Unlock()
+	return ui.fileWatcher != nil && ui.fileWatcher.Dir() == ui.FileSystem.VirtualPath(ui.Config.GetCurrentDir())
+}
+
+// refreshFileList обновляет список файлов в UI
+func (ui *MixailOSUI) refreshFileList() {
+	ui.watchCurrentDir()
+	if ui.FileManager != nil {
+		ui.FileManager.reload()
+	}
+}
@@ -0,0 +1,380 @@
+package ui
+
+import (
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// fileColumns - колонки таблицы файлов: заголовок, ключ сортировки и ширина
+var fileColumns = []struct {
+	Title  string
+	SortBy string
+	Width  float32
+}{
+	{"Имя", core.SortByName, 280},
+	{"Размер", core.SortBySize, 90},
+	{"Тип", core.SortByType, 170},
+	{"Изменен", core.SortByModified, 130},
+}
+
+// fileViewModes - режимы отображения папки в порядке выбора
+var fileViewModes = []struct {
+	Mode  string
+	Title string
+}{
+	{core.ViewList, "Список"},
+	{core.ViewGrid, "Сетка"},
+	{core.ViewIcons, "Значки"},
+}
+
+// Размеры ячеек в режимах сетки и крупных значков
+var (
+	gridIconSize  = fyne.NewSize(32, 32)
+	largeIconSize = fyne.NewSize(64, 64)
+	gridItemWidth = float32(96)
+	iconItemWidth = float32(128)
+)
+
+// fileIcon возвращает значок файла по его типу
+func fileIcon(e core.FileEntry) fyne.Resource {
+	if e.IsDir {
+		return theme.FolderIcon()
+	}
+	switch strings.SplitN(e.MIMEType, "/", 2)[0] {
+	case "image":
+		return theme.FileImageIcon()
+	case "text":
+		return theme.FileTextIcon()
+	case "audio":
+		return theme.FileAudioIcon()
+	case "video":
+		return theme.FileVideoIcon()
+	case "application":
+		return theme.FileApplicationIcon()
+	}
+	return theme.FileIcon()
+}
+
+// fileTypeName возвращает текст колонки «Тип»
+func fileTypeName(e core.FileEntry) string {
+	if e.IsDir {
+		return "Папка"
+	}
+	return e.MIMEType
+}
+
+// fileItem - файл в таблице или сетке файлового менеджера. Нажатие выбирает
+// файл (с Ctrl или Shift - несколько файлов), двойное нажатие открывает его,
+// правая кнопка показывает контекстное меню. При переименовании вместо имени
+// показывается поле ввода.
+type fileItem struct {
+	widget.BaseWidget
+	background *canvas.Rectangle
+	icon       *widget.Icon
+	label      *widget.Label
+	entry      *renameEntry
+	content    fyne.CanvasObject
+
+	// modifier - клавиши-модификаторы, зажатые при нажатии кнопки мыши
+	modifier fyne.KeyModifier
+	// editing - файл, имя которого редактируется в поле ввода
+	editing string
+
+	onTapped       func(modifier fyne.KeyModifier)
+	onDoubleTapped func()
+	onSecondaryTap func(pos fyne.Position)
+}
+
+// newFileItem создает ячейку для режима mode: строку таблицы, значок сетки или крупный значок
+func newFileItem(mode string) *fileItem {
+	item := &fileItem{
+		background: canvas.NewRectangle(color.Transparent),
+		icon:       widget.NewIcon(theme.FileIcon()),
+		label:      widget.NewLabel(""),
+		entry:      newRenameEntry(),
+	}
+	item.label.Truncation = fyne.TextTruncateEllipsis
+	item.entry.Hide()
+
+	switch mode {
+	case core.ViewGrid, core.ViewIcons:
+		size, width := gridIconSize, gridItemWidth
+		if mode == core.ViewIcons {
+			size, width = largeIconSize, iconItemWidth
+		}
+		item.label.Alignment = fyne.TextAlignCenter
+		labelWidth := canvas.NewRectangle(color.Transparent)
+		labelWidth.SetMinSize(fyne.NewSize(width, 0))
+		item.content = container.NewVBox(
+			container.NewCenter(container.NewGridWrap(size, item.icon)),
+			container.NewMax(labelWidth, item.label, item.entry),
+		)
+	default:
+		item.content = container.NewBorder(nil, nil, item.icon, nil, container.NewMax(item.label, item.entry))
+	}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+// CreateRenderer возвращает рендерер ячейки с подсветкой выбора
+func (f *fileItem) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewMax(f.background, f.content))
+}
+
+// setSelected подсвечивает выбранную ячейку
+func (f *fileItem) setSelected(selected bool) {
+	if selected {
+		f.background.FillColor = theme.SelectionColor()
+	} else {
+		f.background.FillColor = color.Transparent
+	}
+	f.background.Refresh()
+}
+
+// MouseDown запоминает клавиши-модификаторы для следующего нажатия
+func (f *fileItem) MouseDown(ev *desktop.MouseEvent) {
+	f.modifier = ev.Modifier
+}
+
+// MouseUp нужен для интерфейса desktop.Mouseable
+func (f *fileItem) MouseUp(*desktop.MouseEvent) {
+}
+
+// Tapped вызывается при нажатии на ячейку
+func (f *fileItem) Tapped(*fyne.PointEvent) {
+	modifier := f.modifier
+	f.modifier = 0
+	if f.onTapped != nil {
+		f.onTapped(modifier)
+	}
+}
+
+// DoubleTapped вызывается при двойном нажатии на ячейку
+func (f *fileItem) DoubleTapped(*fyne.PointEvent) {
+	if f.onDoubleTapped != nil {
+		f.onDoubleTapped()
+	}
+}
+
+// TappedSecondary вызывается при нажатии правой кнопкой мыши
+func (f *fileItem) TappedSecondary(ev *fyne.PointEvent) {
+	if f.onSecondaryTap != nil {
+		f.onSecondaryTap(ev.AbsolutePosition)
+	}
+}
+
+// renameEntry - поле ввода нового имени файла. Enter сохраняет имя, Escape
+// отменяет переименование, потеря фокуса тоже сохраняет имя.
+type renameEntry struct {
+	widget.Entry
+	onCancel    func()
+	onFocusLost func()
+}
+
+// newRenameEntry создает поле ввода имени
+func newRenameEntry() *renameEntry {
+	entry := &renameEntry{}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+// TypedKey обрабатывает Escape, остальные клавиши передает полю ввода
+func (e *renameEntry) TypedKey(key *fyne.KeyEvent) {
+	if key.Name == fyne.KeyEscape && e.onCancel != nil {
+		e.onCancel()
+		return
+	}
+	e.Entry.TypedKey(key)
+}
+
+// FocusLost вызывается, когда поле ввода теряет фокус
+func (e *renameEntry) FocusLost() {
+	e.Entry.FocusLost()
+	if e.onFocusLost != nil {
+		e.onFocusLost()
+	}
+}
+
+// bindItem показывает в ячейке файл e и подключает к ней действия файлового
+// менеджера. В ячейке с именем (nameCell) при переименовании показывается поле ввода.
+func (fm *fileManager) bindItem(item *fileItem, e core.FileEntry, nameCell bool) {
+	name := e.Name
+	item.onTapped = func(modifier fyne.KeyModifier) {
+		fm.selectEntry(name, modifier)
+		fm.focus()
+	}
+	item.onDoubleTapped = func() { fm.ui.openEntry(name, e.IsDir) }
+	item.onSecondaryTap = func(pos fyne.Position) { fm.showItemMenu(e, pos) }
+	item.setSelected(fm.isSelected(name))
+
+	// Вырезанные файлы показываются бледнее до вставки
+	item.label.Importance = widget.MediumImportance
+	if fm.isCut(name) {
+		item.label.Importance = widget.LowImportance
+	}
+	fm.bindRename(item, name, nameCell && fm.isRenaming(name))
+}
+
+// bindRename показывает поле ввода имени, пока файл name переименовывается
+func (fm *fileManager) bindRename(item *fileItem, name string, renaming bool) {
+	if !renaming {
+		// Ячейку могли переиспользовать для другого файла: прежнее имя не сохраняем
+		item.entry.onFocusLost = nil
+		item.editing = ""
+		item.entry.Hide()
+		item.label.Show()
+		return
+	}
+
+	item.entry.OnSubmitted = func(text string) {
+		fm.finishRename(name, text)
+		fm.focus()
+	}
+	item.entry.onCancel = fm.cancelRename
+	item.entry.onFocusLost = func() {
+		// Фокус теряется внутри менеджера фокуса, поэтому сохраняем имя после
+		go fm.finishRename(name, item.entry.Text)
+	}
+	if item.editing != name {
+		item.editing = name
+		item.entry.SetText(name)
+		item.label.Hide()
+		item.entry.Show()
+		fm.ui.MainWindow.Canvas().Focus(item.entry)
+	}
+}
+
+// newTable создает таблицу файлов; нажатие на заголовок колонки сортирует по ней
+func (fm *fileManager) newTable() *widget.Table {
+	table := widget.NewTable(
+		func() (int, int) {
+			fm.mu.Lock()
+			defer fm.mu.Unlock()
+			return len(fm.shown), len(fileColumns)
+		},
+		func() fyne.CanvasObject {
+			return newFileItem(core.ViewList)
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			e, ok := fm.entry(id.Row)
+			if !ok {
+				return
+			}
+			item := obj.(*fileItem)
+			fm.bindItem(item, e, fileColumns[id.Col].SortBy == core.SortByName)
+
+			item.icon.Hide()
+			item.label.Alignment = fyne.TextAlignLeading
+			switch fileColumns[id.Col].SortBy {
+			case core.SortByName:
+				item.icon.SetResource(fileIcon(e))
+				item.icon.Show()
+				item.label.SetText(e.Name)
+			case core.SortBySize:
+				item.label.Alignment = fyne.TextAlignTrailing
+				if e.IsDir {
+					item.label.SetText("")
+				} else {
+					item.label.SetText(core.FormatSize(e.Size))
+				}
+			case core.SortByType:
+				item.label.SetText(fileTypeName(e))
+			case core.SortByModified:
+				item.label.SetText(e.ModTime.Format("02.01.2006 15:04"))
+			}
+			item.label.Refresh()
+		},
+	)
+
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		header := widget.NewButton("", nil)
+		header.Alignment = widget.ButtonAlignLeading
+		header.Importance = widget.LowImportance
+		return header
+	}
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		if id.Col < 0 || id.Col >= len(fileColumns) {
+			return
+		}
+		column := fileColumns[id.Col]
+		header := obj.(*widget.Button)
+
+		fm.mu.Lock()
+		view := fm.view
+		fm.mu.Unlock()
+		title := column.Title
+		if view.SortBy == column.SortBy {
+			if view.Descending {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		header.SetText(title)
+		header.OnTapped = func() { fm.sortBy(column.SortBy) }
+	}
+	for col, c := range fileColumns {
+		table.SetColumnWidth(col, c.Width)
+	}
+	return table
+}
+
+// newGrid создает сетку значков для режима ViewGrid или ViewIcons
+func (fm *fileManager) newGrid(mode string) *widget.GridWrap {
+	return widget.NewGridWrap(
+		func() int {
+			fm.mu.Lock()
+			defer fm.mu.Unlock()
+			return len(fm.shown)
+		},
+		func() fyne.CanvasObject {
+			return newFileItem(mode)
+		},
+		func(id widget.GridWrapItemID, obj fyne.CanvasObject) {
+			e, ok := fm.entry(id)
+			if !ok {
+				return
+			}
+			item := obj.(*fileItem)
+			fm.bindItem(item, e, true)
+			item.icon.SetResource(fileIcon(e))
+			item.label.SetText(e.Name)
+		},
+	)
+}
+
+// newViewSelect создает выбор режима отображения папки
+func (fm *fileManager) newViewSelect() *widget.Select {
+	titles := make([]string, len(fileViewModes))
+	for i, m := range fileViewModes {
+		titles[i] = m.Title
+	}
+	return widget.NewSelect(titles, func(selected string) {
+		for _, m := range fileViewModes {
+			if m.Title == selected {
+				mode := m.Mode
+				fm.changeView(func(v *core.FolderView) { v.Mode = mode })
+			}
+		}
+	})
+}
+
+// viewModeTitle возвращает название режима отображения
+func viewModeTitle(mode string) string {
+	for _, m := range fileViewModes {
+		if m.Mode == mode {
+			return m.Title
+		}
+	}
+	return fileViewModes[0].Title
+}
@@ -0,0 +1,359 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+const (
+	// viewerZoomStep - во сколько раз изменяется масштаб при одном шаге
+	viewerZoomStep = 1.25
+	// viewerMinZoom и viewerMaxZoom - границы масштаба
+	viewerMinZoom = 0.05
+	viewerMaxZoom = 20
+	// slideshowInterval - время показа одного изображения в режиме слайд-шоу
+	slideshowInterval = 3 * time.Second
+)
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "viewer", Name: "Просмотр изображений", Category: "Стандартные", MIMETypes: core.ImageMIMETypes, Order: 8},
+		icon:   theme.MediaPhotoIcon,
+		launch: (*MixailOSUI).createImageViewerTab,
+		open: func(ui *MixailOSUI, path string) error {
+			return ui.ImageViewer.open(path)
+		},
+		stop: func(ui *MixailOSUI) { ui.ImageViewer.pauseSlideshow() },
+	})
+}
+
+// imageViewer - просмотр изображений с масштабированием, поворотом,
+// переходом по папке и слайд-шоу
+type imageViewer struct {
+	ui *MixailOSUI
+
+	// mu защищает состояние просмотра: его меняют и кнопки, и горутина слайд-шоу
+	mu sync.Mutex
+
+	// original - изображение в исходной ориентации, turns - поворот в четвертях оборота
+	original image.Image
+	info     core.ImageInfo
+	turns    int
+	// rotated - original с учетом поворота; пересчитывается только при повороте
+	rotated image.Image
+	// zoom - масштаб; 0 означает «вписать в окно»
+	zoom float32
+
+	image     *canvas.Image
+	view      *fyne.Container
+	infoLabel *widget.Label
+	zoomLabel *widget.Label
+
+	// stopSlideshow останавливает слайд-шоу; nil, если оно не запущено
+	stopSlideshow chan struct{}
+	slideButton   *widget.Button
+}
+
+// createImageViewerTab создает вкладку просмотра изображений
+func (ui *MixailOSUI) createImageViewerTab() fyne.CanvasObject {
+	v := &imageViewer{ui: ui}
+	ui.ImageViewer = v
+
+	v.image = canvas.NewImageFromImage(nil)
+	v.view = container.NewMax(widget.NewLabelWithStyle("Откройте изображение кнопкой «Открыть» или из файлового менеджера",
+		fyne.TextAlignCenter, fyne.TextStyle{Italic: true}))
+	v.infoLabel = widget.NewLabel("")
+	v.infoLabel.Wrapping = fyne.TextWrapWord
+	v.zoomLabel = widget.NewLabel("Вписать")
+	v.slideButton = widget.NewButtonWithIcon("", theme.MediaPlayIcon(), v.toggleSlideshow)
+
+	toolbar := container.NewHBox(
+		widget.NewButtonWithIcon("Открыть", theme.FolderOpenIcon(), v.showOpenDialog),
+		widget.NewSeparator(),
+		widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { v.navigate(-1) }),
+		widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() { v.navigate(1) }),
+		v.slideButton,
+		widget.NewSeparator(),
+		widget.NewButtonWithIcon("", theme.ZoomOutIcon(), func() { v.zoomBy(1 / viewerZoomStep) }),
+		v.zoomLabel,
+		widget.NewButtonWithIcon("", theme.ZoomInIcon(), func() { v.zoomBy(viewerZoomStep) }),
+		widget.NewButtonWithIcon("", theme.ZoomFitIcon(), v.fit),
+		widget.NewButton("100%", func() { v.setZoom(1) }),
+		widget.NewSeparator(),
+		widget.NewButton("⟲", func() { v.rotate(-1) }),
+		widget.NewButton("⟳", func() { v.rotate(1) }),
+		widget.NewSeparator(),
+		widget.NewButtonWithIcon("Сделать обоями", theme.ComputerIcon(), v.setWallpaper),
+	)
+
+	return container.NewBorder(
+		container.NewHScroll(toolbar), // top
+		v.infoLabel,                   // bottom
+		nil,                           // left
+		nil,                           // right
+		v.view,
+	)
+}
+
+// open показывает изображение по пути внутри MixailOS
+func (v *imageViewer) open(path string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.load(path)
+}
+
+// load загружает и показывает изображение; вызывается при захваченном mu
+func (v *imageViewer) load(path string) error {
+	img, info, err := v.ui.FileSystem.LoadImage(path)
+	if err != nil {
+		return err
+	}
+	v.original, v.rotated, v.info = img, img, info
+	v.turns = 0
+	v.zoom = 0
+	v.redraw()
+	return nil
+}
+
+// showOpenDialog запрашивает путь к изображению
+func (v *imageViewer) showOpenDialog() {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("/Pictures/фото.jpg")
+
+	dialog.ShowForm("Открыть изображение", "Открыть", "Отмена",
+		[]*widget.FormItem{
+			widget.NewFormItem("Файл:", nameEntry),
+		},
+		func(confirm bool) {
+			if !confirm || nameEntry.Text == "" {
+				return
+			}
+			if err := v.open(nameEntry.Text); err != nil {
+				dialog.ShowError(err, v.ui.MainWindow)
+			}
+		},
+		v.ui.MainWindow,
+	)
+}
+
+// redraw показывает текущее изображение с учетом поворота и масштаба;
+// вызывается при захваченном mu
+func (v *imageViewer) redraw() {
+	if v.original == nil {
+		return
+	}
+	img := v.rotated
+	v.image.Image = img
+
+	if v.zoom == 0 {
+		v.image.FillMode = canvas.ImageFillContain
+		v.image.SetMinSize(fyne.NewSize(0, 0))
+		v.view.Objects = []fyne.CanvasObject{v.image}
+		v.zoomLabel.SetText("Вписать")
+	} else {
+		b := img.Bounds()
+		v.image.FillMode = canvas.ImageFillStretch
+		v.image.SetMinSize(fyne.NewSize(float32(b.Dx())*v.zoom, float32(b.Dy())*v.zoom))
+		v.view.Objects = []fyne.CanvasObject{container.NewScroll(container.NewCenter(v.image))}
+		v.zoomLabel.SetText(fmt.Sprintf("%.0f%%", v.zoom*100))
+	}
+	v.view.Refresh()
+	v.image.Refresh()
+	v.infoLabel.SetText(v.info.String())
+}
+
+// fitScale возвращает масштаб, при котором изображение вписывается в окно
+func (v *imageViewer) fitScale() float32 {
+	b := v.original.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if v.turns%2 != 0 {
+		width, height = height, width
+	}
+	size := v.view.Size()
+	if width == 0 || height == 0 || size.Width == 0 || size.Height == 0 {
+		return 1
+	}
+	scale := size.Width / float32(width)
+	if s := size.Height / float32(height); s < scale {
+		scale = s
+	}
+	return scale
+}
+
+// zoomBy изменяет масштаб в factor раз
+func (v *imageViewer) zoomBy(factor float32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.original == nil {
+		return
+	}
+	zoom := v.zoom
+	if zoom == 0 {
+		zoom = v.fitScale()
+	}
+	v.applyZoom(zoom * factor)
+}
+
+// setZoom устанавливает масштаб
+func (v *imageViewer) setZoom(zoom float32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.applyZoom(zoom)
+}
+
+// applyZoom устанавливает масштаб в пределах viewerMinZoom..viewerMaxZoom;
+// вызывается при захваченном mu
+func (v *imageViewer) applyZoom(zoom float32) {
+	if v.original == nil {
+		return
+	}
+	if zoom < viewerMinZoom {
+		zoom = viewerMinZoom
+	}
+	if zoom > viewerMaxZoom {
+		zoom = viewerMaxZoom
+	}
+	v.zoom = zoom
+	v.redraw()
+}
+
+// fit вписывает изображение в окно
+func (v *imageViewer) fit() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.zoom = 0
+	v.redraw()
+}
+
+// rotate поворачивает изображение на четверть оборота: 1 - по часовой стрелке, -1 - против
+func (v *imageViewer) rotate(direction int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.original == nil {
+		return
+	}
+	v.turns = (v.turns + direction + 4) % 4
+	v.rotated = core.RotateImage(v.original, v.turns)
+	v.redraw()
+}
+
+// navigate переходит к соседнему изображению по кнопке и показывает ошибку
+func (v *imageViewer) navigate(delta int) {
+	if err := v.step(delta); err != nil {
+		dialog.ShowError(err, v.ui.MainWindow)
+	}
+}
+
+// step переходит к следующему (1) или предыдущему (-1) изображению в папке
+func (v *imageViewer) step(delta int) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.original == nil {
+		return nil
+	}
+	images, err := v.ui.FileSystem.ImagesInFolder(v.info.Path)
+	if err != nil || len(images) == 0 {
+		return err
+	}
+	index := 0
+	for i, path := range images {
+		if path == v.info.Path {
+			index = i
+			break
+		}
+	}
+	next := images[(index+delta+len(images))%len(images)]
+	return v.load(next)
+}
+
+// toggleSlideshow запускает или останавливает слайд-шоу по изображениям папки
+func (v *imageViewer) toggleSlideshow() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.stopSlideshow != nil {
+		v.closeSlideshow()
+		return
+	}
+	if v.original == nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	v.stopSlideshow = stop
+	v.slideButton.SetIcon(theme.MediaPauseIcon())
+	go func() {
+		ticker := time.NewTicker(slideshowInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-v.ui.done:
+				return
+			case <-ticker.C:
+				if err := v.step(1); err != nil {
+					// Слайд-шоу останавливается, чтобы ошибка не повторялась каждый шаг
+					v.endSlideshow(stop)
+					dialog.ShowError(err, v.ui.MainWindow)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// pauseSlideshow останавливает слайд-шоу, если оно запущено
+func (v *imageViewer) pauseSlideshow() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.stopSlideshow != nil {
+		v.closeSlideshow()
+	}
+}
+
+// closeSlideshow останавливает запущенное слайд-шоу. Вызывается под блокировкой.
+func (v *imageViewer) closeSlideshow() {
+	close(v.stopSlideshow)
+	v.stopSlideshow = nil
+	v.slideButton.SetIcon(theme.MediaPlayIcon())
+}
+
+// endSlideshow останавливает слайд-шоу stop, если оно еще не остановлено кнопкой
+func (v *imageViewer) endSlideshow(stop chan struct{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.stopSlideshow == stop {
+		v.stopSlideshow = nil
+		v.slideButton.SetIcon(theme.MediaPlayIcon())
+	}
+}
+
+// setWallpaper копирует текущее изображение в системную папку обоев
+// и делает его обоями рабочего стола
+func (v *imageViewer) setWallpaper() {
+	v.mu.Lock()
+	source := v.info.Path
+	loaded := v.original != nil
+	v.mu.Unlock()
+	if !loaded {
+		return
+	}
+	path, err := v.ui.FileSystem.ImportWallpaper(source)
+	if err != nil {
+		dialog.ShowError(err, v.ui.MainWindow)
+		return
+	}
+	v.ui.setWallpaper(path)
+	v.ui.notify("Обои", "Изображение "+source+" установлено обоями рабочего стола", core.SeveritySuccess)
+}
@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+const (
+	// toastWidth - ширина всплывающего уведомления
+	toastWidth = 320
+	// toastTimeout - время показа уведомления; ошибки показываются дольше
+	toastTimeout      = 5 * time.Second
+	errorToastTimeout = 10 * time.Second
+	// maxToasts - сколько уведомлений показывается одновременно
+	maxToasts = 4
+)
+
+// notificationCenterSize - размер панели истории уведомлений
+var notificationCenterSize = fyne.NewSize(360, 440)
+
+// severityIcon возвращает значок уровня важности
+func severityIcon(s core.Severity) fyne.Resource {
+	switch s {
+	case core.SeveritySuccess:
+		return theme.ConfirmIcon()
+	case core.SeverityWarning:
+		return theme.WarningIcon()
+	case core.SeverityError:
+		return theme.ErrorIcon()
+	}
+	return theme.InfoIcon()
+}
+
+// severityColor возвращает цвет рамки уведомления
+func severityColor(s core.Severity) color.Color {
+	switch s {
+	case core.SeveritySuccess:
+		return theme.SuccessColor()
+	case core.SeverityWarning:
+		return theme.WarningColor()
+	case core.SeverityError:
+		return theme.ErrorColor()
+	}
+	return theme.PrimaryColor()
+}
+
+// notifier показывает уведомления: всплывающие сообщения в правом нижнем углу
+// рабочей области и панель истории, открываемую кнопкой на панели задач
+type notifier struct {
+	ui *MixailOSUI
+	// layer - прозрачный слой поверх рабочей области со всплывающими уведомлениями
+	layer  fyne.CanvasObject
+	button *widget.Button
+	panel  *widget.PopUp
+
+	mu     sync.Mutex
+	toasts *fyne.Container
+}
+
+// newNotifier создает слой уведомлений и подписывается на центр уведомлений
+func (ui *MixailOSUI) newNotifier() *notifier {
+	n := &notifier{ui: ui}
+	n.toasts = container.NewVBox()
+	n.layer = container.NewBorder(nil, nil, nil, container.NewVBox(layout.NewSpacer(), n.toasts))
+	n.button = widget.NewButtonWithIcon("", theme.InfoIcon(), n.showCenter)
+	n.button.Importance = widget.LowImportance
+
+	ui.Console.Notifications.Subscribe(func(note core.Notification, popup bool) {
+		n.refreshButton()
+		if popup {
+			n.showToast(note)
+		}
+	})
+	return n
+}
+
+// notify публикует уведомление без действий
+func (ui *MixailOSUI) notify(title, body string, severity core.Severity) {
+	ui.Console.Notifications.Notify(title, body, severity)
+}
+
+// refreshButton показывает на кнопке количество непрочитанных уведомлений
+func (n *notifier) refreshButton() {
+	if unread := n.ui.Console.Notifications.Unread(); unread > 0 {
+		n.button.SetText(fmt.Sprint(unread))
+		n.button.Importance = widget.HighImportance
+	} else {
+		n.button.SetText("")
+		n.button.Importance = widget.LowImportance
+	}
+	n.button.Refresh()
+}
+
+// newCard создает карточку уведомления. onClose вызывается кнопкой закрытия,
+// а также после нажатия на кнопку действия.
+func (n *notifier) newCard(note core.Notification, onClose func()) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(note.Title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	title.Wrapping = fyne.TextWrapWord
+	text := container.NewVBox(title)
+	if note.Body != "" {
+		body := widget.NewLabel(note.Body)
+		body.Wrapping = fyne.TextWrapWord
+		text.Add(body)
+	}
+	text.Add(widget.NewLabelWithStyle(note.Time.Format("15:04:05"), fyne.TextAlignTrailing, fyne.TextStyle{Italic: true}))
+
+	actions := container.NewHBox()
+	for _, a := range note.Actions {
+		action := a
+		actions.Add(widget.NewButton(action.Label, func() {
+			onClose()
+			if action.Run != nil {
+				action.Run()
+			}
+		}))
+	}
+
+	closeButton := widget.NewButtonWithIcon("", theme.CancelIcon(), onClose)
+	closeButton.Importance = widget.LowImportance
+
+	background := canvas.NewRectangle(theme.OverlayBackgroundColor())
+	background.StrokeColor = severityColor(note.Severity)
+	background.StrokeWidth = 2
+	width := canvas.NewRectangle(color.Transparent)
+	width.SetMinSize(fyne.NewSize(toastWidth, 0))
+
+	return container.NewMax(
+		width,
+		background,
+		container.NewPadded(container.NewBorder(
+			nil,     // top
+			actions, // bottom
+			container.NewVBox(widget.NewIcon(severityIcon(note.Severity))), // left
+			container.NewVBox(closeButton),                                 // right
+			text,
+		)),
+	)
+}
+
+// showToast показывает всплывающее уведомление и скрывает его по таймеру
+func (n *notifier) showToast(note core.Notification) {
+	var card fyne.CanvasObject
+	remove := func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.toasts.Remove(card)
+	}
+	card = n.newCard(note, remove)
+
+	n.mu.Lock()
+	n.toasts.Add(card)
+	if len(n.toasts.Objects) > maxToasts {
+		n.toasts.Remove(n.toasts.Objects[0])
+	}
+	n.mu.Unlock()
+
+	timeout := toastTimeout
+	if note.Severity == core.SeverityError {
+		timeout = errorToastTimeout
+	}
+	time.AfterFunc(timeout, remove)
+}
+
+// showCenter открывает панель истории уведомлений над панелью задач
+func (n *notifier) showCenter() {
+	ui := n.ui
+	nc := ui.Console.Notifications
+	if n.panel != nil {
+		n.panel.Hide()
+	}
+
+	list := container.NewVBox()
+	var fill func()
+	fill = func() {
+		list.Objects = nil
+		for _, note := range nc.History() {
+			id := note.ID
+			list.Add(n.newCard(note, func() {
+				nc.Remove(id)
+				fill()
+			}))
+		}
+		if len(list.Objects) == 0 {
+			list.Add(widget.NewLabel("Уведомлений нет"))
+		}
+		list.Refresh()
+		n.refreshButton()
+	}
+	nc.MarkAllRead()
+	fill()
+
+	dnd := widget.NewCheck("Не беспокоить", nil)
+	dnd.SetChecked(nc.DoNotDisturb())
+	dnd.OnChanged = func(enabled bool) {
+		if err := nc.SetDoNotDisturb(enabled); err != nil {
+			ui.notify("Не удалось сохранить настройки", err.Error(), core.SeverityError)
+		}
+	}
+	clearButton := widget.NewButtonWithIcon("Очистить", theme.DeleteIcon(), func() {
+		nc.Clear()
+		fill()
+	})
+
+	header := container.NewBorder(nil, nil,
+		widget.NewLabelWithStyle("Уведомления", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewHBox(dnd, clearButton),
+	)
+	n.panel = widget.NewPopUp(container.NewBorder(
+		header, // top
+		nil,    // bottom
+		nil,    // left
+		nil,    // right
+		container.NewVScroll(list),
+	), ui.MainWindow.Canvas())
+	n.panel.Resize(notificationCenterSize)
+
+	// Панель открывается в правом нижнем углу над панелью задач
+	canvasSize := ui.MainWindow.Canvas().Size()
+	x := canvasSize.Width - notificationCenterSize.Width
+	y := canvasSize.Height - ui.Taskbar.content.Size().Height - notificationCenterSize.Height
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	n.panel.ShowAtPosition(fyne.NewPos(x, y))
+}
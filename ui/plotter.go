@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+const (
+	// plotZoomStep - во сколько раз изменяется область при одном шаге масштабирования
+	plotZoomStep = 1.25
+	// plotExportWidth и plotExportHeight - размер графика, сохраняемого в PNG
+	plotExportWidth  = 1200
+	plotExportHeight = 900
+)
+
+// plotCanvas - область рисования графика. Перетаскивание мышью сдвигает
+// график, колесо мыши масштабирует его относительно указателя.
+type plotCanvas struct {
+	widget.BaseWidget
+	plot     *core.Plot
+	raster   *canvas.Raster
+	onChange func()
+}
+
+// newPlotCanvas создает область рисования графика
+func newPlotCanvas(plot *core.Plot) *plotCanvas {
+	pc := &plotCanvas{plot: plot}
+	pc.raster = canvas.NewRaster(func(w, h int) image.Image {
+		return plot.Render(w, h)
+	})
+	pc.ExtendBaseWidget(pc)
+	return pc
+}
+
+// CreateRenderer возвращает рендерер, рисующий растр графика
+func (pc *plotCanvas) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(pc.raster)
+}
+
+// MinSize возвращает минимальный размер области графика
+func (pc *plotCanvas) MinSize() fyne.Size {
+	return fyne.NewSize(300, 200)
+}
+
+// Dragged сдвигает график вслед за указателем
+func (pc *plotCanvas) Dragged(ev *fyne.DragEvent) {
+	size := pc.Size()
+	if size.Width == 0 || size.Height == 0 {
+		return
+	}
+	pc.plot.Pan(float64(ev.Dragged.DX/size.Width), float64(ev.Dragged.DY/size.Height))
+	pc.redraw()
+}
+
+// DragEnd завершает перетаскивание
+func (pc *plotCanvas) DragEnd() {}
+
+// Scrolled масштабирует график относительно указателя
+func (pc *plotCanvas) Scrolled(ev *fyne.ScrollEvent) {
+	size := pc.Size()
+	if size.Width == 0 || size.Height == 0 {
+		return
+	}
+	factor := plotZoomStep
+	if ev.Scrolled.DY > 0 {
+		factor = 1 / plotZoomStep
+	}
+	pc.plot.Zoom(factor, float64(ev.Position.X/size.Width), float64(ev.Position.Y/size.Height))
+	pc.redraw()
+}
+
+// redraw перерисовывает график и сообщает об изменении области
+func (pc *plotCanvas) redraw() {
+	pc.raster.Refresh()
+	if pc.onChange != nil {
+		pc.onChange()
+	}
+}
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "plotter", Name: "Графики", Category: "Стандартные", Order: 7},
+		icon:   theme.VisibilityIcon,
+		launch: (*MixailOSUI).createPlotterTab,
+	})
+}
+
+// createPlotterTab создает вкладку построения графиков функций
+func (ui *MixailOSUI) createPlotterTab() fyne.CanvasObject {
+	plot := core.NewPlot()
+	calc := ui.Console.Calculator
+	// Переменные калькулятора доступны в выражениях графиков
+	plot.Vars = calc.Vars
+
+	area := newPlotCanvas(plot)
+	rangeLabel := widget.NewLabel("")
+	area.onChange = func() {
+		rangeLabel.SetText(fmt.Sprintf("x: %s … %s   y: %s … %s",
+			core.FormatNumber(plot.XMin), core.FormatNumber(plot.XMax),
+			core.FormatNumber(plot.YMin), core.FormatNumber(plot.YMax)))
+	}
+	area.onChange()
+
+	// Список функций с цветом и кнопкой удаления
+	functions := container.NewVBox()
+	var refreshFunctions func()
+	refreshFunctions = func() {
+		functions.Objects = nil
+		for i, f := range plot.Functions {
+			index := i
+			swatch := canvas.NewRectangle(f.Color)
+			swatch.SetMinSize(fyne.NewSize(16, 16))
+			remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				plot.RemoveFunction(index)
+				refreshFunctions()
+				area.redraw()
+			})
+			functions.Add(container.NewBorder(nil, nil, container.NewCenter(swatch), remove, widget.NewLabel(f.Source)))
+		}
+		functions.Refresh()
+	}
+
+	// Ввод новой функции
+	input := widget.NewEntry()
+	input.SetPlaceHolder("y = sin(x)")
+	status := widget.NewLabel("")
+	add := func() {
+		plot.Degrees = calc.Degrees
+		if _, err := plot.AddFunction(input.Text); err != nil {
+			status.SetText("Ошибка: " + err.Error())
+			if ce, ok := err.(*core.CalcError); ok {
+				input.CursorColumn = ce.Pos
+				input.Refresh()
+			}
+			return
+		}
+		status.SetText("")
+		input.SetText("")
+		refreshFunctions()
+		area.redraw()
+	}
+	input.OnSubmitted = func(string) { add() }
+
+	// Масштаб, сброс области и экспорт
+	toolbar := container.NewHBox(
+		widget.NewButtonWithIcon("", theme.ZoomInIcon(), func() {
+			plot.Zoom(1/plotZoomStep, 0.5, 0.5)
+			area.redraw()
+		}),
+		widget.NewButtonWithIcon("", theme.ZoomOutIcon(), func() {
+			plot.Zoom(plotZoomStep, 0.5, 0.5)
+			area.redraw()
+		}),
+		widget.NewButtonWithIcon("", theme.ZoomFitIcon(), func() {
+			plot.Reset()
+			area.redraw()
+		}),
+		widget.NewButtonWithIcon("Сохранить PNG", theme.DocumentSaveIcon(), func() {
+			path, err := plot.SavePNG(ui.FileSystem, plotExportWidth, plotExportHeight)
+			if err != nil {
+				dialog.ShowError(err, ui.MainWindow)
+				return
+			}
+			status.SetText("График сохранен: " + path)
+		}),
+		rangeLabel,
+	)
+
+	return container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, widget.NewLabel("Функция:"), widget.NewButtonWithIcon("Добавить", theme.ContentAddIcon(), add), input),
+			status,
+			functions,
+			toolbar,
+		), // top
+		nil, // bottom
+		nil, // left
+		nil, // right
+		area,
+	)
+}
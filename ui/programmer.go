@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// programmerBases - системы счисления режима программиста
+var programmerBases = []struct {
+	name string
+	base int
+}{
+	{"HEX", 16},
+	{"DEC", 10},
+	{"OCT", 8},
+	{"BIN", 2},
+}
+
+// programmerPanel - панель режима программиста: системы счисления, размер слова и сетка битов
+type programmerPanel struct {
+	calc *calculator
+
+	content    *fyne.Container
+	baseLabels map[int]*widget.Label
+	bits       []*widget.Button
+	bitRows    []*fyne.Container
+}
+
+// newProgrammerPanel создает панель режима программиста
+func (calc *calculator) newProgrammerPanel() *programmerPanel {
+	pp := &programmerPanel{
+		calc:       calc,
+		baseLabels: map[int]*widget.Label{},
+	}
+	prog := calc.prog
+
+	// Выбор системы счисления: введенное значение переводится в новую систему
+	var baseNames []string
+	for _, b := range programmerBases {
+		baseNames = append(baseNames, b.name)
+	}
+	baseSelect := widget.NewSelect(baseNames, func(selected string) {
+		for _, b := range programmerBases {
+			if b.name == selected && b.base != prog.Base {
+				prog.SetBase(b.base)
+				calc.input.SetText(prog.Format(prog.Ans, b.base))
+			}
+		}
+	})
+
+	// Размер слова и знаковость
+	var sizeNames []string
+	for _, size := range core.WordSizes {
+		sizeNames = append(sizeNames, strconv.Itoa(size)+" бит")
+	}
+	sizeSelect := widget.NewSelect(sizeNames, func(selected string) {
+		for i, name := range sizeNames {
+			if name == selected {
+				prog.SetBits(core.WordSizes[i])
+				pp.refresh()
+			}
+		}
+	})
+	signedCheck := widget.NewCheck("Со знаком", func(checked bool) {
+		prog.Signed = checked
+		pp.refresh()
+	})
+
+	for _, b := range programmerBases {
+		if b.base == prog.Base {
+			baseSelect.SetSelected(b.name)
+		}
+	}
+	sizeSelect.SetSelected(strconv.Itoa(prog.Bits) + " бит")
+	signedCheck.SetChecked(prog.Signed)
+
+	// Значение последнего результата во всех системах счисления
+	values := container.NewVBox()
+	for _, b := range programmerBases {
+		label := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+		pp.baseLabels[b.base] = label
+		values.Add(container.NewBorder(nil, nil, widget.NewLabel(b.name), nil, label))
+	}
+
+	// Сетка битов: по 16 битов в строке, старшие биты сверху. Нажатие инвертирует бит.
+	grid := container.NewVBox()
+	pp.bits = make([]*widget.Button, 64)
+	for row := 3; row >= 0; row-- {
+		cells := []fyne.CanvasObject{widget.NewLabelWithStyle(strconv.Itoa(row*16+15), fyne.TextAlignTrailing, fyne.TextStyle{Monospace: true})}
+		for col := 15; col >= 0; col-- {
+			bit := row*16 + col
+			pp.bits[bit] = widget.NewButton("0", func() {
+				prog.Ans = prog.ToggleBit(prog.Ans, bit)
+				calc.input.SetText(prog.Format(prog.Ans, prog.Base))
+				pp.refresh()
+			})
+			cells = append(cells, pp.bits[bit])
+		}
+		rowContainer := container.NewGridWithColumns(17, cells...)
+		pp.bitRows = append([]*fyne.Container{rowContainer}, pp.bitRows...)
+		grid.Add(rowContainer)
+	}
+
+	// Шестнадцатеричные цифры и битовые операции
+	button := func(label, text string) *widget.Button {
+		return widget.NewButton(label, func() { calc.insert(text) })
+	}
+	keypad := container.NewGridWithColumns(7,
+		button("A", "A"), button("B", "B"), button("C", "C"), button("D", "D"), button("E", "E"), button("F", "F"), button("ans", "ans"),
+		button("AND", " & "), button("OR", " | "), button("XOR", " xor "), button("NOT", "~"), button("<<", " << "), button(">>", " >> "), button("MOD", " mod "),
+	)
+
+	pp.content = container.NewVBox(
+		container.NewHBox(baseSelect, sizeSelect, signedCheck),
+		values,
+		grid,
+		keypad,
+	)
+	pp.refresh()
+	return pp
+}
+
+// refresh показывает последний результат во всех системах счисления и в сетке битов
+func (pp *programmerPanel) refresh() {
+	prog := pp.calc.prog
+	v := prog.Ans
+
+	for base, label := range pp.baseLabels {
+		if base == 2 {
+			label.SetText(prog.FormatBits(v))
+		} else {
+			label.SetText(prog.Format(v, base))
+		}
+	}
+
+	for bit, button := range pp.bits {
+		if v>>uint(bit)&1 == 1 {
+			button.SetText("1")
+			button.Importance = widget.HighImportance
+		} else {
+			button.SetText("0")
+			button.Importance = widget.MediumImportance
+		}
+		if bit < prog.Bits {
+			button.Enable()
+		} else {
+			button.Disable()
+		}
+		button.Refresh()
+	}
+
+	// Строки старше размера слова скрываются
+	for row, rowContainer := range pp.bitRows {
+		if row*16 < prog.Bits {
+			rowContainer.Show()
+		} else {
+			rowContainer.Hide()
+		}
+	}
+}
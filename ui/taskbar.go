@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// startMenuSize - размер меню «Пуск»
+var startMenuSize = fyne.NewSize(320, 440)
+
+// appCategories - категории приложений в меню «Пуск» в порядке отображения
+var appCategories = []string{"Стандартные", "Интернет", "Система"}
+
+// taskbar - панель задач: кнопка «Пуск», закрепленные приложения,
+// открытые окна и часы
+type taskbar struct {
+	ui      *MixailOSUI
+	content *fyne.Container
+	pinned  *fyne.Container
+	windows *fyne.Container
+	clock   *widget.Label
+	menu    *widget.PopUp
+}
+
+// newTaskbar создает панель задач и запускает часы
+func (ui *MixailOSUI) newTaskbar() *taskbar {
+	tb := &taskbar{ui: ui}
+
+	start := widget.NewButtonWithIcon("Пуск", theme.MenuIcon(), tb.showStartMenu)
+	start.Importance = widget.HighImportance
+	tb.pinned = container.NewHBox()
+	tb.windows = container.NewHBox()
+	tb.clock = widget.NewLabel("")
+	tb.refreshPinned()
+	// Закрепленные приложения хранятся в настройках
+	ui.Config.Events.Subscribe(func(core.Event) { tb.refreshPinned() }, core.EventConfigChanged)
+
+	tb.content = container.NewBorder(
+		nil, // top
+		nil, // bottom
+		container.NewHBox(start, tb.pinned, widget.NewSeparator()), // left
+		container.NewHBox(ui.Notifier.button, tb.clock), // right
+		container.NewHScroll(tb.windows),
+	)
+
+	// Ctrl+T открывает консоль
+	ui.MainWindow.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyT, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		ui.showApp("console")
+	})
+
+	go tb.runClock()
+	return tb
+}
+
+// runClock обновляет часы раз в секунду; горутина завершается при закрытии окна
+func (tb *taskbar) runClock() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		tb.clock.SetText(time.Now().Format("15:04  02.01.2006"))
+		select {
+		case <-tb.ui.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshPinned обновляет кнопки закрепленных приложений
+func (tb *taskbar) refreshPinned() {
+	tb.pinned.Objects = nil
+	for _, id := range tb.ui.Config.GetPinnedApps() {
+		app := tb.ui.findApp(id)
+		if app == nil {
+			continue
+		}
+		id := app.ID
+		tb.pinned.Add(widget.NewButtonWithIcon("", app.Item.Icon, func() { tb.ui.startApp(id) }))
+	}
+	tb.pinned.Refresh()
+}
+
+// setWindows показывает кнопки открытых окон
+func (tb *taskbar) setWindows(buttons []fyne.CanvasObject) {
+	tb.windows.Objects = buttons
+	tb.windows.Refresh()
+}
+
+// showStartMenu открывает меню «Пуск» над панелью задач
+func (tb *taskbar) showStartMenu() {
+	ui := tb.ui
+	if tb.menu != nil {
+		tb.menu.Hide()
+	}
+
+	list := container.NewVBox()
+	search := widget.NewEntry()
+	search.SetPlaceHolder("Поиск приложений...")
+
+	launch := func(app *launcherApp) {
+		tb.menu.Hide()
+		ui.startApp(app.ID)
+	}
+
+	// matches возвращает приложения, подходящие под поисковый запрос
+	matches := func(query string) []*launcherApp {
+		query = strings.ToLower(strings.TrimSpace(query))
+		var found []*launcherApp
+		for _, app := range ui.Apps {
+			if query == "" || strings.Contains(strings.ToLower(app.Name), query) || strings.Contains(strings.ToLower(app.Category), query) {
+				found = append(found, app)
+			}
+		}
+		return found
+	}
+
+	var fill func(query string)
+	row := func(app *launcherApp) fyne.CanvasObject {
+		pinIcon, pinAction := theme.ContentAddIcon(), ui.Config.PinApp
+		if ui.Config.IsPinned(app.ID) {
+			pinIcon, pinAction = theme.ContentRemoveIcon(), ui.Config.UnpinApp
+		}
+		pin := widget.NewButtonWithIcon("", pinIcon, func() {
+			if err := pinAction(app.ID); err != nil {
+				dialog.ShowError(err, ui.MainWindow)
+			}
+			fill(search.Text)
+		})
+		pin.Importance = widget.LowImportance
+		open := widget.NewButtonWithIcon(app.Item.Text, app.Item.Icon, func() { launch(app) })
+		open.Alignment = widget.ButtonAlignLeading
+		open.Importance = widget.LowImportance
+		return container.NewBorder(nil, nil, nil, pin, open)
+	}
+
+	fill = func(query string) {
+		list.Objects = nil
+		found := matches(query)
+		if query == "" {
+			// Закрепленные приложения показываются первыми
+			var favorites []fyne.CanvasObject
+			for _, id := range ui.Config.GetPinnedApps() {
+				if app := ui.findApp(id); app != nil {
+					favorites = append(favorites, row(app))
+				}
+			}
+			if len(favorites) > 0 {
+				list.Add(widget.NewLabelWithStyle("Избранное", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+				list.Objects = append(list.Objects, favorites...)
+			}
+		}
+		for _, category := range appCategories {
+			header := false
+			for _, app := range found {
+				if app.Category != category {
+					continue
+				}
+				if !header {
+					list.Add(widget.NewLabelWithStyle(category, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+					header = true
+				}
+				list.Add(row(app))
+			}
+		}
+		if len(found) == 0 {
+			list.Add(widget.NewLabel("Ничего не найдено"))
+		}
+		list.Refresh()
+	}
+	search.OnChanged = fill
+	search.OnSubmitted = func(query string) {
+		if found := matches(query); len(found) > 0 {
+			launch(found[0])
+		}
+	}
+	fill("")
+
+	header := widget.NewLabelWithStyle("Пользователь: "+ui.Config.GetUsername(), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	tb.menu = widget.NewPopUp(container.NewBorder(
+		container.NewVBox(header, search), // top
+		nil,                               // bottom
+		nil,                               // left
+		nil,                               // right
+		container.NewVScroll(list),
+	), ui.MainWindow.Canvas())
+	tb.menu.Resize(startMenuSize)
+
+	// Меню открывается в левом нижнем углу над панелью задач
+	canvasSize := ui.MainWindow.Canvas().Size()
+	y := canvasSize.Height - tb.content.Size().Height - startMenuSize.Height
+	if y < 0 {
+		y = 0
+	}
+	tb.menu.ShowAtPosition(fyne.NewPos(0, y))
+	ui.MainWindow.Canvas().Focus(search)
+}
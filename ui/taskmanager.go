@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/AleonDM/MixailOS/core"
+)
+
+// taskManagerColumns - заголовки столбцов таблицы процессов
+var taskManagerColumns = []string{"PID", "Имя", "Тип", "Владелец", "Запущен", "Состояние"}
+
+// registerAppProcess регистрирует вкладку приложения в таблице процессов.
+// Завершение процесса закрывает вкладку.
+func (ui *MixailOSUI) registerAppProcess(item *container.TabItem) {
+	pid := ui.Console.Processes.Start(item.Text, core.ProcessApp, ui.Config.GetUsername(), func() {
+		ui.appProcessesMu.Lock()
+		delete(ui.appProcesses, item)
+		ui.appProcessesMu.Unlock()
+		ui.MainTabs.Remove(item)
+		ui.stopItem(item)
+	})
+
+	ui.appProcessesMu.Lock()
+	if ui.appProcesses == nil {
+		ui.appProcesses = map[*container.TabItem]int{}
+	}
+	ui.appProcesses[item] = pid
+	ui.appProcessesMu.Unlock()
+
+	if ui.MainTabs.Selected() != item {
+		ui.Console.Processes.SetState(pid, core.ProcessSleeping)
+	}
+}
+
+// updateAppStates отмечает процесс активной вкладки выполняющимся, остальные - ожидающими
+func (ui *MixailOSUI) updateAppStates(selected *container.TabItem) {
+	ui.appProcessesMu.Lock()
+	defer ui.appProcessesMu.Unlock()
+	for item, pid := range ui.appProcesses {
+		if item == selected {
+			ui.Console.Processes.SetState(pid, core.ProcessRunning)
+		} else {
+			ui.Console.Processes.SetState(pid, core.ProcessSleeping)
+		}
+	}
+}
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "taskmanager", Name: "Диспетчер задач", Category: "Система", Order: 9},
+		icon:   theme.ListIcon,
+		launch: (*MixailOSUI).createTaskManagerTab,
+	})
+}
+
+// createTaskManagerTab создает вкладку с диспетчером задач
+func (ui *MixailOSUI) createTaskManagerTab() fyne.CanvasObject {
+	// processes читается виджетом и обновляется таймером, поэтому защищен мьютексом
+	var mu sync.Mutex
+	processes := ui.Console.Processes.List()
+	selectedPID := 0
+
+	// Статистика ресурсов среды выполнения
+	statsLabel := widget.NewLabel("")
+
+	// Таблица процессов
+	table := widget.NewTable(
+		func() (int, int) {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(processes), len(taskManagerColumns)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("Template Item")
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			mu.Lock()
+			// Проверяем границы массива
+			if id.Row < 0 || id.Row >= len(processes) {
+				mu.Unlock()
+				return
+			}
+			p := processes[id.Row]
+			mu.Unlock()
+
+			label := obj.(*widget.Label)
+			switch id.Col {
+			case 0:
+				label.SetText(strconv.Itoa(p.PID))
+			case 1:
+				label.SetText(p.Name)
+			case 2:
+				label.SetText(string(p.Kind))
+			case 3:
+				label.SetText(p.Owner)
+			case 4:
+				label.SetText(p.StartTime.Format("15:04:05"))
+			case 5:
+				label.SetText(string(p.State))
+			}
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	}
+	table.UpdateHeader = func(id widget.TableCellID, obj fyne.CanvasObject) {
+		if id.Col >= 0 && id.Col < len(taskManagerColumns) {
+			obj.(*widget.Label).SetText(taskManagerColumns[id.Col])
+		}
+	}
+	for col, width := range []float32{60, 180, 110, 100, 90, 120} {
+		table.SetColumnWidth(col, width)
+	}
+
+	table.OnSelected = func(id widget.TableCellID) {
+		mu.Lock()
+		defer mu.Unlock()
+		if id.Row >= 0 && id.Row < len(processes) {
+			selectedPID = processes[id.Row].PID
+		}
+	}
+
+	// refresh перечитывает таблицу процессов и статистику
+	refresh := func() {
+		list := ui.Console.Processes.List()
+		mu.Lock()
+		processes = list
+		mu.Unlock()
+
+		stats := core.ReadResourceStats()
+		statsLabel.SetText(fmt.Sprintf("Процессов: %d | Горутины: %d | Куча: %s из %s | Сборок мусора: %d",
+			len(list),
+			stats.Goroutines,
+			core.FormatSize(int64(stats.HeapAlloc)),
+			core.FormatSize(int64(stats.HeapSys)),
+			stats.NumGC))
+		table.Refresh()
+	}
+	refresh()
+	unsubscribe := ui.Config.Events.Subscribe(func(core.Event) { refresh() }, core.EventAppLaunched)
+
+	endButton := widget.NewButtonWithIcon("Завершить задачу", theme.CancelIcon(), func() {
+		mu.Lock()
+		pid := selectedPID
+		mu.Unlock()
+		if pid == 0 {
+			dialog.ShowInformation("Внимание", "Выберите процесс для завершения", ui.MainWindow)
+			return
+		}
+
+		if err := ui.Console.Processes.Kill(pid); err != nil {
+			dialog.ShowError(err, ui.MainWindow)
+			return
+		}
+		mu.Lock()
+		selectedPID = 0
+		mu.Unlock()
+		table.UnselectAll()
+		refresh()
+	})
+
+	// Живое обновление раз в секунду до закрытия главного окна
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		defer unsubscribe()
+		for {
+			select {
+			case <-ticker.C:
+				refresh()
+			case <-ui.done:
+				return
+			}
+		}
+	}()
+
+	// Размещение элементов в контейнере
+	return container.NewBorder(
+		container.NewVBox(
+			statsLabel,
+			container.NewHBox(
+				endButton,
+				widget.NewButtonWithIcon("Обновить", theme.ViewRefreshIcon(), refresh),
+			),
+		), // top
+		nil, // bottom
+		nil, // left
+		nil, // right
+		table,
+	)
+}
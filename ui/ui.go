@@ -1,18 +1,13 @@
 package ui
 
 import (
-	"fmt"
 	"image/color"
-	"path/filepath"
-	"strconv"
-	"strings"
+	"sync"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
@@ -27,11 +22,52 @@ type MixailOSUI struct {
 	Console     *core.Console
 	
 	// Интерфейсные компоненты
-	ConsoleOutput *widget.TextGrid
-	ConsoleInput  *widget.Entry
-	FileList      *widget.List
-	CurrentPath   *widget.Label
-	UsernameEntry *widget.Entry
+	MainTabs        *container.AppTabs
+	ConsoleOutput   *widget.TextGrid
+	ConsoleInput    *widget.Entry
+	Editor          *textEditor
+	ImageViewer     *imageViewer
+	BrowserTabs     *container.DocTabs
+	Browser         *core.Browser
+	BrowserStore    *core.BrowserStore
+	FileManager     *fileManager
+	// Apps - приложения в порядке вкладок и меню «Пуск»
+	Apps            []*launcherApp
+	Taskbar         *taskbar
+	Notifier        *notifier
+	// Desktop - рабочий стол с окнами; nil, если приложения открываются во вкладках
+	Desktop         *desktopView
+	UsernameEntry   *widget.Entry
+	
+	// fileWatcher следит за папкой, открытой в файловом менеджере
+	fileWatcherMu sync.Mutex
+	fileWatcher   *core.DirWatcher
+	// fileWatchFailed - папка, о сбое наблюдения за которой уже сообщено
+	fileWatchFailed string
+
+	// downloadsChanged обновляет список окна загрузок; nil, пока окно не открыто
+	downloadsMu      sync.Mutex
+	downloadsChanged func()
+
+	// appProcesses - PID процессов вкладок приложений
+	appProcessesMu sync.Mutex
+	appProcesses   map[*container.TabItem]int
+
+	// done закрывается при закрытии главного окна и останавливает фоновые обновления
+	done chan struct{}
+}
+
+func init() {
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "console", Name: "Консоль", Category: "Система", Order: 1},
+		icon:   theme.ComputerIcon,
+		launch: (*MixailOSUI).createConsoleTab,
+	})
+	RegisterApp(&builtinApp{
+		info:   core.AppInfo{ID: "settings", Name: "Настройки", Category: "Система", Order: 10},
+		icon:   theme.SettingsIcon,
+		launch: (*MixailOSUI).createSettingsTab,
+	})
 }
 
 // RunUI создает и запускает пользовательский интерфейс
@@ -51,7 +87,9 @@ func RunUI(config *core.Config, fs *core.FileSystem, console *core.Console) {
 		Config:     config,
 		FileSystem: fs,
 		Console:    console,
+		done:       make(chan struct{}),
 	}
+	mainWindow.SetOnClosed(func() { close(ui.done) })
 	
 	// Создание интерфейса
 	ui.setupUI()
@@ -62,17 +100,50 @@ func RunUI(config *core.Config, fs *core.FileSystem, console *core.Console) {
 
 // setupUI создает все элементы пользовательского интерфейса
 func (ui *MixailOSUI) setupUI() {
-	// Создание вкладок для разных функций
-	tabs := container.NewAppTabs(
-		container.NewTabItem("Консоль", ui.createConsoleTab()),
-		container.NewTabItem("Файлы", ui.createFileManagerTab()),
-		container.NewTabItem("Браузер", ui.createBrowserTab()),
-		container.NewTabItem("Калькулятор", ui.createCalculatorTab()),
-		container.NewTabItem("Настройки", ui.createSettingsTab()),
-	)
+	// Регистрация приложений; загрузки сообщают о завершении, даже если окно загрузок не открыто
+	ui.startApps()
+	ui.watchDownloads()
+	apps := make([]*container.TabItem, len(ui.Apps))
+	for i, app := range ui.Apps {
+		apps[i] = app.Item
+	}
+	ui.Console.OpenEditor = ui.openInEditor
+	ui.Console.LaunchApp = ui.launchApp
+	
+	// Всплывающие уведомления и панель задач с меню «Пуск», уведомлениями и часами
+	ui.Notifier = ui.newNotifier()
+	ui.Taskbar = ui.newTaskbar()
+	
+	// Приложения открываются окнами на рабочем столе или вкладками
+	var workspace fyne.CanvasObject
+	if ui.Config.DesktopMode {
+		ui.Desktop = ui.newDesktopView(apps)
+		workspace = ui.Desktop.content
+	} else {
+		tabs := container.NewAppTabs(apps...)
+		ui.MainTabs = tabs
+		
+		// Регистрируем открытые приложения в таблице процессов;
+		// активная вкладка выполняется, остальные ожидают.
+		// Приложение вкладки создается при первом ее выборе.
+		for _, item := range tabs.Items {
+			ui.registerAppProcess(item)
+		}
+		if selected := tabs.Selected(); selected != nil {
+			ui.launchItem(selected)
+		}
+		tabs.OnSelected = func(item *container.TabItem) {
+			ui.launchItem(item)
+			ui.updateAppStates(item)
+		}
+		workspace = tabs
+	}
 	
 	// Заголовок с именем пользователя
-	userLabel := widget.NewLabel("Пользователь: " + ui.Config.Username)
+	userLabel := widget.NewLabel("Пользователь: " + ui.Config.GetUsername())
+	ui.Config.Events.Subscribe(func(e core.Event) {
+		userLabel.SetText("Пользователь: " + e.User)
+	}, core.EventUserSwitched)
 	
 	// Создание менюбара
 	menuBar := ui.createMenuBar()
@@ -84,11 +155,11 @@ func (ui *MixailOSUI) setupUI() {
 		nil,     // left
 		nil,     // right
 		container.NewBorder(
-			userLabel, // top
-			nil,       // bottom
-			nil,       // left
-			nil,       // right
-			tabs,
+			userLabel,          // top
+			ui.Taskbar.content, // bottom
+			nil,                // left
+			nil,                // right
+			container.NewMax(workspace, ui.Notifier.layer),
 		),
 	))
 }
@@ -117,6 +188,9 @@ func (ui *MixailOSUI) createMenuBar() fyne.CanvasObject {
 	
 	// Пункт меню "Справка"
 	helpMenu := fyne.NewMenu("Справка",
+		fyne.NewMenuItem("Справка по командам", func() {
+			ui.openInBrowser("mixail://help")
+		}),
 		fyne.NewMenuItem("О программе", func() {
 			ui.showAboutDialog()
 		}),
@@ -136,6 +210,9 @@ func (ui *MixailOSUI) createMenuBar() fyne.CanvasObject {
 			ui.createNewFile()
 		}),
 		widget.NewToolbarSeparator(),
+		widget.NewToolbarAction(theme.HelpIcon(), func() {
+			ui.openInBrowser("mixail://help")
+		}),
 		widget.NewToolbarAction(theme.InfoIcon(), func() {
 			ui.showAboutDialog()
 		}),
@@ -184,428 +261,158 @@ func (ui *MixailOSUI) createConsoleTab() fyne.CanvasObject {
 	)
 }
 
-// createFileManagerTab создает вкладку с файловым менеджером
-func (ui *MixailOSUI) createFileManagerTab() fyne.CanvasObject {
-	// Получаем список файлов
-	files, err := ui.FileSystem.ListFiles()
-	if err != nil {
-		// Если не можем получить список файлов, создаем пустой список
-		files = []string{}
-	}
-	
-	// Сохраняем файлы в массив
-	fileNames := make([]string, len(files))
-	for i, file := range files {
-		fileNames[i] = file
-	}
+// createSettingsTab создает вкладку с настройками
+func (ui *MixailOSUI) createSettingsTab() fyne.CanvasObject {
+	// Поле для изменения имени пользователя
+	ui.UsernameEntry = widget.NewEntry()
+	ui.UsernameEntry.SetText(ui.Config.GetUsername())
 	
-	// Создаем метку с текущим путем
-	ui.CurrentPath = widget.NewLabel(ui.Config.GetCurrentDir())
+	usernameForm := widget.NewForm(
+		widget.NewFormItem("Имя пользователя:", ui.UsernameEntry),
+	)
 	
-	// Кнопка для перехода в родительскую директорию
-	upButton := widget.NewButtonWithIcon("Вверх", theme.NavigateBackIcon(), func() {
-		parent := filepath.Dir(ui.Config.GetCurrentDir())
-		if strings.HasPrefix(parent, ui.Config.RootDir) {
-			ui.FileSystem.ChangeDirectory("..")
-			ui.refreshFileList()
+	saveUsernameButton := widget.NewButton("Сохранить имя пользователя", func() {
+		if ui.UsernameEntry.Text != "" {
+			if err := ui.Config.ChangeUsername(ui.UsernameEntry.Text); err != nil {
+				dialog.ShowError(err, ui.MainWindow)
+				return
+			}
+			ui.notify("Настройки", "Имя пользователя изменено на "+ui.Config.GetUsername(), core.SeveritySuccess)
 		}
 	})
 	
-	// Кнопка для создания новой директории
-	mkdirButton := widget.NewButtonWithIcon("Новая папка", theme.FolderNewIcon(), func() {
-		// Диалог для создания директории
-		dirNameEntry := widget.NewEntry()
-		dirNameEntry.SetPlaceHolder("Имя новой папки")
-		
-		dialog.ShowForm("Создать новую папку", "Создать", "Отмена",
-			[]*widget.FormItem{
-				widget.NewFormItem("Имя:", dirNameEntry),
-			},
-			func(confirm bool) {
-				if confirm && dirNameEntry.Text != "" {
-					ui.FileSystem.CreateDirectory(dirNameEntry.Text)
-					ui.refreshFileList()
+	// Галерея обоев: встроенные, импортированные, цвета и градиенты
+	var choices []core.WallpaperChoice
+	gallery := widget.NewSelect(nil, nil)
+	fillGallery := func() {
+		choices = append(ui.FileSystem.Wallpapers(), core.WallpaperPresets...)
+		wallpaper := ui.Config.GetWallpaper()
+		current := false
+		for _, c := range choices {
+			current = current || c.Value == wallpaper
+		}
+		if !current {
+			// Свой цвет или градиент показывается в галерее, пока выбран
+			choices = append(choices, core.WallpaperChoice{Value: wallpaper, Title: core.WallpaperTitle(wallpaper)})
+		}
+		gallery.Options = nil
+		for _, c := range choices {
+			gallery.Options = append(gallery.Options, c.Title)
+		}
+		gallery.OnChanged = nil
+		gallery.SetSelected(core.WallpaperTitle(wallpaper))
+		gallery.OnChanged = func(selected string) {
+			for _, c := range choices {
+				if c.Title == selected {
+					ui.setWallpaper(c.Value)
 				}
-			},
-			ui.MainWindow,
-		)
-	})
-	
-	// Создаем список файлов с переменной fileNames из внешней области видимости
-	ui.FileList = widget.NewList(
-		func() int {
-			return len(fileNames)
-		},
-		func() fyne.CanvasObject {
-			return container.NewHBox(
-				widget.NewIcon(theme.FileIcon()),
-				widget.NewLabel("Template Item"),
-			)
-		},
-		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			// Проверяем границы массива
-			if id < 0 || id >= len(fileNames) {
-				return
-			}
-			
-			container := obj.(*fyne.Container)
-			label := container.Objects[1].(*widget.Label)
-			icon := container.Objects[0].(*widget.Icon)
-			
-			// Изменяем иконку в зависимости от типа (файл или директория)
-			if strings.Contains(fileNames[id], "(dir)") {
-				icon.SetResource(theme.FolderIcon())
-			} else {
-				icon.SetResource(theme.FileIcon())
 			}
-			
-			label.SetText(fileNames[id])
-		},
-	)
-	
-	ui.FileList.OnSelected = func(id widget.ListItemID) {
-		// Проверяем границы массива
-		if id < 0 || id >= len(fileNames) {
-			return
 		}
-		
-		fileName := fileNames[id]
-		// Извлекаем имя файла без типа
-		parts := strings.Split(fileName, " (")
-		name := parts[0]
-		
-		// Проверяем, директория ли это
-		if strings.Contains(fileName, "(dir)") {
-			ui.FileSystem.ChangeDirectory(name)
-			ui.refreshFileList()
-		} else if strings.HasSuffix(name, ".txt") {
-			// Читаем текстовый файл
-			content, err := ui.FileSystem.ReadTextFile(name)
+	}
+	fillGallery()
+	
+	// Кнопка для выбора файла обоев; файл копируется в системную папку обоев
+	importWallpaperButton := widget.NewButtonWithIcon("Импортировать...", theme.FolderOpenIcon(), func() {
+		// Диалог выбора файла
+		dialog.ShowFileOpen(func(uri fyne.URIReadCloser, err error) {
 			if err != nil {
 				dialog.ShowError(err, ui.MainWindow)
 				return
 			}
-			
-			// Показываем диалог с содержимым
-			textViewer := widget.NewMultiLineEntry()
-			textViewer.SetText(content)
-			textViewer.Disable() // Только для чтения
-			
-			dialog.ShowCustom("Файл: "+name, "Закрыть", container.NewScroll(textViewer), ui.MainWindow)
-		}
-	}
-	
-	// Toolbar для файлового менеджера
-	fileToolbar := container.NewHBox(
-		upButton,
-		mkdirButton,
-		widget.NewButtonWithIcon("Удалить", theme.DeleteIcon(), func() {
-			// Проверяем, выбран ли файл
-			if ui.FileList.Selected() < 0 || ui.FileList.Selected() >= len(fileNames) {
-				dialog.ShowInformation("Внимание", "Выберите файл для удаления", ui.MainWindow)
+			if uri == nil {
 				return
 			}
+			uri.Close()
 			
-			fileName := fileNames[ui.FileList.Selected()]
-			parts := strings.Split(fileName, " (")
-			name := parts[0]
-			
-			dialog.ShowConfirm("Подтверждение", "Вы уверены, что хотите удалить "+name+"?",
-				func(confirm bool) {
-					if confirm {
-						ui.FileSystem.DeleteFile(name)
-						ui.refreshFileList()
-					}
-				},
-				ui.MainWindow,
-			)
-		}),
-		widget.NewButtonWithIcon("Обновить", theme.ViewRefreshIcon(), func() {
-			ui.refreshFileList()
-		}),
-	)
-	
-	// Размещение элементов в контейнере
-	return container.NewBorder(
-		container.NewVBox(
-			ui.CurrentPath,
-			fileToolbar,
-		), // top
-		nil, // bottom
-		nil, // left
-		nil, // right
-		ui.FileList,
-	)
-}
-
-// createBrowserTab создает вкладку с браузером
-func (ui *MixailOSUI) createBrowserTab() fyne.CanvasObject {
-	// Поле для ввода URL
-	urlEntry := widget.NewEntry()
-	urlEntry.SetPlaceHolder("Введите URL...")
-	
-	// Кнопка для перехода по URL
-	goButton := widget.NewButtonWithIcon("Перейти", theme.NavigateNextIcon(), func() {
-		// Здесь будет код для загрузки страницы
-		// В текущей версии просто показываем заглушку
-		content := fmt.Sprintf("Просмотр страницы: %s\n\n--- Содержимое страницы ---\nЭто упрощенная версия браузера MixailOS.\nЗдесь вы увидите содержимое загруженной страницы.", urlEntry.Text)
-		
-		browserContent.SetText(content)
+			path, err := ui.FileSystem.ImportWallpaper(uri.URI().Path())
+			if err != nil {
+				dialog.ShowError(err, ui.MainWindow)
+				return
+			}
+			ui.setWallpaper(path)
+			fillGallery()
+		}, ui.MainWindow)
 	})
 	
-	// Поле для отображения содержимого страницы
-	browserContent := widget.NewMultiLineEntry()
-	browserContent.SetText("Добро пожаловать в браузер MixailOS!\nВведите URL в поле выше и нажмите 'Перейти' для начала работы.")
-	browserContent.Disable() // Только для чтения
-	
-	// URL bar
-	urlBar := container.NewBorder(
-		nil, // top
-		nil, // bottom
-		widget.NewLabel("URL:"), // left
-		goButton, // right
-		urlEntry,
-	)
-	
-	// Размещение элементов в контейнере
-	return container.NewBorder(
-		urlBar, // top
-		nil, // bottom
-		nil, // left
-		nil, // right
-		container.NewScroll(browserContent),
-	)
-}
-
-// createCalculatorTab создает вкладку с калькулятором
-func (ui *MixailOSUI) createCalculatorTab() fyne.CanvasObject {
-	// Поле для отображения результата
-	result := widget.NewEntry()
-	result.SetPlaceHolder("0")
-	
-	// Поле для истории вычислений
-	history := widget.NewMultiLineEntry()
-	history.SetText("Калькулятор MixailOS\nПоддерживаемые операции: +, -, *, /\n\n")
-	history.Disable() // Только для чтения
-	
-	// Создаем кнопки цифр и операций
-	var currentInput string
+	// Сплошной цвет и градиент из двух цветов
+	colorButton := widget.NewButtonWithIcon("Цвет...", theme.ColorPaletteIcon(), func() {
+		picker := dialog.NewColorPicker("Цвет рабочего стола", "", func(c color.Color) {
+			ui.setWallpaper(core.ColorWallpaper(c))
+			fillGallery()
+		}, ui.MainWindow)
+		picker.Advanced = true
+		picker.Show()
+	})
+	gradientButton := widget.NewButtonWithIcon("Градиент...", theme.ColorChromaticIcon(), func() {
+		top := dialog.NewColorPicker("Градиент", "Цвет вверху экрана", func(from color.Color) {
+			bottom := dialog.NewColorPicker("Градиент", "Цвет внизу экрана", func(to color.Color) {
+				ui.setWallpaper(core.GradientWallpaper(from, to))
+				fillGallery()
+			}, ui.MainWindow)
+			bottom.Advanced = true
+			bottom.Show()
+		}, ui.MainWindow)
+		top.Advanced = true
+		top.Show()
+	})
 	
-	// Функция для добавления символа к вводу
-	appendToInput := func(s string) {
-		currentInput += s
-		result.SetText(currentInput)
+	// Период смены обоев
+	var intervalTitles []string
+	for _, i := range core.WallpaperIntervals {
+		intervalTitles = append(intervalTitles, i.Title)
 	}
-	
-	// Функция для вычисления результата
-	calculate := func() {
-		// Если входная строка пуста, ничего не делаем
-		if currentInput == "" {
-			return
+	wallpaperInterval := widget.NewSelect(intervalTitles, nil)
+	wallpaperInterval.SetSelected(intervalTitles[0])
+	for _, i := range core.WallpaperIntervals {
+		if i.Minutes == ui.Config.GetWallpaperInterval() {
+			wallpaperInterval.SetSelected(i.Title)
 		}
-		
-		// Разбиваем входную строку на части (числа и операции)
-		parts := strings.Fields(currentInput)
-		if len(parts) < 3 || len(parts)%2 == 0 {
-			// Если формат не соответствует A op B op C...
-			historyText := history.Text
-			historyText += currentInput + " = Ошибка формата\n"
-			history.SetText(historyText)
-			currentInput = ""
-			result.SetText("Ошибка")
-			return
-		}
-		
-		// Выполняем операции последовательно слева направо
-		// Сначала обрабатываем умножение и деление
-		var val float64
-		var err error
-		
-		// Первое число
-		val, err = parseFloat(parts[0])
-		if err != nil {
-			historyText := history.Text
-			historyText += currentInput + " = Ошибка: " + err.Error() + "\n"
-			history.SetText(historyText)
-			currentInput = ""
-			result.SetText("Ошибка")
-			return
-		}
-		
-		// Первый проход: умножение и деление
-		for i := 1; i < len(parts); i += 2 {
-			op := parts[i]
-			if op != "*" && op != "/" {
-				continue
-			}
-			
-			// Получаем следующее число
-			next, err := parseFloat(parts[i+1])
-			if err != nil {
-				historyText := history.Text
-				historyText += currentInput + " = Ошибка: " + err.Error() + "\n"
-				history.SetText(historyText)
-				currentInput = ""
-				result.SetText("Ошибка")
-				return
-			}
-			
-			// Выполняем операцию
-			switch op {
-			case "*":
-				val *= next
-				// Обновляем части, чтобы пропустить этот результат при последующих операциях
-				parts[i+1] = fmt.Sprintf("%g", val)
-				parts[i] = " " // пометка для пропуска
-				parts[i-1] = " "
-			case "/":
-				if next == 0 {
-					historyText := history.Text
-					historyText += currentInput + " = Ошибка: деление на ноль\n"
-					history.SetText(historyText)
-					currentInput = ""
-					result.SetText("Ошибка")
-					return
+	}
+	wallpaperInterval.OnChanged = func(selected string) {
+		for _, i := range core.WallpaperIntervals {
+			if i.Title == selected {
+				if err := ui.Config.SetWallpaperInterval(i.Minutes); err != nil {
+					dialog.ShowError(err, ui.MainWindow)
 				}
-				val /= next
-				// Обновляем части, чтобы пропустить этот результат при последующих операциях
-				parts[i+1] = fmt.Sprintf("%g", val)
-				parts[i] = " " // пометка для пропуска
-				parts[i-1] = " "
 			}
 		}
-		
-		// Второй проход: сложение и вычитание
-		foundValue := false
-		val = 0
-		
-		for i := 0; i < len(parts); i++ {
-			if parts[i] == " " {
-				continue
-			}
-			
-			if !foundValue {
-				val, err = parseFloat(parts[i])
-				if err == nil {
-					foundValue = true
-				}
+	}
+	
+	// Режим отображения обоев
+	var modeTitles []string
+	for _, m := range core.WallpaperModes {
+		modeTitles = append(modeTitles, m.Title)
+	}
+	wallpaperMode := widget.NewSelect(modeTitles, nil)
+	wallpaperMode.SetSelected(modeTitles[0])
+	for _, m := range core.WallpaperModes {
+		if m.Mode == ui.Config.GetWallpaperMode() {
+			wallpaperMode.SetSelected(m.Title)
+		}
+	}
+	wallpaperMode.OnChanged = func(selected string) {
+		for _, m := range core.WallpaperModes {
+			if m.Title != selected {
 				continue
 			}
-			
-			if parts[i] == "+" || parts[i] == "-" {
-				op := parts[i]
-				if i+1 < len(parts) && parts[i+1] != " " {
-					next, err := parseFloat(parts[i+1])
-					if err != nil {
-						continue
-					}
-					
-					switch op {
-					case "+":
-						val += next
-					case "-":
-						val -= next
-					}
-				}
+			if err := ui.Config.ChangeWallpaperMode(m.Mode); err != nil {
+				dialog.ShowError(err, ui.MainWindow)
 			}
 		}
-		
-		// Форматируем результат и обновляем историю
-		resultStr := fmt.Sprintf("%g", val)
-		historyText := history.Text
-		historyText += currentInput + " = " + resultStr + "\n"
-		history.SetText(historyText)
-		
-		// Обновляем поле результата
-		result.SetText(resultStr)
-		currentInput = resultStr
 	}
 	
-	// Кнопки для цифр
-	digits := container.NewGridWithColumns(3,
-		widget.NewButton("7", func() { appendToInput("7") }),
-		widget.NewButton("8", func() { appendToInput("8") }),
-		widget.NewButton("9", func() { appendToInput("9") }),
-		widget.NewButton("4", func() { appendToInput("4") }),
-		widget.NewButton("5", func() { appendToInput("5") }),
-		widget.NewButton("6", func() { appendToInput("6") }),
-		widget.NewButton("1", func() { appendToInput("1") }),
-		widget.NewButton("2", func() { appendToInput("2") }),
-		widget.NewButton("3", func() { appendToInput("3") }),
-		widget.NewButton("0", func() { appendToInput("0") }),
-		widget.NewButton(".", func() { appendToInput(".") }),
-		widget.NewButton("=", calculate),
-	)
-	
-	// Кнопки для операций
-	operations := container.NewVBox(
-		widget.NewButton("+", func() { appendToInput(" + ") }),
-		widget.NewButton("-", func() { appendToInput(" - ") }),
-		widget.NewButton("*", func() { appendToInput(" * ") }),
-		widget.NewButton("/", func() { appendToInput(" / ") }),
-		widget.NewButton("C", func() {
-			currentInput = ""
-			result.SetText("0")
-		}),
-	)
-	
-	// Размещение элементов в контейнере
-	calcLayout := container.NewBorder(
-		container.NewVBox(
-			result,
-			container.NewScroll(history),
-		), // top
-		nil, // bottom
-		nil, // left
-		operations, // right
-		digits,
-	)
-	
-	return calcLayout
-}
-
-// parseFloat преобразует строку в число с плавающей точкой
-func parseFloat(s string) (float64, error) {
-	return strconv.ParseFloat(s, 64)
-}
-
-// createSettingsTab создает вкладку с настройками
-func (ui *MixailOSUI) createSettingsTab() fyne.CanvasObject {
-	// Поле для изменения имени пользователя
-	ui.UsernameEntry = widget.NewEntry()
-	ui.UsernameEntry.SetText(ui.Config.Username)
-	
-	usernameForm := widget.NewForm(
-		widget.NewFormItem("Имя пользователя:", ui.UsernameEntry),
-	)
-	
-	saveUsernameButton := widget.NewButton("Сохранить имя пользователя", func() {
-		if ui.UsernameEntry.Text != "" {
-			ui.Config.ChangeUsername(ui.UsernameEntry.Text)
-			dialog.ShowInformation("Успех", "Имя пользователя успешно изменено", ui.MainWindow)
+	// Рабочий стол с окнами вместо вкладок; применяется при следующем запуске
+	desktopMode := widget.NewCheck("Рабочий стол с окнами вместо вкладок", func(checked bool) {
+		if checked == ui.Config.DesktopMode {
+			return
 		}
+		if err := ui.Config.SetDesktopMode(checked); err != nil {
+			dialog.ShowError(err, ui.MainWindow)
+			return
+		}
+		ui.notify("Рабочий стол", "Изменение вступит в силу после перезапуска MixailOS", core.SeverityInfo)
 	})
-	
-	// Кнопка для изменения обоев
-	changeWallpaperButton := widget.NewButton("Изменить обои", func() {
-		// Диалог выбора файла
-		dialog.ShowFileOpen(func(uri fyne.URIReadCloser, err error) {
-			if err != nil {
-				dialog.ShowError(err, ui.MainWindow)
-				return
-			}
-			if uri == nil {
-				return
-			}
-			
-			// Получаем путь к файлу
-			path := uri.URI().Path()
-			
-			// Изменяем обои
-			ui.Config.ChangeWallpaper(path)
-			dialog.ShowInformation("Успех", "Обои успешно изменены", ui.MainWindow)
-		}, ui.MainWindow)
-	})
+	desktopMode.SetChecked(ui.Config.DesktopMode)
 	
 	// Размещение элементов в контейнере
 	return container.NewVBox(
@@ -613,10 +420,21 @@ func (ui *MixailOSUI) createSettingsTab() fyne.CanvasObject {
 		usernameForm,
 		saveUsernameButton,
 		widget.NewSeparator(),
-		changeWallpaperButton,
+		container.NewBorder(nil, nil, widget.NewLabel("Обои:"), nil, gallery),
+		container.NewHBox(importWallpaperButton, colorButton, gradientButton),
+		container.NewBorder(nil, nil, widget.NewLabel("Режим:"), nil, wallpaperMode),
+		container.NewBorder(nil, nil, widget.NewLabel("Смена обоев:"), nil, wallpaperInterval),
+		desktopMode,
 	)
 }
 
+// setWallpaper сохраняет обои в настройках; рабочий стол обновится по событию изменения настроек
+func (ui *MixailOSUI) setWallpaper(value string) {
+	if err := ui.Config.ChangeWallpaper(value); err != nil {
+		dialog.ShowError(err, ui.MainWindow)
+	}
+}
+
 // createNewFile создает новый текстовый файл
 func (ui *MixailOSUI) createNewFile() {
 	// Создаем диалог для создания файла
@@ -633,11 +451,10 @@ func (ui *MixailOSUI) createNewFile() {
 		},
 		func(confirm bool) {
 			if confirm && filenameEntry.Text != "" {
-				// Создаем файл
-				ui.FileSystem.CreateTextFile(filenameEntry.Text, contentEntry.Text)
-				
-				// Обновляем список файлов, если находимся в файловом менеджере
-				ui.refreshFileList()
+				// Файловый менеджер обновится по событию создания файла
+				if err := ui.FileSystem.CreateTextFile(filenameEntry.Text, contentEntry.Text); err != nil {
+					dialog.ShowError(err, ui.MainWindow)
+				}
 			}
 		},
 		ui.MainWindow,
@@ -649,98 +466,3 @@ func (ui *MixailOSUI) showAboutDialog() {
 	aboutText := "MixailOS v1.0\n\nЭмулятор операционной системы на Go.\nРазработано с использованием Fyne.io\n\n© 2024"
 	dialog.ShowInformation("О программе", aboutText, ui.MainWindow)
 }
-
-// refreshFileList обновляет список файлов в UI
-func (ui *MixailOSUI) refreshFileList() {
-	// Обновляем текст текущего пути
-	ui.CurrentPath.SetText(ui.Config.GetCurrentDir())
-	
-	// Получаем список файлов
-	files, err := ui.FileSystem.ListFiles()
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("Ошибка при получении списка файлов: %v", err), ui.MainWindow)
-		return
-	}
-	
-	// Сохраняем файлы в новый массив
-	fileNames := make([]string, len(files))
-	for i, file := range files {
-		fileNames[i] = file
-	}
-	
-	// Создаем новый список файлов
-	newFileList := widget.NewList(
-		func() int {
-			return len(fileNames)
-		},
-		func() fyne.CanvasObject {
-			return container.NewHBox(
-				widget.NewIcon(theme.FileIcon()),
-				widget.NewLabel("Template Item"),
-			)
-		},
-		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			// Проверяем границы массива
-			if id < 0 || id >= len(fileNames) {
-				return
-			}
-			
-			container := obj.(*fyne.Container)
-			label := container.Objects[1].(*widget.Label)
-			icon := container.Objects[0].(*widget.Icon)
-			
-			// Изменяем иконку в зависимости от типа (файл или директория)
-			if strings.Contains(fileNames[id], "(dir)") {
-				icon.SetResource(theme.FolderIcon())
-			} else {
-				icon.SetResource(theme.FileIcon())
-			}
-			
-			label.SetText(fileNames[id])
-		},
-	)
-	
-	newFileList.OnSelected = func(id widget.ListItemID) {
-		// Проверяем границы массива
-		if id < 0 || id >= len(fileNames) {
-			return
-		}
-		
-		fileName := fileNames[id]
-		// Извлекаем имя файла без типа
-		parts := strings.Split(fileName, " (")
-		name := parts[0]
-		
-		// Проверяем, директория ли это
-		if strings.Contains(fileName, "(dir)") {
-			ui.FileSystem.ChangeDirectory(name)
-			ui.refreshFileList()
-		} else if strings.HasSuffix(name, ".txt") {
-			// Читаем текстовый файл
-			content, err := ui.FileSystem.ReadTextFile(name)
-			if err != nil {
-				dialog.ShowError(err, ui.MainWindow)
-				return
-			}
-			
-			// Показываем диалог с содержимым
-			textViewer := widget.NewMultiLineEntry()
-			textViewer.SetText(content)
-			textViewer.Disable() // Только для чтения
-			
-			dialog.ShowCustom("Файл: "+name, "Закрыть", container.NewScroll(textViewer), ui.MainWindow)
-		}
-	}
-	
-	// Находим контейнер, содержащий старый список файлов
-	tabs := ui.MainTabs.Objects[0].(*container.AppTabs)
-	fileManagerTab := tabs.Items[1].Content.(*fyne.Container)
-	fileListContainer := fileManagerTab.Objects[0].(*fyne.Container)
-	
-	// Удаляем старый список файлов
-	fileListContainer.Objects[0] = newFileList
-	ui.FileList = newFileList
-	
-	// Обновляем UI
-	fileListContainer.Refresh()
-} 
\ No newline at end of file
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	// windowTitleHeight - высота заголовка внутреннего окна
+	windowTitleHeight = 36
+	// windowResizeHandle - размер уголка для изменения размера окна
+	windowResizeHandle = 16
+)
+
+// windowMinSize - минимальный размер внутреннего окна
+var windowMinSize = fyne.NewSize(240, 160)
+
+// touchArea - область, реагирующая на нажатие, двойное нажатие и перетаскивание.
+// Используется для заголовков окон, уголков изменения размера и значков рабочего стола.
+type touchArea struct {
+	widget.BaseWidget
+	content     fyne.CanvasObject
+	onTap       func()
+	onDoubleTap func()
+	onDrag      func(dx, dy float32)
+	onDragEnd   func()
+}
+
+// newTouchArea создает область поверх содержимого content
+func newTouchArea(content fyne.CanvasObject) *touchArea {
+	t := &touchArea{content: content}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+// CreateRenderer возвращает рендерер, рисующий содержимое области
+func (t *touchArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.content)
+}
+
+// Tapped вызывается при нажатии
+func (t *touchArea) Tapped(*fyne.PointEvent) {
+	if t.onTap != nil {
+		t.onTap()
+	}
+}
+
+// DoubleTapped вызывается при двойном нажатии
+func (t *touchArea) DoubleTapped(*fyne.PointEvent) {
+	if t.onDoubleTap != nil {
+		t.onDoubleTap()
+	}
+}
+
+// Dragged вызывается при перетаскивании
+func (t *touchArea) Dragged(ev *fyne.DragEvent) {
+	if t.onDrag != nil {
+		t.onDrag(ev.Dragged.DX, ev.Dragged.DY)
+	}
+}
+
+// DragEnd вызывается по окончании перетаскивания
+func (t *touchArea) DragEnd() {
+	if t.onDragEnd != nil {
+		t.onDragEnd()
+	}
+}
+
+// innerWindow - окно приложения внутри рабочего стола: заголовок с кнопками
+// свертывания, развертывания и закрытия, содержимое и уголок изменения размера
+type innerWindow struct {
+	desktop *desktopView
+	item    *container.TabItem
+	frame   *fyne.Container
+	title   *canvas.Text
+	pid     int
+
+	minimized bool
+	maximized bool
+	// restorePos и restoreSize - положение и размер окна до развертывания
+	restorePos  fyne.Position
+	restoreSize fyne.Size
+}
+
+// newInnerWindow создает окно для приложения item
+func newInnerWindow(d *desktopView, item *container.TabItem) *innerWindow {
+	w := &innerWindow{desktop: d, item: item}
+
+	w.title = canvas.NewText(item.Text, theme.ForegroundColor())
+	w.title.TextStyle = fyne.TextStyle{Bold: true}
+	titleArea := newTouchArea(container.NewBorder(nil, nil, widget.NewIcon(item.Icon), nil, container.NewCenter(w.title)))
+	titleArea.onTap = func() { d.raise(w) }
+	titleArea.onDoubleTap = w.toggleMaximize
+	titleArea.onDrag = func(dx, dy float32) {
+		if w.maximized {
+			return
+		}
+		w.frame.Move(w.frame.Position().Add(fyne.NewPos(dx, dy)))
+	}
+
+	buttons := container.NewHBox(
+		widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() { d.minimize(w) }),
+		widget.NewButtonWithIcon("", theme.ViewFullScreenIcon(), w.toggleMaximize),
+		widget.NewButtonWithIcon("", theme.CancelIcon(), func() { d.close(w) }),
+	)
+	titleBar := container.NewBorder(nil, nil, nil, buttons, titleArea)
+
+	resize := newTouchArea(widget.NewIcon(theme.MoreVerticalIcon()))
+	resize.onDrag = func(dx, dy float32) {
+		if w.maximized {
+			return
+		}
+		size := w.frame.Size().Add(fyne.NewSize(dx, dy))
+		w.frame.Resize(size.Max(windowMinSize))
+	}
+	resizeRow := container.NewBorder(nil, nil, nil, container.NewGridWrap(fyne.NewSize(windowResizeHandle, windowResizeHandle), resize))
+
+	background := canvas.NewRectangle(theme.BackgroundColor())
+	background.StrokeColor = theme.PrimaryColor()
+	background.StrokeWidth = 1
+	w.frame = container.NewMax(
+		background,
+		container.NewBorder(
+			container.NewVBox(titleBar, widget.NewSeparator()), // top
+			resizeRow, // bottom
+			nil,       // left
+			nil,       // right
+			item.Content,
+		),
+	)
+	return w
+}
+
+// toggleMaximize разворачивает окно на весь рабочий стол или восстанавливает размер
+func (w *innerWindow) toggleMaximize() {
+	if w.maximized {
+		w.maximized = false
+		w.frame.Move(w.restorePos)
+		w.frame.Resize(w.restoreSize)
+		return
+	}
+	w.maximized = true
+	w.restorePos, w.restoreSize = w.frame.Position(), w.frame.Size()
+	w.frame.Move(fyne.NewPos(0, 0))
+	w.frame.Resize(w.desktop.layer.Size())
+	w.desktop.raise(w)
+}
+
+// setActive выделяет заголовок активного окна
+func (w *innerWindow) setActive(active bool) {
+	if active {
+		w.title.Color = theme.ForegroundColor()
+	} else {
+		w.title.Color = theme.DisabledColor()
+	}
+	w.title.Refresh()
+}